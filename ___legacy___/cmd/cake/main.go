@@ -1,18 +1,51 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/jrengmusic/cake/internal/app"
+	"github.com/jrengmusic/cake/internal/cli"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	application := app.NewApplication()
+	// Headless subcommands (generate, build, clean, clean-all) bypass the TUI entirely
+	if cli.IsSubcommand(os.Args[1:]) {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
+
+	opts, argsErr := cli.ParseTUIArgs(os.Args[1:])
+	if argsErr == flag.ErrHelp {
+		os.Exit(cli.ExitSuccess)
+	}
+	if argsErr != nil {
+		fmt.Fprintf(os.Stderr, "cake: %v\n", argsErr)
+		os.Exit(cli.ExitUsage)
+	}
+
+	application := app.NewApplication(opts.ProjectRoot)
 	program := tea.NewProgram(application, tea.WithAltScreen())
 
-	if _, err := program.Run(); err != nil {
+	// Bubble Tea quits on SIGINT and SIGTERM; a closed terminal (SIGHUP) quits the same way
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		<-hangup
+		program.Quit()
+	}()
+
+	_, err := program.Run()
+	// A build, test or run still going when cake quits takes its whole process tree with it
+	utils.TerminateProcessTrees()
+	// Write out the last line still held back for progress updates
+	ui.GetBuffer().SpoolTo(nil)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
@@ -5,6 +5,7 @@ import (
 	"github.com/jrengmusic/cake/internal/config"
 	"github.com/jrengmusic/cake/internal/state"
 	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
 	"context"
 	"path/filepath"
 	"time"
@@ -56,12 +57,57 @@ type Application struct {
 
 	pendingOperation   string // Track operation to execute after confirmation
 	buildAfterGenerate bool   // Chain build after generate when project not yet generated
+	runAfterBuild      bool   // Chain run after a successful build (Run row)
+	debugAfterBuild    bool   // Chain the debugger after a successful build (Debug row)
+	runArgsEditing     bool   // Run arguments prompt is open in the menu
+	runArgsInput       string // Run arguments being typed
+
+	watchActive      bool      // Watch mode: rebuild whenever the sources settle after a change
+	watchGeneration  int       // Bumped per watch session so ticks of a stopped session are dropped
+	watchFingerprint uint64    // utils.SourceFingerprint of the last poll
+	watchChangedAt   time.Time // When the sources last changed
+	watchPending     bool      // A change is waiting to be built
+	watchCancelled   bool      // The running cycle was cancelled because the sources changed again
+	watchCycle       int       // Number of the current or last cycle
+	watchCycleStart  time.Time // When the current cycle started
 
 	lastActivityTime time.Time // Track last user activity for lazy auto-scan
 
-	vsEnv []string // Captured Visual Studio environment (Windows only)
+	vsEnv    []string         // Child environment: captured Visual Studio environment (Windows only) + .cake.toml env
+	settings config.Effective // Defaults, user config and .cake.toml merged
 
 	spinnerFrame int // Current braille spinner animation frame index
+
+	testResults              []utils.CTestCase // Per-test outcomes of the last ctest run
+	testFilter               string            // ctest -R regex; empty = all tests
+	testRerun                bool              // Last run was --rerun-failed
+	testFilterEditing        bool              // Filter prompt is open in the test results view
+	testFilterInput          string            // Filter being typed
+	consoleBackToTestResults bool              // Esc in console returns to the test results view
+
+	diagnostics              []utils.Diagnostic // Errors and warnings of the last build
+	diagnosticsErrorsOnly    bool               // Error list hides warnings
+	diagnosticsBuildDir      string             // Build tree the diagnostics came from; relative paths resolve against it
+	consoleBackToDiagnostics bool               // Esc in console opens the error list
+
+	historyEntry   *config.HistoryEntry  // Operation being recorded; nil when the running operation is not logged
+	historyEntries []config.HistoryEntry // Past operations of this project, newest first
+	historyLog     *ui.OutputBuffer      // Saved log shown in the console; nil = live output
+
+	cacheEntries      []state.CacheEntry // CMakeCache.txt of the selected build tree
+	cacheEdits        map[string]string  // Pending values by entry name, applied as -D overrides
+	cacheEditsDir     string             // Build tree cacheEdits belong to; kept across a failed reconfigure
+	cacheApplying     bool               // A reconfigure with cacheEdits is running; they are cleared when it succeeds
+	cacheSearch       string             // Case-insensitive name filter
+	cacheShowAdvanced bool               // Show entries marked advanced
+	cacheInputMode    int                // cacheInputNone, cacheInputSearch or cacheInputEdit
+	cacheInput        string             // Prompt text being typed
+
+	argsGenerator string // Generator whose extra cmake arguments are being edited
+	argsList      string // Name of the argument list being edited; empty until the generator has one
+	argsInputMode int    // argsInputNone, argsInputDefinition, argsInputArg or argsInputListName
+	argsInput     string // Prompt text being typed
+	argsEditing   bool   // Prompt replaces the selected row instead of adding one
 }
 
 func (a *Application) registerKeyHandlers() {
@@ -71,9 +117,24 @@ func (a *Application) registerKeyHandlers() {
 	a.keyDispatcher.Register(ModePreferences, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return app.handlePreferencesKeyPress(msg)
 	})
+	a.keyDispatcher.Register(ModeCache, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+		return app.handleCacheKeyPress(msg)
+	})
+	a.keyDispatcher.Register(ModeCMakeArgs, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+		return app.handleCMakeArgsKeyPress(msg)
+	})
 	a.keyDispatcher.Register(ModeConsole, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return app.handleOperationKeyPress(msg)
 	})
+	a.keyDispatcher.Register(ModeTestResults, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+		return app.handleTestResultsKeyPress(msg)
+	})
+	a.keyDispatcher.Register(ModeDiagnostics, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+		return app.handleDiagnosticsKeyPress(msg)
+	})
+	a.keyDispatcher.Register(ModeHistory, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+		return app.handleHistoryKeyPress(msg)
+	})
 	a.keyDispatcher.Register(ModeInvalidProject, func(app *Application, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return app.handleInvalidProjectKeyPress(msg)
 	})
@@ -123,6 +184,12 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case AutoScanTickMsg:
 		return a.handleAutoScanTick()
 
+	case WatchTickMsg:
+		return a.handleWatchTick(msg)
+
+	case WatchScanMsg:
+		return a.handleWatchScan(msg)
+
 	case GenerateCompleteMsg:
 		if a.cancelContext != nil {
 			a.cancelContext()
@@ -133,12 +200,17 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.killTree = nil
 		}
 		a.asyncState.End()
+		a.finishCacheEdits(msg.Success && !a.asyncState.IsAborted())
 		if a.asyncState.IsAborted() {
 			a.asyncState.ClearAborted()
+			a.recordHistory(config.HistoryResultAborted, 0)
 			a.buildAfterGenerate = false
+			a.runAfterBuild = false
+			a.debugAfterBuild = false
 			a.footerHint = "Operation aborted"
 			return a, nil
 		}
+		a.recordHistory(historyResult(msg.Success), msg.ExitCode)
 		a.projectState.ForceRefresh()
 		a.menuItems = a.GenerateMenu()
 		if msg.Success {
@@ -151,7 +223,12 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.footerHint = GetFooterMessageText(MessageOperationComplete)
 		} else {
 			a.buildAfterGenerate = false
+			a.runAfterBuild = false
+			a.debugAfterBuild = false
 			a.footerHint = "Generate failed: " + msg.Error
+			if len(a.cacheEdits) > 0 {
+				a.footerHint += ". Cache edits kept, press e to fix them."
+			}
 		}
 		return a, nil
 
@@ -167,23 +244,59 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.asyncState.End()
 		if a.asyncState.IsAborted() {
 			a.asyncState.ClearAborted()
+			a.recordHistory(config.HistoryResultAborted, 0)
+			a.runAfterBuild = false
+			a.debugAfterBuild = false
 			a.footerHint = "Operation aborted"
 			return a, nil
 		}
+		a.diagnostics = msg.Diagnostics
+		a.diagnosticsErrorsOnly = false
+		a.diagnosticsBuildDir = a.projectState.GetBuildPath()
+		if a.watchActive {
+			return a.handleWatchBuildComplete(msg)
+		}
+		a.recordHistory(historyResult(msg.Success), msg.ExitCode)
 		if msg.Success {
-			a.footerHint = GetFooterMessageText(MessageOperationComplete)
+			// Run or Debug was requested: the build it needed is done
+			if a.runAfterBuild {
+				a.runAfterBuild = false
+				return a.startRunProcess()
+			}
+			if a.debugAfterBuild {
+				a.debugAfterBuild = false
+				return a.startDebugger()
+			}
+			if !a.offerDiagnostics("") {
+				a.footerHint = GetFooterMessageText(MessageOperationComplete)
+			}
 		} else {
-			a.footerHint = "Build failed: " + msg.Error
+			a.runAfterBuild = false
+			a.debugAfterBuild = false
+			if !a.offerDiagnostics("Build failed: ") {
+				a.footerHint = "Build failed: " + msg.Error
+			}
 		}
 		return a, nil
 
+	case TestCompleteMsg:
+		return a.handleTestComplete(msg)
+
+	case RunCompleteMsg:
+		return a.handleRunComplete(msg)
+
+	case DebugCompleteMsg:
+		return a.handleDebugComplete(msg)
+
 	case CleanCompleteMsg:
 		a.asyncState.End()
 		if a.asyncState.IsAborted() {
 			a.asyncState.ClearAborted()
+			a.recordHistory(config.HistoryResultAborted, 0)
 			a.footerHint = "Operation aborted"
 			return a, nil
 		}
+		a.recordHistory(historyResult(msg.Success), 0)
 		a.projectState.ForceRefresh()
 		a.menuItems = a.GenerateMenu()
 		if msg.Success {
@@ -197,9 +310,11 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.asyncState.End()
 		if a.asyncState.IsAborted() {
 			a.asyncState.ClearAborted()
+			a.recordHistory(config.HistoryResultAborted, 0)
 			a.footerHint = "Operation aborted"
 			return a, nil
 		}
+		a.recordHistory(historyResult(msg.Success), 0)
 		a.projectState.ForceRefresh()
 		a.menuItems = a.GenerateMenu()
 		if msg.Success {
@@ -231,9 +346,11 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.asyncState.End()
 		if a.asyncState.IsAborted() {
 			a.asyncState.ClearAborted()
+			a.recordHistory(config.HistoryResultAborted, 0)
 			a.footerHint = "Operation aborted"
 			return a, nil
 		}
+		a.recordHistory(historyResult(msg.Success), msg.ExitCode)
 		a.projectState.ForceRefresh()
 		a.menuItems = a.GenerateMenu()
 		if msg.Success {
@@ -244,10 +361,12 @@ func (a *Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case OpenEditorCompleteMsg:
-		a.asyncState.End()
-		if msg.Success {
+		switch {
+		case msg.Success && msg.Detached:
+			a.footerHint = "Opened in " + msg.Editor
+		case msg.Success:
 			a.footerHint = "Editor closed"
-		} else {
+		default:
 			a.footerHint = "Failed to open editor: " + msg.Error
 		}
 		return a, nil
@@ -285,6 +404,16 @@ func (a *Application) renderModeContent() string {
 		return a.renderMenuWithBanner()
 	case ModePreferences:
 		return a.renderPreferencesWithBanner()
+	case ModeTestResults:
+		return a.renderTestResults()
+	case ModeCache:
+		return a.renderCacheView()
+	case ModeCMakeArgs:
+		return a.renderCMakeArgsView()
+	case ModeDiagnostics:
+		return a.renderDiagnosticsView()
+	case ModeHistory:
+		return a.renderHistory()
 	default:
 		return a.renderMenuWithBanner()
 	}
@@ -2,7 +2,10 @@ package app
 
 import (
 	"fmt"
+	"path/filepath"
 
+	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/config"
 	"github.com/jrengmusic/cake/internal/ui"
 	"github.com/jrengmusic/cake/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
@@ -57,18 +60,13 @@ func (a *Application) GetVisiblePreferenceRows() []ui.MenuRow {
 		return []ui.MenuRow{}
 	}
 
-	autoScanValue := "OFF"
-	if a.config.IsAutoScanEnabled() {
-		autoScanValue = "ON"
-	}
-
 	return []ui.MenuRow{
 		{
 			ID:           "prefs_auto_scan",
 			Shortcut:     "",
 			Emoji:        "🔄",
 			Label:        "Auto-scan",
-			Value:        autoScanValue,
+			Value:        onOff(a.config.IsAutoScanEnabled()),
 			Visible:      true,
 			IsAction:     false,
 			IsSelectable: true,
@@ -96,7 +94,47 @@ func (a *Application) GetVisiblePreferenceRows() []ui.MenuRow {
 			IsSelectable: true,
 			Hint:         "Cycle through available themes",
 		},
+		{
+			ID:           "prefs_console_colors",
+			Shortcut:     "",
+			Emoji:        "🌈",
+			Label:        "Console Colors",
+			Value:        a.config.ConsoleColors(),
+			Visible:      true,
+			IsAction:     false,
+			IsSelectable: true,
+			Hint:         "Keep the colors compilers and CMake write (tool), or color lines by type from the theme",
+		},
+		{
+			ID:           "prefs_compile_commands",
+			Shortcut:     "",
+			Emoji:        "📒",
+			Label:        "Compile Commands",
+			Value:        onOff(a.config.ExportCompileCommands()),
+			Visible:      true,
+			IsAction:     false,
+			IsSelectable: true,
+			Hint:         "Export compile_commands.json and link the selected build's into the project root",
+		},
+		{
+			ID:           "prefs_pty",
+			Shortcut:     "",
+			Emoji:        "🖥️",
+			Label:        "Build on PTY",
+			Value:        onOff(a.config.PTY()),
+			Visible:      true,
+			IsAction:     false,
+			IsSelectable: true,
+			Hint:         "Run builds on a pseudo-terminal: native ninja progress and compiler colors (Linux, macOS)",
+		},
+	}
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "ON"
 	}
+	return "OFF"
 }
 
 // ToggleRowAtIndex handles menu row toggle/action at given VISIBLE index
@@ -141,6 +179,50 @@ func (a *Application) applyNextTheme() bool {
 	return true
 }
 
+// toggleCompileCommands flips the user setting; a .cake.toml value keeps deciding for this project
+func (a *Application) toggleCompileCommands() bool {
+	enabled := !a.config.ExportCompileCommands()
+	if err := a.config.SetExportCompileCommands(enabled); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+		return false
+	}
+	if a.settings.Source(config.SettingCompileCommands) == config.SourceProject {
+		a.footerHint = "Saved; " + internal.ProjectConfigFile + " still decides compile_commands for this project"
+		return true
+	}
+	a.settings.CompileCommands = enabled
+	if enabled && a.settings.Sources != nil {
+		a.settings.Sources[config.SettingCompileCommands] = config.SourceUser
+	} else {
+		delete(a.settings.Sources, config.SettingCompileCommands)
+	}
+	a.syncCompileCommands()
+	return true
+}
+
+// togglePTY flips the user setting; a .cake.toml value keeps deciding for this project
+func (a *Application) togglePTY() bool {
+	enabled := !a.config.PTY()
+	if err := a.config.SetPTY(enabled); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+		return false
+	}
+	if a.settings.Source(config.SettingPTY) == config.SourceProject {
+		a.footerHint = "Saved; " + internal.ProjectConfigFile + " still decides pty for this project"
+		return true
+	}
+	a.settings.PTY = enabled
+	if enabled && a.settings.Sources != nil {
+		a.settings.Sources[config.SettingPTY] = config.SourceUser
+	} else {
+		delete(a.settings.Sources, config.SettingPTY)
+	}
+	if enabled && !utils.PTYSupported {
+		a.footerHint = "Saved; builds use pipes on this platform"
+	}
+	return true
+}
+
 func (a *Application) TogglePreferenceAtIndex(visibleIndex int) bool {
 	visibleRows := a.GetVisiblePreferenceRows()
 	if visibleIndex < 0 || visibleIndex >= len(visibleRows) {
@@ -157,6 +239,20 @@ func (a *Application) TogglePreferenceAtIndex(visibleIndex int) bool {
 		return true
 	case "prefs_theme":
 		return a.applyNextTheme()
+	case "prefs_console_colors":
+		mode := config.ConsoleColorsTheme
+		if a.config.ConsoleColors() == config.ConsoleColorsTheme {
+			mode = config.ConsoleColorsTool
+		}
+		if err := a.config.SetConsoleColors(mode); err != nil {
+			a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+			return false
+		}
+		return true
+	case "prefs_compile_commands":
+		return a.toggleCompileCommands()
+	case "prefs_pty":
+		return a.togglePTY()
 	case "prefs_interval":
 		return true
 	}
@@ -199,7 +295,7 @@ func (a *Application) executeRowActionBuild() (bool, tea.Cmd) {
 func (a *Application) showCleanConfirmDialog() {
 	a.showConfirmationDialog(
 		"Clean Build Directory",
-		"Remove all build artifacts for "+a.projectState.SelectedProject+"?",
+		"Remove all build artifacts for "+filepath.Base(a.projectState.GetBuildPath())+"?",
 		"Yes", "No", "clean",
 	)
 }
@@ -212,11 +308,27 @@ func (a *Application) showCleanAllConfirmDialog() {
 	)
 }
 
+// saveLastTarget persists the selected target for this project; failure only shows in the footer
+func (a *Application) saveLastTarget() {
+	if a.config == nil {
+		return
+	}
+	if err := a.config.SetLastTarget(a.projectState.WorkingDirectory, a.projectState.SelectedTarget); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+	}
+}
+
 // executeRowAction executes the action associated with a menu row
 func (a *Application) executeRowAction(rowID string) (bool, tea.Cmd) {
 	switch rowID {
 	case "project":
 		a.projectState.CycleToNextProject()
+		a.syncCompileCommands()
+		a.menuItems = a.GenerateMenu()
+		return true, nil
+	case "preset":
+		a.projectState.CyclePreset()
+		a.syncCompileCommands()
 		a.menuItems = a.GenerateMenu()
 		return true, nil
 	case "regenerate":
@@ -228,7 +340,7 @@ func (a *Application) executeRowAction(rowID string) (bool, tea.Cmd) {
 		a.showCleanAllConfirmDialog()
 		return true, nil
 	case "openIde":
-		if utils.IsGeneratorIDE(a.projectState.SelectedProject) {
+		if utils.IsGeneratorIDE(a.projectState.ActiveGenerator()) {
 			_, cmd := a.startOpenIDEOperation()
 			return true, cmd
 		}
@@ -236,10 +348,30 @@ func (a *Application) executeRowAction(rowID string) (bool, tea.Cmd) {
 		return true, cmd
 	case "configuration":
 		a.projectState.CycleConfiguration()
+		a.syncCompileCommands()
+		a.menuItems = a.GenerateMenu()
+		return true, nil
+	case "variant":
+		a.projectState.CycleVariant()
+		a.syncCompileCommands()
+		a.menuItems = a.GenerateMenu()
+		return true, nil
+	case "target":
+		a.projectState.CycleTarget()
+		a.saveLastTarget()
 		a.menuItems = a.GenerateMenu()
 		return true, nil
 	case "build":
 		return a.executeRowActionBuild()
+	case "test":
+		_, cmd := a.startTestOperation(false)
+		return true, cmd
+	case "run":
+		a.runAfterBuild = true
+		return a.executeRowActionBuild()
+	case "debug":
+		a.debugAfterBuild = true
+		return a.executeRowActionBuild()
 	}
 	return false, nil
 }
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jrengmusic/cake/internal/state"
+	"github.com/jrengmusic/cake/internal/ui"
+)
+
+// Cache browser prompt states
+const (
+	cacheInputNone = iota
+	cacheInputSearch
+	cacheInputEdit
+)
+
+// enterCacheMode loads the selected build tree's CMakeCache.txt into the cache browser
+func (a *Application) enterCacheMode() {
+	if !a.projectState.CanBuild() {
+		a.footerHint = "No CMakeCache.txt yet. Generate first."
+		return
+	}
+	entries, err := state.ReadCMakeCache(a.projectState.GetBuildPath())
+	if err != nil {
+		a.footerHint = fmt.Sprintf("Failed to read cache: %v", err)
+		return
+	}
+
+	a.cacheEntries = entries
+	// Edits kept from a failed reconfigure of this tree come back for fixing
+	if a.cacheEdits == nil || a.cacheEditsDir != a.projectState.GetBuildPath() {
+		a.cacheEdits = map[string]string{}
+		a.cacheEditsDir = a.projectState.GetBuildPath()
+	}
+	a.cacheSearch = ""
+	a.cacheInputMode = cacheInputNone
+	a.mode = ModeCache
+	a.selectedIndex = 0
+	a.footerHint = ""
+}
+
+// visibleCacheEntries returns the entries matching the search, hiding advanced ones unless shown
+func (a *Application) visibleCacheEntries() []state.CacheEntry {
+	search := strings.ToLower(a.cacheSearch)
+	var visible []state.CacheEntry
+	for _, entry := range a.cacheEntries {
+		if entry.Advanced && !a.cacheShowAdvanced {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(entry.Name), search) {
+			continue
+		}
+		visible = append(visible, entry)
+	}
+	return visible
+}
+
+// selectedCacheEntry returns the highlighted entry; ok is false when the list is empty
+func (a *Application) selectedCacheEntry() (state.CacheEntry, bool) {
+	visible := a.visibleCacheEntries()
+	if a.selectedIndex < 0 || a.selectedIndex >= len(visible) {
+		return state.CacheEntry{}, false
+	}
+	return visible[a.selectedIndex], true
+}
+
+// cacheValue returns the pending value of entry, or its cached one
+func (a *Application) cacheValue(entry state.CacheEntry) string {
+	if value, ok := a.cacheEdits[entry.Name]; ok {
+		return value
+	}
+	return entry.Value
+}
+
+// setCacheValue records a pending edit; setting the cached value back drops it
+func (a *Application) setCacheValue(entry state.CacheEntry, value string) {
+	if value == entry.Value {
+		delete(a.cacheEdits, entry.Name)
+		return
+	}
+	a.cacheEdits[entry.Name] = value
+}
+
+// activateCacheEntry toggles BOOLs, cycles entries with allowed values and opens the editor for the rest
+func (a *Application) activateCacheEntry() {
+	entry, ok := a.selectedCacheEntry()
+	if !ok {
+		return
+	}
+	value := a.cacheValue(entry)
+
+	switch {
+	case entry.Type == state.CacheTypeBool:
+		if state.IsCMakeTrue(value) {
+			a.setCacheValue(entry, "OFF")
+		} else {
+			a.setCacheValue(entry, "ON")
+		}
+	case len(entry.Strings) > 0:
+		next := entry.Strings[0]
+		for i, allowed := range entry.Strings {
+			if allowed == value {
+				next = entry.Strings[(i+1)%len(entry.Strings)]
+				break
+			}
+		}
+		a.setCacheValue(entry, next)
+	default:
+		a.cacheInputMode = cacheInputEdit
+		a.cacheInput = value
+	}
+}
+
+// cacheDefinitions returns the pending edits as -D bodies, sorted by name
+func (a *Application) cacheDefinitions() []string {
+	var definitions []string
+	for _, entry := range a.cacheEntries {
+		if value, ok := a.cacheEdits[entry.Name]; ok {
+			definitions = append(definitions, state.CacheDefinition(entry, value))
+		}
+	}
+	sort.Strings(definitions)
+	return definitions
+}
+
+// applyCacheEdits reconfigures the build tree with the pending edits as -D overrides
+func (a *Application) applyCacheEdits() (tea.Model, tea.Cmd) {
+	definitions := a.cacheDefinitions()
+	if len(definitions) == 0 {
+		a.footerHint = "No pending changes"
+		return a, nil
+	}
+	a.cacheApplying = true
+	return a.startConfigureOperation(definitions)
+}
+
+// finishCacheEdits drops the applied cache edits once their reconfigure succeeded; a failed
+// or aborted reconfigure keeps them, so the cache browser reopens with them to fix
+func (a *Application) finishCacheEdits(applied bool) {
+	if a.cacheApplying && applied {
+		a.cacheEdits = nil
+	}
+	a.cacheApplying = false
+}
+
+func (a *Application) clampCacheSelection() {
+	visibleCount := len(a.visibleCacheEntries())
+	a.selectedIndex = clampToRange(a.selectedIndex, 0, visibleCount-1)
+	if a.selectedIndex < 0 {
+		a.selectedIndex = 0
+	}
+}
+
+func (a *Application) handleCacheKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.lastActivityTime = time.Now()
+	if a.cacheInputMode != cacheInputNone {
+		return a.handleCacheInputKeyPress(msg)
+	}
+
+	visibleCount := len(a.visibleCacheEntries())
+	switch msg.String() {
+	case "up", "k":
+		if a.selectedIndex > 0 {
+			a.selectedIndex = clampToRange(a.selectedIndex-1, 0, visibleCount-1)
+		}
+	case "down", "j":
+		if a.selectedIndex < visibleCount-1 {
+			a.selectedIndex = clampToRange(a.selectedIndex+1, 0, visibleCount-1)
+		}
+	case "enter", " ":
+		a.activateCacheEntry()
+	case "/":
+		a.cacheInputMode = cacheInputSearch
+		a.cacheInput = a.cacheSearch
+	case "a", "A":
+		a.cacheShowAdvanced = !a.cacheShowAdvanced
+		a.clampCacheSelection()
+	case "u", "U":
+		if entry, ok := a.selectedCacheEntry(); ok {
+			delete(a.cacheEdits, entry.Name)
+		}
+	case "c", "C":
+		return a.applyCacheEdits()
+	case "esc":
+		a.cacheEntries = nil
+		a.cacheEdits = nil
+		a.returnToMenuFromConsole()
+	case "ctrl+c":
+		return a.handleCtrlC()
+	}
+	return a, nil
+}
+
+// handleCacheInputKeyPress edits the search prompt (filtering live) or the selected entry's value
+func (a *Application) handleCacheInputKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if a.cacheInputMode == cacheInputEdit {
+			if entry, ok := a.selectedCacheEntry(); ok {
+				a.setCacheValue(entry, a.cacheInput)
+			}
+		}
+		a.cacheInputMode = cacheInputNone
+	case tea.KeyEsc:
+		if a.cacheInputMode == cacheInputSearch {
+			a.cacheSearch = ""
+			a.clampCacheSelection()
+		}
+		a.cacheInputMode = cacheInputNone
+	case tea.KeyCtrlC:
+		return a.handleCtrlC()
+	default:
+		a.cacheInput = editTextInput(a.cacheInput, msg)
+		if a.cacheInputMode == cacheInputSearch {
+			a.cacheSearch = a.cacheInput
+			a.clampCacheSelection()
+		}
+	}
+	return a, nil
+}
+
+// renderCacheView renders the cache browser
+func (a *Application) renderCacheView() string {
+	visible := a.visibleCacheEntries()
+	rows := make([]ui.CacheRow, 0, len(visible))
+	for _, entry := range visible {
+		_, modified := a.cacheEdits[entry.Name]
+		rows = append(rows, ui.CacheRow{
+			Name:     entry.Name,
+			Type:     entry.Type,
+			Value:    a.cacheValue(entry),
+			Help:     entry.Help,
+			Modified: modified,
+		})
+	}
+
+	viewState := ui.CacheViewState{
+		Rows:         rows,
+		Total:        len(a.cacheEntries),
+		Search:       a.cacheSearch,
+		ShowAdvanced: a.cacheShowAdvanced,
+		Pending:      len(a.cacheEdits),
+	}
+	return ui.RenderCacheView(viewState, a.selectedIndex, a.theme, a.sizing.ContentHeight, a.sizing.ContentInnerWidth)
+}
+
+// getCacheFooter returns the prompt while typing, else the cache shortcuts
+func (a *Application) getCacheFooter(width int) string {
+	switch a.cacheInputMode {
+	case cacheInputSearch:
+		return ui.RenderFooterOverride("Search: "+a.cacheInput+"█", width, &a.theme)
+	case cacheInputEdit:
+		entry, _ := a.selectedCacheEntry()
+		return ui.RenderFooterOverride(entry.Name+" = "+a.cacheInput+"█", width, &a.theme)
+	}
+	return ui.RenderFooter(FooterHintShortcuts["cache"], width, &a.theme, a.footerHint)
+}
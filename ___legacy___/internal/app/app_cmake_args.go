@@ -0,0 +1,312 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ui"
+)
+
+// Extra cmake arguments editor prompt states
+const (
+	argsInputNone = iota
+	argsInputDefinition
+	argsInputArg
+	argsInputListName
+)
+
+// defaultArgListName names the list created by adding a row before any list exists
+const defaultArgListName = "default"
+
+// enterCMakeArgsMode opens the editor on the active generator's list in use, or its first list
+func (a *Application) enterCMakeArgsMode() {
+	if a.config == nil {
+		a.footerHint = "Config unavailable: extra cmake arguments cannot be saved"
+		return
+	}
+	generator := a.projectState.ActiveGenerator()
+	if generator == "" {
+		a.footerHint = "No generator selected"
+		return
+	}
+
+	a.argsGenerator = generator
+	a.argsList = a.config.ActiveArgList(generator)
+	if lists := a.config.GeneratorArgLists(generator); a.argsList == "" && len(lists) > 0 {
+		a.argsList = lists[0]
+	}
+	a.argsInputMode = argsInputNone
+	a.mode = ModeCMakeArgs
+	a.selectedIndex = 0
+	a.footerHint = ""
+}
+
+// cmakeArgRows returns the edited list's definitions, then its arguments
+func (a *Application) cmakeArgRows() []ui.CMakeArgRow {
+	list := a.config.GeneratorArgList(a.argsGenerator, a.argsList)
+	var rows []ui.CMakeArgRow
+	for _, definition := range list.Definitions {
+		rows = append(rows, ui.CMakeArgRow{Kind: ui.CMakeArgDefinition, Value: definition})
+	}
+	for _, arg := range list.Args {
+		rows = append(rows, ui.CMakeArgRow{Kind: ui.CMakeArgExtra, Value: arg})
+	}
+	return rows
+}
+
+// saveCMakeArgRows splits rows back into definitions and arguments and persists them in the
+// edited list; the first row of a generator without lists starts one called "default"
+func (a *Application) saveCMakeArgRows(rows []ui.CMakeArgRow) {
+	if a.argsList == "" {
+		a.argsList = defaultArgListName
+	}
+	var definitions, args []string
+	for _, row := range rows {
+		if row.Kind == ui.CMakeArgDefinition {
+			definitions = append(definitions, row.Value)
+		} else {
+			args = append(args, row.Value)
+		}
+	}
+	list := config.ArgList{Definitions: definitions, Args: args}
+	if err := a.config.SetGeneratorArgList(a.argsGenerator, a.argsList, list); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+		return
+	}
+	a.footerHint = ""
+}
+
+// normalizeDefinition accepts NAME[:TYPE]=VALUE, with or without a leading -D
+func normalizeDefinition(text string) (string, bool) {
+	definition := strings.TrimPrefix(strings.TrimSpace(text), "-D")
+	name, _, found := strings.Cut(definition, "=")
+	if !found || strings.TrimSpace(name) == "" {
+		return "", false
+	}
+	return definition, true
+}
+
+// commitArgsInput adds the typed row, or replaces the selected one when editing, or
+// creates the named list. Returns false, keeping the prompt open, when the input is invalid.
+func (a *Application) commitArgsInput() bool {
+	if a.argsInputMode == argsInputListName {
+		return a.createArgList(strings.TrimSpace(a.argsInput))
+	}
+	kind := ui.CMakeArgExtra
+	value := strings.TrimSpace(a.argsInput)
+	if a.argsInputMode == argsInputDefinition {
+		kind = ui.CMakeArgDefinition
+		definition, ok := normalizeDefinition(a.argsInput)
+		if !ok {
+			a.footerHint = "Definitions look like NAME=VALUE or NAME:TYPE=VALUE"
+			return false
+		}
+		value = definition
+	}
+	if value == "" {
+		return true
+	}
+
+	rows := a.cmakeArgRows()
+	row := ui.CMakeArgRow{Kind: kind, Value: value}
+	if a.argsEditing && a.selectedIndex < len(rows) {
+		rows[a.selectedIndex] = row
+	} else {
+		rows = append(rows, row)
+	}
+	a.saveCMakeArgRows(rows)
+
+	// Definitions stay ahead of arguments: find the row where it landed
+	for i, saved := range a.cmakeArgRows() {
+		if saved == row {
+			a.selectedIndex = i
+		}
+	}
+	return true
+}
+
+// createArgList adds an empty list called name and switches the editor to it
+func (a *Application) createArgList(name string) bool {
+	if name == "" {
+		return true
+	}
+	if slices.Contains(a.config.GeneratorArgLists(a.argsGenerator), name) {
+		a.footerHint = "A list called " + name + " already exists"
+		return false
+	}
+	if err := a.config.SetGeneratorArgList(a.argsGenerator, name, config.ArgList{}); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+		return true
+	}
+	a.argsList = name
+	a.selectedIndex = 0
+	a.footerHint = ""
+	return true
+}
+
+// cycleArgList switches the editor to the generator's next list
+func (a *Application) cycleArgList() {
+	lists := a.config.GeneratorArgLists(a.argsGenerator)
+	if len(lists) == 0 {
+		return
+	}
+	next := (slices.Index(lists, a.argsList) + 1) % len(lists)
+	a.argsList = lists[next]
+	a.selectedIndex = 0
+}
+
+// toggleArgListInUse passes the edited list to the generator's configures, or stops passing it
+func (a *Application) toggleArgListInUse() {
+	if a.argsList == "" {
+		return
+	}
+	active := a.argsList
+	if a.config.ActiveArgList(a.argsGenerator) == a.argsList {
+		active = ""
+	}
+	if err := a.config.SetActiveArgList(a.argsGenerator, active); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+		return
+	}
+	if active == "" {
+		a.footerHint = "No list is passed to " + a.argsGenerator + " configures"
+	} else {
+		a.footerHint = a.argsList + " is passed to every " + a.argsGenerator + " configure"
+	}
+}
+
+// removeArgList deletes the edited list and moves to the list in use, or the first one left
+func (a *Application) removeArgList() {
+	if a.argsList == "" {
+		return
+	}
+	if err := a.config.RemoveGeneratorArgList(a.argsGenerator, a.argsList); err != nil {
+		a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+		return
+	}
+	a.footerHint = "Removed list " + a.argsList
+	a.argsList = a.config.ActiveArgList(a.argsGenerator)
+	if lists := a.config.GeneratorArgLists(a.argsGenerator); a.argsList == "" && len(lists) > 0 {
+		a.argsList = lists[0]
+	}
+	a.selectedIndex = 0
+}
+
+func (a *Application) openArgsInput(inputMode int, text string, editing bool) {
+	a.argsInputMode = inputMode
+	a.argsInput = text
+	a.argsEditing = editing
+	a.footerHint = ""
+}
+
+func (a *Application) handleCMakeArgsKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.lastActivityTime = time.Now()
+	if a.argsInputMode != argsInputNone {
+		return a.handleCMakeArgsInputKeyPress(msg)
+	}
+
+	rows := a.cmakeArgRows()
+	switch msg.String() {
+	case "up", "k":
+		if a.selectedIndex > 0 {
+			a.selectedIndex = clampToRange(a.selectedIndex-1, 0, len(rows)-1)
+		}
+	case "down", "j":
+		if a.selectedIndex < len(rows)-1 {
+			a.selectedIndex = clampToRange(a.selectedIndex+1, 0, len(rows)-1)
+		}
+	case "enter", " ":
+		if a.selectedIndex < len(rows) {
+			row := rows[a.selectedIndex]
+			if row.Kind == ui.CMakeArgDefinition {
+				a.openArgsInput(argsInputDefinition, row.Value, true)
+			} else {
+				a.openArgsInput(argsInputArg, row.Value, true)
+			}
+		}
+	case "d", "D":
+		a.openArgsInput(argsInputDefinition, "", false)
+	case "a", "A":
+		a.openArgsInput(argsInputArg, "", false)
+	case "n", "N":
+		a.openArgsInput(argsInputListName, "", false)
+	case "tab":
+		a.cycleArgList()
+	case "u", "U":
+		a.toggleArgListInUse()
+	case "r", "R":
+		a.removeArgList()
+	case "x", "X", "delete":
+		if a.selectedIndex < len(rows) {
+			rows = append(rows[:a.selectedIndex], rows[a.selectedIndex+1:]...)
+			a.saveCMakeArgRows(rows)
+			if a.selectedIndex >= len(rows) && a.selectedIndex > 0 {
+				a.selectedIndex--
+			}
+		}
+	case "esc":
+		a.argsGenerator = ""
+		a.argsList = ""
+		a.returnToMenuFromConsole()
+	case "ctrl+c":
+		return a.handleCtrlC()
+	}
+	return a, nil
+}
+
+// handleCMakeArgsInputKeyPress edits the definition or argument prompt
+func (a *Application) handleCMakeArgsInputKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if a.commitArgsInput() {
+			a.argsInputMode = argsInputNone
+		}
+	case tea.KeyEsc:
+		a.argsInputMode = argsInputNone
+	case tea.KeyCtrlC:
+		return a.handleCtrlC()
+	default:
+		a.argsInput = editTextInput(a.argsInput, msg)
+	}
+	return a, nil
+}
+
+// renderCMakeArgsView renders the extra cmake arguments editor
+func (a *Application) renderCMakeArgsView() string {
+	rows := a.cmakeArgRows()
+	viewState := ui.CMakeArgsViewState{
+		Generator: a.argsGenerator,
+		List:      a.argsList,
+		ListCount: len(a.config.GeneratorArgLists(a.argsGenerator)),
+		InUse:     a.argsList != "" && a.config.ActiveArgList(a.argsGenerator) == a.argsList,
+		Rows:      rows,
+		Preview:   ui.CMakeArgsPreview(rows),
+	}
+	return ui.RenderCMakeArgsView(viewState, a.selectedIndex, a.theme, a.sizing.ContentHeight, a.sizing.ContentInnerWidth)
+}
+
+// getCMakeArgsFooter returns the prompt while typing, else the editor shortcuts
+func (a *Application) getCMakeArgsFooter(width int) string {
+	switch a.argsInputMode {
+	case argsInputDefinition:
+		return ui.RenderFooterOverride(footerPrompt("-D ", a.argsInput, a.footerHint), width, &a.theme)
+	case argsInputArg:
+		return ui.RenderFooterOverride(footerPrompt("cmake argument: ", a.argsInput, ""), width, &a.theme)
+	case argsInputListName:
+		return ui.RenderFooterOverride(footerPrompt("List name: ", a.argsInput, a.footerHint), width, &a.theme)
+	}
+	return ui.RenderFooter(FooterHintShortcuts["cmake_args"], width, &a.theme, a.footerHint)
+}
+
+// footerPrompt renders a prompt with its cursor, followed by a validation hint if any
+func footerPrompt(label, input, hint string) string {
+	prompt := label + input + "█"
+	if hint != "" {
+		prompt += "  " + hint
+	}
+	return prompt
+}
@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
 )
 
 // startAsyncOperation marks the app as running an async operation and clears prior output.
@@ -21,9 +22,55 @@ func (a *Application) enterConsoleMode(op ui.OpType, footerHint string) {
 	a.spinnerFrame = 0
 	a.asyncState.Start(op)
 	a.outputBuffer.Clear()
+	a.startOperationLog(op)
 	a.footerHint = footerHint
 	a.mode = ModeConsole
 	a.consoleAutoScroll = true
+	a.consoleBackToDiagnostics = false
+}
+
+// operationNames names each console operation in the history and its log file
+var operationNames = map[ui.OpType]string{
+	ui.OpBuild:      "build",
+	ui.OpGenerate:   "generate",
+	ui.OpTest:       "test",
+	ui.OpRun:        "run",
+	ui.OpClean:      "clean",
+	ui.OpCleanAll:   "clean all",
+	ui.OpRegenerate: "regenerate",
+}
+
+// unloggedOperations delete the build tree the logs live in, so they are recorded in the
+// history without a log
+var unloggedOperations = map[ui.OpType]bool{
+	ui.OpClean:      true,
+	ui.OpCleanAll:   true,
+	ui.OpRegenerate: true,
+}
+
+// startOperationLog starts the operation's history entry and spools the console to a new
+// log in the selected build tree, closing the previous operation's log
+func (a *Application) startOperationLog(op ui.OpType) {
+	name, recorded := operationNames[op]
+	if !recorded {
+		a.outputBuffer.SpoolTo(nil)
+		a.historyEntry = nil
+		return
+	}
+	a.beginHistoryEntry(name)
+	if unloggedOperations[op] {
+		a.outputBuffer.SpoolTo(nil)
+		return
+	}
+	file, err := utils.CreateOperationLog(a.projectState.GetBuildPath(), name, time.Now())
+	if err != nil {
+		a.outputBuffer.SpoolTo(nil)
+		a.outputBuffer.Append("Log not written: "+err.Error(), ui.TypeWarning)
+		return
+	}
+	a.outputBuffer.SpoolTo(file)
+	a.historyEntry.Log = file.Name()
+	a.outputBuffer.Append("Log: "+a.displayPath(file.Name()), ui.TypeInfo)
 }
 
 // cmdRefreshConsole sends periodic refresh messages while async operation is active
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/ops"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+// enterDiagnosticsMode shows the errors and warnings of the last build
+func (a *Application) enterDiagnosticsMode() {
+	if len(a.diagnostics) == 0 {
+		a.footerHint = "No errors or warnings from the last build"
+		return
+	}
+	a.mode = ModeDiagnostics
+	a.selectedIndex = 0
+	// Start on the first error: Enter then opens what broke the build
+	for i, diagnostic := range a.visibleDiagnostics() {
+		if diagnostic.IsError() {
+			a.selectedIndex = i
+			break
+		}
+	}
+	a.footerHint = diagnosticsSummaryHint(a.diagnostics)
+}
+
+// visibleDiagnostics returns the diagnostics shown in the error list
+func (a *Application) visibleDiagnostics() []utils.Diagnostic {
+	if !a.diagnosticsErrorsOnly {
+		return a.diagnostics
+	}
+	var errors []utils.Diagnostic
+	for _, diagnostic := range a.diagnostics {
+		if diagnostic.IsError() {
+			errors = append(errors, diagnostic)
+		}
+	}
+	return errors
+}
+
+func (a *Application) handleDiagnosticsKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleCount := len(a.visibleDiagnostics())
+	switch msg.String() {
+	case "up", "k":
+		if a.selectedIndex > 0 {
+			a.selectedIndex = clampToRange(a.selectedIndex-1, 0, visibleCount-1)
+		}
+	case "down", "j":
+		if a.selectedIndex < visibleCount-1 {
+			a.selectedIndex = clampToRange(a.selectedIndex+1, 0, visibleCount-1)
+		}
+	case "home", "g":
+		a.selectedIndex = 0
+	case "end", "G":
+		a.selectedIndex = clampToRange(visibleCount-1, 0, visibleCount-1)
+	case "enter", " ":
+		return a.openSelectedDiagnostic()
+	case "q", "Q":
+		return a.writeQuickfix()
+	case "e", "E":
+		a.diagnosticsErrorsOnly = !a.diagnosticsErrorsOnly
+		a.selectedIndex = clampToRange(a.selectedIndex, 0, len(a.visibleDiagnostics())-1)
+	case "o", "O":
+		a.mode = ModeConsole
+		a.consoleBackToDiagnostics = true
+		a.footerHint = a.GetDefaultFooterHint()
+	case "esc":
+		a.consoleBackToDiagnostics = false
+		a.returnToMenuFromConsole()
+	case "ctrl+c":
+		return a.handleCtrlC()
+	}
+	return a, nil
+}
+
+// diagnosticLocation shortens paths inside the project to project-relative ones
+func (a *Application) diagnosticLocation(diagnostic utils.Diagnostic) string {
+	if filepath.IsAbs(diagnostic.File) {
+		if rel, err := filepath.Rel(a.projectState.WorkingDirectory, diagnostic.File); err == nil && !strings.HasPrefix(rel, "..") {
+			diagnostic.File = rel
+		}
+	}
+	return diagnostic.Location()
+}
+
+// renderDiagnosticsView renders the error list
+func (a *Application) renderDiagnosticsView() string {
+	visible := a.visibleDiagnostics()
+	rows := make([]ui.DiagnosticRow, 0, len(visible))
+	for _, diagnostic := range visible {
+		message := diagnostic.Message
+		if diagnostic.Code != "" {
+			message = diagnostic.Code + ": " + message
+		}
+		rows = append(rows, ui.DiagnosticRow{
+			Location: a.diagnosticLocation(diagnostic),
+			Message:  message,
+			Notes:    diagnostic.Notes,
+			IsError:  diagnostic.IsError(),
+		})
+	}
+
+	errors, warnings := utils.CountDiagnostics(a.diagnostics)
+	viewState := ui.DiagnosticsViewState{
+		Rows:       rows,
+		Errors:     errors,
+		Warnings:   warnings,
+		ErrorsOnly: a.diagnosticsErrorsOnly,
+	}
+	return ui.RenderDiagnostics(viewState, a.selectedIndex, a.theme, a.sizing.ContentHeight, a.sizing.ContentInnerWidth)
+}
+
+// diagnosticsSummaryHint returns e.g. "2 errors, 1 warning."
+func diagnosticsSummaryHint(diagnostics []utils.Diagnostic) string {
+	errors, warnings := utils.CountDiagnostics(diagnostics)
+	return fmt.Sprintf("%d error%s, %d warning%s.", errors, pluralSuffix(errors), warnings, pluralSuffix(warnings))
+}
+
+func pluralSuffix(count int) string {
+	if count == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// offerDiagnostics makes ESC from the console open the error list when the build reported any
+func (a *Application) offerDiagnostics(prefix string) bool {
+	if len(a.diagnostics) == 0 {
+		return false
+	}
+	a.consoleBackToDiagnostics = true
+	a.footerHint = prefix + diagnosticsSummaryHint(a.diagnostics) + " Press ESC for the error list."
+	return true
+}
+
+// editorCommand returns the configured editor, else $VISUAL, else $EDITOR, else nvim
+func (a *Application) editorCommand() string {
+	configured := ""
+	if a.config != nil {
+		configured = a.config.Editor.Command
+	}
+	return utils.ResolveEditor(configured)
+}
+
+// editorTemplates returns the user's goto argument templates by editor name
+func (a *Application) editorTemplates() map[string]string {
+	if a.config == nil {
+		return nil
+	}
+	return a.config.Editor.Goto
+}
+
+// openSelectedDiagnostic opens the editor at the selected diagnostic's file:line:col
+func (a *Application) openSelectedDiagnostic() (tea.Model, tea.Cmd) {
+	visible := a.visibleDiagnostics()
+	if a.selectedIndex < 0 || a.selectedIndex >= len(visible) {
+		return a, nil
+	}
+	diagnostic := visible[a.selectedIndex]
+	if diagnostic.File == "" {
+		a.footerHint = "No source file for " + diagnostic.Location()
+		return a, nil
+	}
+
+	file := utils.ResolveDiagnosticPath(diagnostic.File, a.diagnosticsBuildDir, a.projectState.WorkingDirectory)
+	cmd, err := ops.EditorGotoCommand(a.editorCommand(), a.editorTemplates(), file, diagnostic.Line, diagnostic.Column, a.projectState.WorkingDirectory)
+	if err != nil {
+		a.footerHint = err.Error()
+		return a, nil
+	}
+	return a, a.launchEditor(cmd)
+}
+
+// writeQuickfix writes the diagnostics to the build tree's vim errorfile and, for
+// vim-family editors, opens it as the quickfix list
+func (a *Application) writeQuickfix() (tea.Model, tea.Cmd) {
+	path := filepath.Join(a.diagnosticsBuildDir, internal.QuickfixFile)
+	if err := utils.WriteQuickfix(path, a.diagnostics, a.diagnosticsBuildDir, a.projectState.WorkingDirectory); err != nil {
+		a.footerHint = err.Error()
+		return a, nil
+	}
+
+	cmd, err := ops.EditorQuickfixCommand(a.editorCommand(), path, a.projectState.WorkingDirectory)
+	if err != nil {
+		a.footerHint = "Wrote " + path
+		return a, nil
+	}
+	return a, a.launchEditor(cmd)
+}
@@ -14,6 +14,11 @@ var menuShortcutMap = map[string]string{
 	"O":      "openIde",
 	"b":      "build",
 	"B":      "build",
+	"t":      "test",
+	"T":      "test",
+	"r":      "run",
+	"d":      "debug",
+	"D":      "debug",
 	"c":      "clean",
 	"C":      "clean",
 	"x":      "cleanAll",
@@ -134,9 +139,15 @@ func (a *Application) clampSelectedIndex() {
 
 func (a *Application) executeMenuSelection(visibleCount int) (tea.Model, tea.Cmd) {
 	if a.selectedIndex >= 0 && a.selectedIndex < visibleCount {
+		rowID := a.GetVisibleRows()[a.selectedIndex].ID
 		handled, cmd := a.ToggleRowAtIndex(a.selectedIndex)
 		if handled {
 			a.menuItems = a.GenerateMenu()
+			// Keep the cursor on the same row when rows above it changed selectability
+			// (selecting a preset makes the Project row unselectable)
+			if idx := a.GetVisibleIndex(rowID); idx >= 0 {
+				a.selectedIndex = idx
+			}
 			a.clampSelectedIndex()
 			return a, cmd
 		}
@@ -168,6 +179,10 @@ func (a *Application) handleMenuKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	a.lastActivityTime = time.Now()
 	visibleCount := len(a.GetVisibleRows())
 
+	if a.runArgsEditing {
+		return a.handleRunArgsKey(msg)
+	}
+
 	switch msg.String() {
 	case "up", "k":
 		if a.selectedIndex > 0 {
@@ -184,6 +199,23 @@ func (a *Application) handleMenuKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "/":
 		a.togglePreferencesMode()
 		return a, nil
+	case "e", "E":
+		a.enterCacheMode()
+		return a, nil
+	case "a", "A":
+		a.enterCMakeArgsMode()
+		return a, nil
+	case "R":
+		a.openRunArgsPrompt()
+		return a, nil
+	case "w", "W":
+		return a.startWatch()
+	case "l", "L":
+		a.enterDiagnosticsMode()
+		return a, nil
+	case "h", "H":
+		a.enterHistoryMode()
+		return a, nil
 	case "ctrl+c":
 		return a.handleCtrlC()
 	default:
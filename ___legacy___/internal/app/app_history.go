@@ -0,0 +1,179 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ui"
+)
+
+// beginHistoryEntry starts recording the operation about to run; recordHistory finishes it
+func (a *Application) beginHistoryEntry(operation string) {
+	a.historyEntry = &config.HistoryEntry{
+		Project:       a.projectState.WorkingDirectory,
+		Operation:     operation,
+		Generator:     a.projectState.SelectedProject,
+		Preset:        a.projectState.SelectedPreset,
+		Configuration: a.projectState.Configuration,
+		Start:         time.Now(),
+	}
+}
+
+// recordHistory adds the operation that just finished to the history index
+func (a *Application) recordHistory(result string, exitCode int) {
+	entry := a.historyEntry
+	if entry == nil {
+		return
+	}
+	a.historyEntry = nil
+
+	entry.DurationMs = time.Since(entry.Start).Milliseconds()
+	entry.Result = result
+	if result == config.HistoryResultFailed {
+		entry.ExitCode = exitCode
+	}
+	if err := config.AppendHistory(*entry); err != nil {
+		a.outputBuffer.Append("History not saved: "+err.Error(), ui.TypeWarning)
+	}
+}
+
+// historyResult returns the history result of a finished, not aborted, operation
+func historyResult(success bool) string {
+	if success {
+		return config.HistoryResultSuccess
+	}
+	return config.HistoryResultFailed
+}
+
+// enterHistoryMode lists the past operations of this project, newest first
+func (a *Application) enterHistoryMode() {
+	history, err := config.LoadHistory()
+	if err != nil {
+		a.footerHint = err.Error()
+		return
+	}
+	// Close the last operation's log so it opens complete
+	a.outputBuffer.SpoolTo(nil)
+
+	a.historyEntries = history.ForProject(a.projectState.WorkingDirectory)
+	a.mode = ModeHistory
+	a.selectedIndex = 0
+	a.footerHint = historySummaryHint(a.historyEntries)
+}
+
+// historyRows converts the history entries into view rows
+func (a *Application) historyRows() []ui.HistoryRow {
+	rows := make([]ui.HistoryRow, 0, len(a.historyEntries))
+	for _, entry := range a.historyEntries {
+		generator := entry.Generator
+		if entry.Preset != "" {
+			generator = "preset " + entry.Preset
+		}
+		rows = append(rows, ui.HistoryRow{
+			Start:         entry.Start.Local().Format("Jan 02 15:04:05"),
+			Operation:     entry.Operation,
+			Generator:     generator,
+			Configuration: entry.Configuration,
+			Duration:      entry.Duration().Round(100 * time.Millisecond).String(),
+			Result:        entry.Result,
+			ExitCode:      entry.ExitCode,
+			Log:           a.displayPath(entry.Log),
+			Failed:        entry.Result == config.HistoryResultFailed,
+			Aborted:       entry.Result == config.HistoryResultAborted,
+		})
+	}
+	return rows
+}
+
+// displayPath returns path relative to the project root when it lies inside it
+func (a *Application) displayPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if rel, err := filepath.Rel(a.projectState.WorkingDirectory, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func historySummaryHint(entries []config.HistoryEntry) string {
+	if len(entries) == 0 {
+		return "No operations recorded yet."
+	}
+	return "Enter opens the selected operation's log."
+}
+
+// openHistoryLog shows the selected operation's saved log in the console viewer
+func (a *Application) openHistoryLog() {
+	if a.selectedIndex < 0 || a.selectedIndex >= len(a.historyEntries) {
+		return
+	}
+	entry := a.historyEntries[a.selectedIndex]
+	if entry.Log == "" {
+		a.footerHint = "No log was written for this operation"
+		return
+	}
+
+	file, err := os.Open(entry.Log)
+	if os.IsNotExist(err) {
+		a.footerHint = "Log was rotated away or cleaned: " + a.displayPath(entry.Log)
+		return
+	}
+	if err != nil {
+		a.footerHint = "Failed to open log: " + err.Error()
+		return
+	}
+	defer file.Close()
+
+	buffer, err := ui.ReadLog(file)
+	if err != nil {
+		a.footerHint = "Failed to read log: " + err.Error()
+		return
+	}
+
+	a.historyLog = buffer
+	a.consoleState.Reset()
+	a.consoleAutoScroll = false
+	a.mode = ModeConsole
+	a.footerHint = a.GetDefaultFooterHint()
+}
+
+// closeHistoryLog leaves the log viewer for the history list, on the same entry
+func (a *Application) closeHistoryLog() {
+	a.historyLog = nil
+	a.mode = ModeHistory
+	a.footerHint = historySummaryHint(a.historyEntries)
+}
+
+// consoleBuffer returns what the console shows: a saved log from the history, or the live output
+func (a *Application) consoleBuffer() *ui.OutputBuffer {
+	if a.historyLog != nil {
+		return a.historyLog
+	}
+	return a.outputBuffer
+}
+
+func (a *Application) handleHistoryKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.lastActivityTime = time.Now()
+	rowCount := len(a.historyEntries)
+	switch msg.String() {
+	case "up", "k":
+		if a.selectedIndex > 0 {
+			a.selectedIndex = clampToRange(a.selectedIndex-1, 0, rowCount-1)
+		}
+	case "down", "j":
+		if a.selectedIndex < rowCount-1 {
+			a.selectedIndex = clampToRange(a.selectedIndex+1, 0, rowCount-1)
+		}
+	case "enter", " ":
+		a.openHistoryLog()
+	case "esc", "h", "H":
+		a.returnToMenuFromConsole()
+	case "ctrl+c":
+		return a.handleCtrlC()
+	}
+	return a, nil
+}
@@ -116,9 +116,30 @@ func (a *Application) handleOperationKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cm
 		a.consoleState.ScrollDown()
 		a.consoleAutoScroll = false
 		return a, nil
+	case "pgup":
+		a.consoleState.ScrollPageUp()
+		a.consoleAutoScroll = false
+		return a, nil
+	case "pgdown":
+		a.consoleState.ScrollPageDown()
+		a.consoleAutoScroll = false
+		return a, nil
+	case "w", "W":
+		if a.watchActive {
+			a.stopWatch()
+		}
+		return a, nil
 	case "esc":
-		if a.asyncState.IsActive() {
+		if a.historyLog != nil {
+			a.closeHistoryLog()
+		} else if a.watchActive {
+			a.stopWatch()
+		} else if a.asyncState.IsActive() {
 			a.abortActiveOperation()
+		} else if a.consoleBackToTestResults {
+			a.enterTestResultsMode()
+		} else if a.consoleBackToDiagnostics {
+			a.enterDiagnosticsMode()
 		} else {
 			a.returnToMenuFromConsole()
 		}
@@ -129,6 +150,77 @@ func (a *Application) handleOperationKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cm
 	return a, nil
 }
 
+func (a *Application) handleTestResultsKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.lastActivityTime = time.Now()
+	if a.testFilterEditing {
+		return a.handleTestFilterKeyPress(msg)
+	}
+
+	rowCount := len(a.testResults)
+	switch msg.String() {
+	case "up", "k":
+		if a.selectedIndex > 0 {
+			a.selectedIndex = clampToRange(a.selectedIndex-1, 0, rowCount-1)
+		}
+	case "down", "j":
+		if a.selectedIndex < rowCount-1 {
+			a.selectedIndex = clampToRange(a.selectedIndex+1, 0, rowCount-1)
+		}
+	case "f", "F":
+		a.testFilterEditing = true
+		a.testFilterInput = a.testFilter
+	case "r", "R":
+		if !a.hasFailedTests() {
+			a.footerHint = "No failed tests to rerun"
+			return a, nil
+		}
+		return a.startTestOperation(true)
+	case "t", "T":
+		return a.startTestOperation(false)
+	case "o", "O":
+		a.mode = ModeConsole
+		a.consoleBackToTestResults = true
+		a.footerHint = a.GetDefaultFooterHint()
+	case "esc":
+		a.consoleBackToTestResults = false
+		a.returnToMenuFromConsole()
+	case "ctrl+c":
+		return a.handleCtrlC()
+	}
+	return a, nil
+}
+
+// handleTestFilterKeyPress edits the ctest -R regex; Enter applies it and reruns the tests
+func (a *Application) handleTestFilterKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		a.testFilterEditing = false
+		a.testFilter = a.testFilterInput
+		a.menuItems = a.GenerateMenu()
+		return a.startTestOperation(false)
+	case tea.KeyEsc:
+		a.testFilterEditing = false
+	case tea.KeyCtrlC:
+		return a.handleCtrlC()
+	default:
+		a.testFilterInput = editTextInput(a.testFilterInput, msg)
+	}
+	return a, nil
+}
+
+// editTextInput applies a typed rune or backspace to a single-line text prompt
+func editTextInput(input string, msg tea.KeyMsg) string {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if runes := []rune(input); len(runes) > 0 {
+			return string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		return input + string(msg.Runes)
+	}
+	return input
+}
+
 func (a *Application) handleCtrlC() (tea.Model, tea.Cmd) {
 	if a.asyncState.IsActive() {
 		a.footerHint = FooterHints["operation_wait"]
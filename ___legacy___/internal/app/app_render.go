@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/jrengmusic/cake/internal/config"
 	"github.com/jrengmusic/cake/internal/ui"
 
 	"github.com/charmbracelet/lipgloss"
@@ -38,7 +39,22 @@ func (a *Application) renderMenuWithBanner() string {
 
 // renderPreferencesWithBanner renders preferences (left 50%) + banner (right 50%)
 func (a *Application) renderPreferencesWithBanner() string {
-	return ui.RenderPreferencesWithBanner(a.config, a.selectedIndex, a.theme, a.sizing)
+	return ui.RenderPreferencesWithBanner(a.config, a.settings, a.selectedIndex, a.theme, a.sizing)
+}
+
+// renderTestResults renders the per-test outcomes of the last ctest run
+func (a *Application) renderTestResults() string {
+	state := ui.TestResultsState{
+		Rows:   a.testResultRows(),
+		Filter: a.testFilter,
+		Rerun:  a.testRerun,
+	}
+	return ui.RenderTestResults(state, a.selectedIndex, a.theme, a.sizing.ContentHeight, a.sizing.ContentInnerWidth)
+}
+
+// renderHistory renders the past operations of this project
+func (a *Application) renderHistory() string {
+	return ui.RenderHistory(a.historyRows(), a.selectedIndex, a.theme, a.sizing.ContentHeight, a.sizing.ContentInnerWidth)
 }
 
 func (a *Application) renderConsoleMode() string {
@@ -47,8 +63,9 @@ func (a *Application) renderConsoleMode() string {
 
 	consoleContent := ui.RenderConsoleOutput(
 		&a.consoleState,
-		a.outputBuffer,
+		a.consoleBuffer(),
 		a.theme,
+		a.config == nil || a.config.ConsoleColors() == config.ConsoleColorsTool,
 		a.sizing.TerminalWidth,
 		consoleHeight,
 		a.asyncState.IsActive(),
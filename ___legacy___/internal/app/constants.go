@@ -9,6 +9,14 @@ const (
 	// SpinnerTickInterval is the independent spinner animation tick rate (decoupled from console refresh)
 	SpinnerTickInterval = 80 * time.Millisecond
 
+	// WatchPollInterval is how often watch mode fingerprints the sources; each scan stats
+	// every source file, so it stays coarse. .cake.toml [watch] poll_ms overrides it
+	WatchPollInterval = 1 * time.Second
+
+	// WatchSettleDelay is how long sources must stay quiet before watch mode builds;
+	// .cake.toml [watch] delay_ms overrides it
+	WatchSettleDelay = 500 * time.Millisecond
+
 	MaxAutoScanInterval   = 60
 	MinAutoScanInterval   = 1
 	AutoScanIntervalStep  = 10
@@ -32,13 +32,40 @@ func (a *Application) GetFooterContent() string {
 		)
 
 	case ModeMenu:
-		// Menu mode: show selected menu item's hint/description
+		// Menu mode: Run arguments prompt while typing, else the selected menu item's hint/description
+		if a.runArgsEditing {
+			return ui.RenderFooterOverride(footerPrompt("Run arguments: ", a.runArgsInput, a.footerHint), width, &a.theme)
+		}
 		return a.getMenuFooter(width)
 
 	case ModeConsole:
 		// Console mode: show scroll shortcuts + scroll status (left/right)
 		return a.getConsoleFooter(width)
 
+	case ModeTestResults:
+		// Test results mode: filter prompt while editing, else shortcuts + run summary
+		if a.testFilterEditing {
+			return ui.RenderFooterOverride("Filter (regex): "+a.testFilterInput+"█", width, &a.theme)
+		}
+		shortcuts := FooterHintShortcuts["test_results"]
+		return ui.RenderFooter(shortcuts, width, &a.theme, a.footerHint)
+
+	case ModeDiagnostics:
+		// Error list mode: shortcuts + error/warning counts
+		return ui.RenderFooter(FooterHintShortcuts["diagnostics"], width, &a.theme, a.footerHint)
+
+	case ModeHistory:
+		// History mode: shortcuts + operation count or why a log did not open
+		return ui.RenderFooter(FooterHintShortcuts["history"], width, &a.theme, a.footerHint)
+
+	case ModeCache:
+		// Cache mode: search/edit prompt while typing, else shortcuts + status
+		return a.getCacheFooter(width)
+
+	case ModeCMakeArgs:
+		// Extra cmake arguments mode: definition/argument prompt while typing, else shortcuts + status
+		return a.getCMakeArgsFooter(width)
+
 	case ModePreferences:
 		// Preferences mode: navigation shortcuts
 		shortcuts := FooterHintShortcuts["preferences"]
@@ -70,7 +97,11 @@ func (a *Application) getMenuFooter(width int) string {
 func (a *Application) getConsoleFooter(width int) string {
 	// Determine which shortcut set to use
 	var hintKey string
-	if a.asyncState.IsActive() {
+	if a.historyLog != nil {
+		hintKey = "console_history"
+	} else if a.watchActive {
+		hintKey = "console_watching"
+	} else if a.asyncState.IsActive() {
 		hintKey = "console_running"
 	} else {
 		hintKey = "console_complete"
@@ -92,10 +123,22 @@ func (a *Application) GetDefaultFooterHint() string {
 		return FooterHints["menu_navigate"]
 	case ModePreferences:
 		return "↑↓ navigate │ Enter change │ / back"
+	case ModeTestResults:
+		return testSummaryHint(a.testResults)
+	case ModeCache, ModeCMakeArgs:
+		return ""
+	case ModeHistory:
+		return historySummaryHint(a.historyEntries)
 	case ModeConsole:
+		if a.historyLog != nil {
+			return "Press ESC to return to history"
+		}
 		if a.asyncState.IsActive() {
 			return "Operation in progress..."
 		}
+		if a.consoleBackToTestResults {
+			return "Press ESC to return to test results"
+		}
 		return "Press ESC to return to menu"
 	default:
 		return ""
@@ -3,6 +3,7 @@ package app
 import (
 	"time"
 
+	"github.com/jrengmusic/cake/internal"
 	"github.com/jrengmusic/cake/internal/config"
 	"github.com/jrengmusic/cake/internal/state"
 	"github.com/jrengmusic/cake/internal/ui"
@@ -22,33 +23,28 @@ func loadTheme(cfg *config.Config) ui.Theme {
 	return theme
 }
 
-func captureVSEnvironment() []string {
-	vcVarsAllPath, vsErr := utils.FindVCVarsAll()
-	if vsErr != nil {
-		return nil
-	}
-	// VS env capture failure is non-fatal: build operations fall back to system PATH
-	capturedVSEnv, _ := utils.CaptureVSEnv(vcVarsAllPath)
-	return capturedVSEnv
-}
-
-func initialModeAndHint(projectState *state.ProjectState, cfg *config.Config) (AppMode, string) {
+func initialModeAndHint(projectState *state.ProjectState, cfg *config.Config, settings config.Effective) (AppMode, string) {
 	if !projectState.HasCMakeLists {
 		return ModeInvalidProject, "The cake is a lie"
 	}
 
+	if settings.Generator != "" {
+		projectState.SetSelectedProject(settings.Generator)
+	}
+	projectState.SetConfiguration(settings.Configuration)
 	if cfg != nil {
-		if lastProject := cfg.LastProject(); lastProject != "" {
-			projectState.SetSelectedProject(lastProject)
-		}
-		if lastConfig := cfg.LastConfiguration(); lastConfig != "" {
-			projectState.SetConfiguration(lastConfig)
-		}
+		// After project and configuration: the target is checked against that build tree
+		projectState.SetSelectedTarget(cfg.LastTarget(projectState.WorkingDirectory))
+	}
+
+	if settings.ProjectError != "" {
+		return ModeMenu, "Ignoring " + internal.ProjectConfigFile + ": " + settings.ProjectError
 	}
 	return ModeMenu, FooterHints["menu_navigate"]
 }
 
-func NewApplication() *Application {
+// NewApplication creates the TUI model for the project rooted at projectRoot (absolute path)
+func NewApplication(projectRoot string) *Application {
 	// Create theme files if missing
 	ui.CreateDefaultThemeIfMissing()
 
@@ -56,15 +52,19 @@ func NewApplication() *Application {
 	cfg, _ := config.Load()
 	theme := loadTheme(cfg)
 
+	// .cake.toml failure is non-fatal: reported in the footer, user config used
+	projectConfig, projectConfigErr := config.LoadProjectConfig(projectRoot)
+	settings := config.Resolve(cfg, projectConfig, projectConfigErr)
+
 	// Capture Visual Studio environment before ForceRefresh — Ninja may only be
 	// discoverable via the VS-provided PATH, so detection must run after capture.
-	capturedVSEnv := captureVSEnvironment()
+	capturedVSEnv := utils.MergeEnv(utils.CaptureVSEnvironment(), settings.EnvList())
 
-	projectState := state.NewProjectState()
+	projectState := state.NewProjectState(projectRoot)
 	projectState.SetVSEnv(capturedVSEnv)
 	projectState.ForceRefresh()
 
-	initialMode, footerHint := initialModeAndHint(projectState, cfg)
+	initialMode, footerHint := initialModeAndHint(projectState, cfg, settings)
 
 	return &Application{
 		width:           DefaultTerminalWidth,
@@ -80,5 +80,6 @@ func NewApplication() *Application {
 		footerHint:      footerHint,
 		quitConfirmTime: time.Now(),
 		vsEnv:           capturedVSEnv,
+		settings:        settings,
 	}
 }
@@ -5,22 +5,51 @@ import (
 	"github.com/jrengmusic/cake/internal/utils"
 )
 
-// GenerateMenu returns exactly 8 rows using UI package
+// GenerateMenu returns exactly 14 rows using UI package
 func (a *Application) GenerateMenu() []ui.MenuRow {
 	buildInfo := a.projectState.GetSelectedBuildInfo()
-	canOpenIDE := a.projectState.CanOpenIDE() && buildInfo.Exists
-	canClean := buildInfo.Exists
-	hasBuild := buildInfo.Exists
-	hasBuildsToClean := a.projectState.HasBuildsToClean()
-	isIDEGenerator := utils.IsGeneratorIDE(a.projectState.SelectedProject)
+	_, presetActive := a.projectState.GetSelectedPreset()
 
-	return ui.GenerateMenuRows(
-		a.projectState.GetProjectLabel(),
-		a.projectState.Configuration,
-		canOpenIDE,
-		canClean,
-		hasBuild,
-		hasBuildsToClean,
-		isIDEGenerator,
-	)
+	return ui.GenerateMenuRows(ui.MenuState{
+		ProjectLabel:     a.projectState.GetProjectLabel(),
+		PresetLabel:      a.projectState.GetPresetLabel(),
+		PresetHint:       a.presetHint(),
+		HasPresets:       a.projectState.HasPresets(),
+		PresetActive:     presetActive,
+		Configuration:    a.projectState.Configuration,
+		Variant:          a.projectState.Variant,
+		TargetLabel:      a.projectState.GetTargetLabel(),
+		HasTargets:       len(a.projectState.GetTargets()) > 0,
+		TestLabel:        a.testLabel(),
+		CanTest:          a.projectState.CanBuild(),
+		RunLabel:         a.projectState.GetRunLabel(a.settings.Run.Target),
+		DebuggerLabel:    a.debuggerLabel(),
+		CanOpenIDE:       a.projectState.CanOpenIDE() && buildInfo.Exists,
+		CanClean:         buildInfo.Exists,
+		HasBuild:         buildInfo.Exists,
+		HasBuildsToClean: a.projectState.HasBuildsToClean(),
+		IsIDEGenerator:   utils.IsGeneratorIDE(a.projectState.ActiveGenerator()),
+	})
+}
+
+// presetHint describes the Preset row: load error, active preset, or how to pick one
+func (a *Application) presetHint() string {
+	if a.projectState.PresetError != "" {
+		return "Preset files invalid: " + a.projectState.PresetError
+	}
+	if preset, ok := a.projectState.GetSelectedPreset(); ok {
+		return "Preset: " + preset.Title() + " (cmake --preset " + preset.Name + ")"
+	}
+	if a.projectState.HasPresets() {
+		return "Select a CMake preset (None = cake's own generator and Builds/ layout)"
+	}
+	return "No CMakePresets.json in project"
+}
+
+// testLabel shows the active ctest -R filter on the Test row
+func (a *Application) testLabel() string {
+	if a.testFilter != "" {
+		return a.testFilter
+	}
+	return "All"
 }
@@ -4,14 +4,23 @@ import (
 	"time"
 
 	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
 )
 
 type TickMsg time.Time
 
 type BuildCompleteMsg struct {
+	Success     bool
+	ExitCode    int
+	Error       string
+	Diagnostics []utils.Diagnostic
+}
+
+type TestCompleteMsg struct {
 	Success  bool
 	ExitCode int
 	Error    string
+	Cases    []utils.CTestCase
 }
 
 type CleanCompleteMsg struct {
@@ -30,18 +39,33 @@ type OpenIDECompleteMsg struct {
 }
 
 type OpenEditorCompleteMsg struct {
-	Success bool
-	Error   string
+	Success  bool
+	Error    string
+	Editor   string // Editor name, e.g. "nvim"
+	Detached bool   // GUI editor left running in its own window
 }
 
 type GenerateCompleteMsg struct {
-	Success bool
-	Error   string
+	Success  bool
+	ExitCode int
+	Error    string
 }
 
 type RegenerateCompleteMsg struct {
-	Success bool
-	Error   string
+	Success  bool
+	ExitCode int
+	Error    string
+}
+
+// DebugCompleteMsg is sent when the debugger exits and the TUI is back
+type DebugCompleteMsg struct {
+	Error error
+}
+
+type RunCompleteMsg struct {
+	Success  bool
+	ExitCode int
+	Error    string
 }
 
 // OutputRefreshMsg triggers UI re-render to show updated console output
@@ -54,6 +78,18 @@ type SpinnerTickMsg struct{}
 // AutoScanTickMsg is sent periodically to trigger auto-scan
 type AutoScanTickMsg struct{}
 
+// WatchTickMsg schedules the next source poll of watch session Generation
+type WatchTickMsg struct {
+	Generation int
+}
+
+// WatchScanMsg carries the source fingerprint taken for watch session Generation
+type WatchScanMsg struct {
+	Generation  int
+	Fingerprint uint64
+	Error       error
+}
+
 type FooterMessageType int
 
 const (
@@ -62,6 +98,8 @@ const (
 	MessageSetupInProgress
 	MessageBuildInProgress
 	MessageCleanInProgress
+	MessageTestInProgress
+	MessageRunInProgress
 	MessageOperationComplete
 	MessageOperationFailed
 	MessageExitBlocked
@@ -73,6 +111,8 @@ var footerMessageText = map[FooterMessageType]string{
 	MessageSetupInProgress:   "Setting up CMake... (ESC to abort)",
 	MessageBuildInProgress:   "Building project... (ESC to abort)",
 	MessageCleanInProgress:   "Cleaning project... (ESC to abort)",
+	MessageTestInProgress:    "Running tests... (ESC to abort)",
+	MessageRunInProgress:     "Running executable... (ESC to abort)",
 	MessageOperationComplete: "Operation completed. Press ESC to return.",
 	MessageOperationFailed:   "Operation failed. Press ESC to return.",
 	MessageExitBlocked:       "Operation in progress. Cannot quit.",
@@ -86,7 +126,7 @@ func GetFooterMessageText(msgType FooterMessageType) string {
 }
 
 var FooterHints = map[string]string{
-	"menu_navigate":    "[g] Generate [b] Build [c] Clean [x] Clean All [o] Open [/] Config ↑↓ select",
+	"menu_navigate":    "[g] Generate [b] Build [t] Test [r] Run [d] Debug [w] Watch [l] Errors [c] Clean [x] Clean All [o] Open [e] Cache [a] Args [h] History [/] Config ↑↓ select",
 	"setup_gen_choose": "↑↓ choose project │ Enter select │ ESC back",
 	"ide_choose":       "↑↓ choose IDE project │ Enter select │ ESC back",
 	"editor_choose":    "↑↓ choose build dir │ Enter select │ ESC back",
@@ -109,6 +149,67 @@ var FooterHintShortcuts = map[string][]ui.FooterShortcut{
 		{Key: "↑↓", Desc: "scroll"},
 		{Key: "Esc", Desc: "back"},
 	},
+	"console_watching": {
+		{Key: "↑↓", Desc: "scroll"},
+		{Key: "w/Esc", Desc: "stop watching"},
+	},
+	"console_history": {
+		{Key: "↑↓", Desc: "scroll"},
+		{Key: "PgUp/PgDn", Desc: "page"},
+		{Key: "Esc", Desc: "back to history"},
+	},
+
+	// Test results mode
+	"test_results": {
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "f", Desc: "filter"},
+		{Key: "r", Desc: "rerun failed"},
+		{Key: "t", Desc: "run again"},
+		{Key: "o", Desc: "output"},
+		{Key: "Esc", Desc: "back"},
+	},
+
+	// Operation history
+	"history": {
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "Enter", Desc: "open log"},
+		{Key: "Esc", Desc: "back"},
+	},
+
+	// Error list of the last build
+	"diagnostics": {
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "Enter", Desc: "open in editor"},
+		{Key: "q", Desc: "quickfix"},
+		{Key: "e", Desc: "errors only"},
+		{Key: "o", Desc: "output"},
+		{Key: "Esc", Desc: "back"},
+	},
+
+	// Cache browser
+	"cache": {
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "Enter", Desc: "toggle/edit"},
+		{Key: "/", Desc: "search"},
+		{Key: "a", Desc: "advanced"},
+		{Key: "u", Desc: "undo"},
+		{Key: "c", Desc: "configure"},
+		{Key: "Esc", Desc: "back"},
+	},
+
+	// Extra cmake arguments editor
+	"cmake_args": {
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "Enter", Desc: "edit"},
+		{Key: "d", Desc: "add -D"},
+		{Key: "a", Desc: "add arg"},
+		{Key: "x", Desc: "remove"},
+		{Key: "Tab", Desc: "next list"},
+		{Key: "n", Desc: "new list"},
+		{Key: "u", Desc: "use list"},
+		{Key: "r", Desc: "remove list"},
+		{Key: "Esc", Desc: "back"},
+	},
 
 	// Preferences mode
 	"preferences": {
@@ -7,6 +7,11 @@ const (
 	ModeMenu
 	ModePreferences
 	ModeConsole
+	ModeTestResults
+	ModeCache
+	ModeCMakeArgs
+	ModeDiagnostics
+	ModeHistory
 )
 
 var modeNames = map[AppMode]string{
@@ -14,6 +19,11 @@ var modeNames = map[AppMode]string{
 	ModeMenu:           "menu",
 	ModePreferences:    "preferences",
 	ModeConsole:        "console",
+	ModeTestResults:    "testResults",
+	ModeCache:          "cache",
+	ModeCMakeArgs:      "cmakeArgs",
+	ModeDiagnostics:    "diagnostics",
+	ModeHistory:        "history",
 }
 
 func (m AppMode) String() string {
@@ -24,25 +24,53 @@ func (a *Application) cmdBuildProject(ctx context.Context) tea.Cmd {
 
 		project := a.projectState.SelectedProject
 		config := a.projectState.Configuration
+		target := a.projectState.GetBuildTarget()
 		projectRoot := a.projectState.WorkingDirectory
+		onProcessTreeStarted := func(tree *utils.ProcessTree) {
+			a.killTree = tree.Close
+		}
 
-		result := ops.ExecuteBuildProject(
-			ctx,
-			project,
-			config,
-			projectRoot,
-			a.vsEnv,
-			appendCallback,
-			replaceCallback,
-			func(tree *utils.ProcessTree) {
-				a.killTree = tree.Close
-			},
-		)
+		var result ops.BuildResult
+		if a.projectState.SelectedPreset != "" {
+			// buildPreset stays empty when no build preset builds the selected configuration
+			buildPreset, _ := a.projectState.GetSelectedBuildPreset()
+			result = ops.ExecuteBuildPreset(
+				ctx,
+				projectRoot,
+				buildPreset.Name,
+				a.projectState.GetBuildPath(),
+				a.projectState.ActiveGenerator(),
+				config,
+				target,
+				a.settings.Jobs,
+				a.settings.PTY,
+				a.vsEnv,
+				appendCallback,
+				replaceCallback,
+				onProcessTreeStarted,
+			)
+		} else {
+			result = ops.ExecuteBuildProject(
+				ctx,
+				project,
+				config,
+				a.projectState.Variant,
+				target,
+				projectRoot,
+				a.settings.Jobs,
+				a.settings.PTY,
+				a.vsEnv,
+				appendCallback,
+				replaceCallback,
+				onProcessTreeStarted,
+			)
+		}
 
 		return BuildCompleteMsg{
-			Success:  result.Success,
-			ExitCode: result.ExitCode,
-			Error:    result.Error,
+			Success:     result.Success,
+			ExitCode:    result.ExitCode,
+			Error:       result.Error,
+			Diagnostics: result.Diagnostics,
 		}
 	}
 }
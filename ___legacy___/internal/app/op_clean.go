@@ -18,16 +18,16 @@ func (a *Application) cmdCleanProject() tea.Cmd {
 		// replace callback unused: operation does not produce progress lines
 		appendCallback, _ := a.outputCallbacks()
 
-		project := a.projectState.SelectedProject
-		config := a.projectState.Configuration
-		projectRoot := a.projectState.WorkingDirectory
+		// GetBuildPath covers both Builds/<dir>/ and a preset's binaryDir
+		buildPath := a.projectState.GetBuildPath()
 
-		result := ops.ExecuteCleanProject(
-			project,
-			config,
-			projectRoot,
+		result := ops.ExecuteCleanDirectory(
+			buildPath,
 			appendCallback,
 		)
+		if result.Success {
+			appendCallback("Press ESC to return to menu", ui.TypeInfo)
+		}
 
 		return CleanCompleteMsg{
 			Success: result.Success,
@@ -1,10 +1,7 @@
 package app
 
 import (
-	"os"
-	"path/filepath"
-
-	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/ops"
 	"github.com/jrengmusic/cake/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,30 +19,15 @@ func (a *Application) cmdCleanAllProject() tea.Cmd {
 		// replace callback unused: operation does not produce progress lines
 		appendCallback, _ := a.outputCallbacks()
 
-		buildsDir := filepath.Join(a.projectState.WorkingDirectory, internal.BuildsDirName)
-
-		appendCallback("", ui.TypeStdout)
-		appendCallback("Cleaning all projects...", ui.TypeInfo)
-		appendCallback("Target: "+buildsDir, ui.TypeStdout)
-		appendCallback("", ui.TypeStdout)
-
-		// Remove the entire Builds directory
-		err := os.RemoveAll(buildsDir)
-		if err != nil {
-			appendCallback("Error: Failed to remove Builds directory: "+err.Error(), ui.TypeStderr)
-			return CleanAllCompleteMsg{
-				Success: false,
-				Error:   err.Error(),
-			}
+		result := ops.ExecuteCleanAllProject(a.projectState.WorkingDirectory, appendCallback)
+		if result.Success {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("Press ESC to return to menu", ui.TypeInfo)
 		}
 
-		appendCallback("✓ All build artifacts removed successfully", ui.TypeInfo)
-		appendCallback("", ui.TypeStdout)
-		appendCallback("Press ESC to return to menu", ui.TypeInfo)
-
 		return CleanAllCompleteMsg{
-			Success: true,
-			Error:   "",
+			Success: result.Success,
+			Error:   result.Error,
 		}
 	}
 }
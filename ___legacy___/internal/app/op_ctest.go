@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ops"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startTestOperation runs ctest on the selected build tree; rerunFailed limits it to last run's failures
+func (a *Application) startTestOperation(rerunFailed bool) (tea.Model, tea.Cmd) {
+	a.testRerun = rerunFailed
+	a.consoleBackToTestResults = false
+	a.enterConsoleMode(ui.OpTest, GetFooterMessageText(MessageTestInProgress))
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelContext = cancel
+	return a, tea.Batch(a.cmdTestProject(ctx, rerunFailed), a.cmdRefreshConsole(), a.cmdSpinnerTick())
+}
+
+// cmdTestProject executes ctest and collects per-test results
+func (a *Application) cmdTestProject(ctx context.Context, rerunFailed bool) tea.Cmd {
+	buildDir := a.projectState.GetBuildPath()
+	config := a.projectState.Configuration
+	filter := a.testFilter
+
+	return func() tea.Msg {
+		appendCallback, replaceCallback := a.outputCallbacks()
+		onProcessTreeStarted := func(tree *utils.ProcessTree) {
+			a.killTree = tree.Close
+		}
+
+		result := ops.ExecuteTestProject(
+			ctx,
+			buildDir,
+			config,
+			filter,
+			rerunFailed,
+			a.vsEnv,
+			appendCallback,
+			replaceCallback,
+			onProcessTreeStarted,
+		)
+
+		return TestCompleteMsg{
+			Success:  result.Success,
+			ExitCode: result.ExitCode,
+			Error:    result.Error,
+			Cases:    result.Cases,
+		}
+	}
+}
+
+// handleTestComplete stores the per-test results; ESC from the console then opens the results view
+func (a *Application) handleTestComplete(msg TestCompleteMsg) (tea.Model, tea.Cmd) {
+	if a.cancelContext != nil {
+		a.cancelContext()
+		a.cancelContext = nil
+	}
+	if a.killTree != nil {
+		a.killTree()
+		a.killTree = nil
+	}
+	a.asyncState.End()
+	if a.asyncState.IsAborted() {
+		a.asyncState.ClearAborted()
+		a.recordHistory(config.HistoryResultAborted, 0)
+		a.footerHint = "Operation aborted"
+		return a, nil
+	}
+	a.recordHistory(historyResult(msg.Success), msg.ExitCode)
+
+	if len(msg.Cases) == 0 {
+		if msg.Success {
+			a.footerHint = "No tests found"
+		} else {
+			a.footerHint = "Test failed: " + msg.Error
+		}
+		return a, nil
+	}
+
+	a.testResults = msg.Cases
+	a.consoleBackToTestResults = true
+	a.footerHint = testSummaryHint(a.testResults) + " Press ESC for results."
+	return a, nil
+}
+
+// enterTestResultsMode shows the results of the last ctest run
+func (a *Application) enterTestResultsMode() {
+	a.mode = ModeTestResults
+	a.selectedIndex = 0
+	a.testFilterEditing = false
+	a.footerHint = testSummaryHint(a.testResults)
+}
+
+// hasFailedTests reports whether --rerun-failed has anything to rerun
+func (a *Application) hasFailedTests() bool {
+	for _, testCase := range a.testResults {
+		if testCase.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// testResultRows converts ctest outcomes into rows for the results view
+func (a *Application) testResultRows() []ui.TestResultRow {
+	rows := make([]ui.TestResultRow, 0, len(a.testResults))
+	for _, testCase := range a.testResults {
+		rows = append(rows, ui.TestResultRow{
+			Name:     testCase.Name,
+			Status:   testCase.Status,
+			Detail:   testCase.Detail,
+			Duration: fmt.Sprintf("%.2f s", testCase.Duration.Seconds()),
+			Failed:   testCase.Failed(),
+		})
+	}
+	return rows
+}
+
+func testSummaryHint(cases []utils.CTestCase) string {
+	failed := 0
+	for _, testCase := range cases {
+		if testCase.Failed() {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return fmt.Sprintf("All %d tests passed.", len(cases))
+	}
+	return fmt.Sprintf("%d of %d tests failed.", failed, len(cases))
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"path/filepath"
+
+	"github.com/jrengmusic/cake/internal/ops"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// debuggerLabel returns the name of the debugger Debug launches, empty when none is found
+func (a *Application) debuggerLabel() string {
+	path, err := utils.FindDebugger(a.settings.Debug.Debugger)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(path)
+}
+
+// startDebugger suspends the TUI and runs the debugger on the executable Run would launch,
+// once the build it needed succeeded. The TUI comes back when the debugger exits.
+func (a *Application) startDebugger() (tea.Model, tea.Cmd) {
+	a.projectState.ForceRefresh()
+	a.menuItems = a.GenerateMenu()
+
+	executable, ok := a.projectState.GetRunExecutable(a.settings.Run.Target)
+	if !ok {
+		a.outputBuffer.Append("", ui.TypeStdout)
+		a.outputBuffer.Append("ERROR: No executable found in "+a.projectState.GetBuildPath(), ui.TypeStderr)
+		a.footerHint = "Debug failed: no executable target"
+		return a, nil
+	}
+
+	cmd, err := ops.DebugCommand(
+		a.settings.Debug.Debugger,
+		a.settings.Debug.InitCommands,
+		executable.Path,
+		a.runArgs(),
+		a.runWorkingDirectory(executable.Path),
+		utils.MergeEnv(a.vsEnv, a.settings.Run.EnvList()),
+	)
+	if err != nil {
+		a.outputBuffer.Append("", ui.TypeStdout)
+		a.outputBuffer.Append("ERROR: "+err.Error(), ui.TypeStderr)
+		a.footerHint = "Debug failed: " + err.Error()
+		return a, nil
+	}
+
+	a.outputBuffer.Append("", ui.TypeStdout)
+	a.outputBuffer.Append("Debugging: "+utils.JoinArgs(cmd.Args), ui.TypeInfo)
+	return a, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return DebugCompleteMsg{Error: err}
+	})
+}
+
+// handleDebugComplete shows how the debugger session ended, back in the console
+func (a *Application) handleDebugComplete(msg DebugCompleteMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil {
+		a.outputBuffer.Append("Debugger exited: "+msg.Error.Error(), ui.TypeStderr)
+		a.footerHint = "Debugger exited: " + msg.Error.Error()
+		return a, nil
+	}
+	a.outputBuffer.Append("Debugger exited", ui.TypeStatus)
+	a.footerHint = GetFooterMessageText(MessageOperationComplete)
+	return a, nil
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 
+	"github.com/jrengmusic/cake/internal"
 	"github.com/jrengmusic/cake/internal/ops"
 	"github.com/jrengmusic/cake/internal/ui"
 	"github.com/jrengmusic/cake/internal/utils"
@@ -11,40 +12,123 @@ import (
 
 // startGenerateOperation begins the generate/regenerate operation
 func (a *Application) startGenerateOperation() (tea.Model, tea.Cmd) {
+	return a.startConfigureOperation(nil)
+}
+
+// startConfigureOperation configures the selected build tree in place, passing definitions as -D overrides
+func (a *Application) startConfigureOperation(definitions []string) (tea.Model, tea.Cmd) {
 	a.enterConsoleMode(ui.OpGenerate, GetFooterMessageText(MessageSetupInProgress))
 
 	// Create cancellable context for process termination
 	ctx, cancel := context.WithCancel(context.Background())
 	a.cancelContext = cancel
 
-	return a, tea.Batch(a.cmdGenerateProject(ctx), a.cmdRefreshConsole(), a.cmdSpinnerTick())
+	return a, tea.Batch(a.cmdGenerateProject(ctx, a.configureDefinitions(definitions)), a.cmdRefreshConsole(), a.cmdSpinnerTick())
+}
+
+// configureDefinitions returns the compile_commands.json export when enabled, the active
+// generator's saved definitions, then the .cake.toml ones, then overrides; cmake keeps the
+// last -D of a name, so later sources win
+func (a *Application) configureDefinitions(overrides []string) []string {
+	var definitions []string
+	if a.settings.CompileCommands {
+		definitions = append(definitions, utils.CompileCommandsDefinition)
+	}
+	if a.config != nil {
+		definitions = append(definitions, a.config.GeneratorDefinitions(a.projectState.ActiveGenerator())...)
+	}
+	definitions = append(definitions, a.settings.Definitions...)
+	return append(definitions, overrides...)
+}
+
+// configureArgs returns the extra cmake arguments saved for the active generator
+func (a *Application) configureArgs() []string {
+	if a.config == nil {
+		return nil
+	}
+	return a.config.GeneratorArgs(a.projectState.ActiveGenerator())
+}
+
+// linkCompileCommands links the configured tree's compile_commands.json into the project root,
+// reporting the outcome in the console
+func linkCompileCommands(projectRoot, buildDir string, appendCallback func(string, ui.OutputLineType)) {
+	linked, err := utils.LinkCompileCommands(projectRoot, buildDir)
+	switch {
+	case err != nil:
+		appendCallback("WARNING: "+err.Error(), ui.TypeWarning)
+	case linked:
+		appendCallback(internal.CompileCommandsFile+" now follows "+buildDir, ui.TypeInfo)
+	default:
+		appendCallback("WARNING: generator wrote no "+internal.CompileCommandsFile+"; the project root links none", ui.TypeWarning)
+	}
+}
+
+// syncCompileCommands points the project root's compile_commands.json at the selected tree
+// after the Project, Preset, Configuration or Variant row changed it
+func (a *Application) syncCompileCommands() {
+	if !a.settings.CompileCommands {
+		return
+	}
+	linked, err := utils.LinkCompileCommands(a.projectState.WorkingDirectory, a.projectState.GetBuildPath())
+	switch {
+	case err != nil:
+		a.footerHint = err.Error()
+	case !linked:
+		a.footerHint = "No " + internal.CompileCommandsFile + " for this build; clangd has none until it is generated"
+	}
 }
 
 // cmdGenerateProject executes the generate/regenerate command
-func (a *Application) cmdGenerateProject(ctx context.Context) tea.Cmd {
+func (a *Application) cmdGenerateProject(ctx context.Context, definitions []string) tea.Cmd {
 	return func() tea.Msg {
 		appendCallback, replaceCallback := a.outputCallbacks()
 
 		generator := a.projectState.SelectedProject
 		config := a.projectState.Configuration
 		projectRoot := a.projectState.WorkingDirectory
+		onProcessTreeStarted := func(tree *utils.ProcessTree) {
+			a.killTree = tree.Close
+		}
 
-		result := ops.ExecuteSetupProject(
-			ctx,
-			projectRoot,
-			generator,
-			config,
-			a.vsEnv,
-			appendCallback,
-			replaceCallback,
-			func(tree *utils.ProcessTree) {
-				a.killTree = tree.Close
-			},
-		)
+		var result ops.SetupResult
+		if a.projectState.SelectedPreset != "" {
+			result = ops.ExecuteSetupPreset(
+				ctx,
+				projectRoot,
+				a.projectState.SelectedPreset,
+				a.projectState.GetBuildPath(),
+				a.projectState.PresetBinaryDirOverride(),
+				definitions,
+				a.configureArgs(),
+				a.vsEnv,
+				appendCallback,
+				replaceCallback,
+				onProcessTreeStarted,
+			)
+		} else {
+			result = ops.ExecuteSetupProject(
+				ctx,
+				projectRoot,
+				generator,
+				config,
+				a.projectState.Variant,
+				definitions,
+				a.configureArgs(),
+				a.vsEnv,
+				appendCallback,
+				replaceCallback,
+				onProcessTreeStarted,
+			)
+		}
+
+		if result.Success && a.settings.CompileCommands {
+			linkCompileCommands(projectRoot, a.projectState.GetBuildPath(), appendCallback)
+		}
 
 		return GenerateCompleteMsg{
-			Success: result.Success,
-			Error:   result.Error,
+			Success:  result.Success,
+			ExitCode: result.ExitCode,
+			Error:    result.Error,
 		}
 	}
 }
@@ -1,7 +1,10 @@
 package app
 
 import (
+	"os/exec"
+
 	"github.com/jrengmusic/cake/internal/ops"
+	"github.com/jrengmusic/cake/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -17,14 +20,12 @@ func (a *Application) cmdOpenIDE() tea.Cmd {
 		// replace callback unused: operation does not produce progress lines
 		appendCallback, _ := a.outputCallbacks()
 
-		project := a.projectState.SelectedProject
-		config := a.projectState.Configuration
-		projectRoot := a.projectState.WorkingDirectory
+		generator := a.projectState.ActiveGenerator()
+		buildPath := a.projectState.GetBuildPath()
 
 		result := ops.ExecuteOpenIDE(
-			project,
-			config,
-			projectRoot,
+			generator,
+			buildPath,
 			appendCallback,
 		)
 
@@ -35,25 +36,45 @@ func (a *Application) cmdOpenIDE() tea.Cmd {
 	}
 }
 
-// startOpenEditorOperation opens the editor in the build directory
+// startOpenEditorOperation opens the editor on the project root
 func (a *Application) startOpenEditorOperation() (tea.Model, tea.Cmd) {
-	a.startAsyncOperation("Opening editor...")
-	return a, a.cmdOpenEditor()
+	template := ""
+	if a.config != nil {
+		template = a.config.Editor.Open
+	}
+	cmd, err := ops.EditorOpenCommand(a.editorCommand(), template, a.projectState.WorkingDirectory)
+	if err != nil {
+		a.footerHint = "Failed to open editor: " + err.Error()
+		return a, nil
+	}
+	return a, a.launchEditor(cmd)
 }
 
-// cmdOpenEditor executes the open editor command
-func (a *Application) cmdOpenEditor() tea.Cmd {
-	return func() tea.Msg {
-		// replace callback unused: operation does not produce progress lines
-		appendCallback, _ := a.outputCallbacks()
-
-		buildPath := a.projectState.GetBuildPath()
-
-		result := ops.ExecuteOpenEditor(buildPath, appendCallback)
+// editorIsGUI reports whether the editor opens its own window: the [editor] gui
+// setting when given, else whether it is a known GUI editor
+func (a *Application) editorIsGUI(executable string) bool {
+	if a.config != nil && a.config.Editor.GUI != nil {
+		return *a.config.Editor.GUI
+	}
+	return utils.IsGUIEditor(executable)
+}
 
-		return OpenEditorCompleteMsg{
-			Success: result.Success,
-			Error:   result.Error,
+// launchEditor starts a GUI editor detached and leaves cake running; a terminal editor
+// gets the terminal, with the TUI suspended until it exits
+func (a *Application) launchEditor(cmd *exec.Cmd) tea.Cmd {
+	name := utils.EditorName(cmd.Path)
+	if a.editorIsGUI(cmd.Path) {
+		return func() tea.Msg {
+			if err := utils.StartDetached(cmd); err != nil {
+				return OpenEditorCompleteMsg{Success: false, Error: err.Error(), Editor: name}
+			}
+			return OpenEditorCompleteMsg{Success: true, Detached: true, Editor: name}
 		}
 	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return OpenEditorCompleteMsg{Success: false, Error: err.Error(), Editor: name}
+		}
+		return OpenEditorCompleteMsg{Success: true, Editor: name}
+	})
 }
@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 
-	"github.com/jrengmusic/cake/internal"
 	"github.com/jrengmusic/cake/internal/ops"
 	"github.com/jrengmusic/cake/internal/ui"
 	"github.com/jrengmusic/cake/internal/utils"
@@ -29,7 +27,8 @@ func (a *Application) cmdRegenerateProject(ctx context.Context) tea.Cmd {
 
 		project := a.projectState.SelectedProject
 		projectRoot := a.projectState.WorkingDirectory
-		buildDir := filepath.Join(projectRoot, internal.BuildsDirName, utils.GetDirectoryName(project))
+		config := a.projectState.Configuration
+		buildDir := a.projectState.GetBuildPath()
 
 		// Step 1: Clean
 		appendCallback("=== Step 1: Clean ===", ui.TypeInfo)
@@ -56,20 +55,46 @@ func (a *Application) cmdRegenerateProject(ctx context.Context) tea.Cmd {
 		// Step 2: Generate
 		result := RegenerateCompleteMsg{Success: false}
 		if cleanSucceeded {
-			setupResult := ops.ExecuteSetupProject(
-				ctx,
-				projectRoot,
-				project,
-				"",
-				a.vsEnv,
-				appendCallback,
-				replaceCallback,
-				func(tree *utils.ProcessTree) {
-					a.killTree = tree.Close
-				},
-			)
+			onProcessTreeStarted := func(tree *utils.ProcessTree) {
+				a.killTree = tree.Close
+			}
+
+			var setupResult ops.SetupResult
+			if a.projectState.SelectedPreset != "" {
+				setupResult = ops.ExecuteSetupPreset(
+					ctx,
+					projectRoot,
+					a.projectState.SelectedPreset,
+					buildDir,
+					a.projectState.PresetBinaryDirOverride(),
+					a.configureDefinitions(nil),
+					a.configureArgs(),
+					a.vsEnv,
+					appendCallback,
+					replaceCallback,
+					onProcessTreeStarted,
+				)
+			} else {
+				setupResult = ops.ExecuteSetupProject(
+					ctx,
+					projectRoot,
+					project,
+					config,
+					a.projectState.Variant,
+					a.configureDefinitions(nil),
+					a.configureArgs(),
+					a.vsEnv,
+					appendCallback,
+					replaceCallback,
+					onProcessTreeStarted,
+				)
+			}
 			result.Success = setupResult.Success
+			result.ExitCode = setupResult.ExitCode
 			result.Error = setupResult.Error
+			if result.Success && a.settings.CompileCommands {
+				linkCompileCommands(projectRoot, buildDir, appendCallback)
+			}
 		} else {
 			result.Error = cleanErr.Error()
 		}
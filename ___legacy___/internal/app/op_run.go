@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ops"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runArgs returns the arguments typed for Run in this project, else the .cake.toml defaults
+func (a *Application) runArgs() []string {
+	if a.config != nil {
+		if args, ok := a.config.LastRunArgs(a.projectState.WorkingDirectory); ok {
+			return args
+		}
+	}
+	return a.settings.Run.Args
+}
+
+// runWorkingDirectory returns the .cake.toml [run] cwd (relative to the project root),
+// else the executable's own directory
+func (a *Application) runWorkingDirectory(executable string) string {
+	cwd := a.settings.Run.Cwd
+	if cwd == "" {
+		return filepath.Dir(executable)
+	}
+	if !filepath.IsAbs(cwd) {
+		cwd = filepath.Join(a.projectState.WorkingDirectory, cwd)
+	}
+	return cwd
+}
+
+// openRunArgsPrompt opens the Run arguments prompt, prefilled with the current arguments
+func (a *Application) openRunArgsPrompt() {
+	a.runArgsEditing = true
+	a.runArgsInput = utils.JoinArgs(a.runArgs())
+	a.footerHint = ""
+}
+
+// handleRunArgsKey edits the Run arguments; Enter saves them for this project and runs
+func (a *Application) handleRunArgsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		args, err := utils.SplitArgs(a.runArgsInput)
+		if err != nil {
+			a.footerHint = err.Error()
+			return a, nil
+		}
+		a.runArgsEditing = false
+		a.footerHint = ""
+		if a.config != nil {
+			if err := a.config.SetLastRunArgs(a.projectState.WorkingDirectory, args); err != nil {
+				a.footerHint = fmt.Sprintf("Failed to save config: %v", err)
+				return a, nil
+			}
+		}
+		a.menuItems = a.GenerateMenu()
+		return a.executeMenuShortcut("run")
+	case tea.KeyEsc:
+		a.runArgsEditing = false
+		a.footerHint = ""
+	case tea.KeyCtrlC:
+		return a.handleCtrlC()
+	default:
+		a.runArgsInput = editTextInput(a.runArgsInput, msg)
+		a.footerHint = ""
+	}
+	return a, nil
+}
+
+// startRunProcess launches the executable Run picks, once the build it needed succeeded
+func (a *Application) startRunProcess() (tea.Model, tea.Cmd) {
+	a.projectState.ForceRefresh()
+	a.menuItems = a.GenerateMenu()
+
+	executable, ok := a.projectState.GetRunExecutable(a.settings.Run.Target)
+	if !ok {
+		a.outputBuffer.Append("", ui.TypeStdout)
+		a.outputBuffer.Append("ERROR: No executable found in "+a.projectState.GetBuildPath(), ui.TypeStderr)
+		a.footerHint = "Run failed: no executable target"
+		return a, nil
+	}
+
+	a.enterConsoleMode(ui.OpRun, GetFooterMessageText(MessageRunInProgress))
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelContext = cancel
+	return a, tea.Batch(a.cmdRunExecutable(ctx, executable.Path), a.cmdRefreshConsole(), a.cmdSpinnerTick())
+}
+
+// cmdRunExecutable runs the executable with the Run arguments, working directory and environment
+func (a *Application) cmdRunExecutable(ctx context.Context, executable string) tea.Cmd {
+	args := a.runArgs()
+	workingDir := a.runWorkingDirectory(executable)
+	env := utils.MergeEnv(a.vsEnv, a.settings.Run.EnvList())
+
+	return func() tea.Msg {
+		appendCallback, replaceCallback := a.outputCallbacks()
+		onProcessTreeStarted := func(tree *utils.ProcessTree) {
+			a.killTree = tree.Close
+		}
+
+		result := ops.ExecuteRun(
+			ctx,
+			executable,
+			args,
+			workingDir,
+			env,
+			appendCallback,
+			replaceCallback,
+			onProcessTreeStarted,
+		)
+
+		return RunCompleteMsg{
+			Success:  result.Success,
+			ExitCode: result.ExitCode,
+			Error:    result.Error,
+		}
+	}
+}
+
+// handleRunComplete reports the exit code of the run
+func (a *Application) handleRunComplete(msg RunCompleteMsg) (tea.Model, tea.Cmd) {
+	if a.cancelContext != nil {
+		a.cancelContext()
+		a.cancelContext = nil
+	}
+	if a.killTree != nil {
+		a.killTree()
+		a.killTree = nil
+	}
+	a.asyncState.End()
+	if a.asyncState.IsAborted() {
+		a.asyncState.ClearAborted()
+		a.recordHistory(config.HistoryResultAborted, 0)
+		a.footerHint = "Operation aborted"
+		return a, nil
+	}
+	a.recordHistory(historyResult(msg.Success), msg.ExitCode)
+	if msg.Success {
+		a.footerHint = GetFooterMessageText(MessageOperationComplete)
+	} else {
+		a.footerHint = fmt.Sprintf("Exited with code %d. Press ESC to return.", msg.ExitCode)
+	}
+	return a, nil
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchDelay returns how long sources must stay quiet before a change is built
+func (a *Application) watchDelay() time.Duration {
+	if a.settings.Watch.DelayMs > 0 {
+		return time.Duration(a.settings.Watch.DelayMs) * time.Millisecond
+	}
+	return WatchSettleDelay
+}
+
+// watchBuildDirs returns the build trees the watch skips: the selected one and every known one,
+// so a preset binaryDir inside the sources does not restart the build it is written by
+func (a *Application) watchBuildDirs() []string {
+	buildDirs := []string{a.projectState.GetBuildPath()}
+	for buildPath := range a.projectState.Builds {
+		buildDirs = append(buildDirs, buildPath)
+	}
+	return buildDirs
+}
+
+// watchPollInterval returns how often the sources are fingerprinted
+func (a *Application) watchPollInterval() time.Duration {
+	if a.settings.Watch.PollMs > 0 {
+		return time.Duration(a.settings.Watch.PollMs) * time.Millisecond
+	}
+	return WatchPollInterval
+}
+
+// startWatch switches to the console and builds on every settled source change until stopped
+func (a *Application) startWatch() (tea.Model, tea.Cmd) {
+	if !a.projectState.CanBuild() {
+		a.footerHint = "Generate the project before watching"
+		return a, nil
+	}
+	root := a.projectState.WorkingDirectory
+	fingerprint, err := utils.SourceFingerprint(root, a.settings.Watch.Ignore, a.watchBuildDirs())
+	if err != nil {
+		a.footerHint = fmt.Sprintf("Cannot watch %s: %v", root, err)
+		return a, nil
+	}
+
+	a.watchActive = true
+	a.watchGeneration++
+	a.watchFingerprint = fingerprint
+	a.watchPending = false
+	a.watchCancelled = false
+	a.watchCycle = 0
+
+	a.mode = ModeConsole
+	a.consoleAutoScroll = true
+	a.consoleBackToTestResults = false
+	a.consoleBackToDiagnostics = false
+	a.outputBuffer.Clear()
+	a.outputBuffer.Append("Watching "+root+" for changes (w or ESC to stop)", ui.TypeInfo)
+	a.footerHint = ""
+
+	// Build once right away so the console starts from the current state of the sources
+	return a, tea.Batch(a.startWatchCycle(), a.cmdWatchTick())
+}
+
+// stopWatch ends watch mode, aborting the cycle in progress if there is one
+func (a *Application) stopWatch() {
+	a.watchActive = false
+	a.watchGeneration++
+	a.watchPending = false
+	a.watchCancelled = false
+	if a.asyncState.IsActive() {
+		a.abortActiveOperation()
+		return
+	}
+	a.outputBuffer.Append("", ui.TypeStdout)
+	a.outputBuffer.Append("Stopped watching", ui.TypeInfo)
+	a.outputBuffer.Append("Press ESC to return to menu", ui.TypeInfo)
+}
+
+// startWatchCycle builds the selected target, keeping earlier cycles in the console
+func (a *Application) startWatchCycle() tea.Cmd {
+	a.watchPending = false
+	a.watchCycle++
+	a.watchCycleStart = time.Now()
+
+	a.spinnerFrame = 0
+	a.asyncState.Start(ui.OpBuild)
+	a.consoleAutoScroll = true
+	a.outputBuffer.Append("", ui.TypeStdout)
+	a.startOperationLog(ui.OpBuild)
+	a.outputBuffer.Append(fmt.Sprintf("=== Cycle %d · %s ===", a.watchCycle, a.watchCycleStart.Format("15:04:05")), ui.TypeInfo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelContext = cancel
+	return tea.Batch(a.cmdBuildProject(ctx), a.cmdRefreshConsole(), a.cmdSpinnerTick())
+}
+
+// cmdWatchTick schedules the next source poll of this watch session
+func (a *Application) cmdWatchTick() tea.Cmd {
+	generation := a.watchGeneration
+	return tea.Tick(a.watchPollInterval(), func(t time.Time) tea.Msg {
+		return WatchTickMsg{Generation: generation}
+	})
+}
+
+// cmdWatchScan fingerprints the sources off the UI goroutine
+func (a *Application) cmdWatchScan(generation int) tea.Cmd {
+	root := a.projectState.WorkingDirectory
+	ignore := a.settings.Watch.Ignore
+	buildDirs := a.watchBuildDirs()
+	return func() tea.Msg {
+		fingerprint, err := utils.SourceFingerprint(root, ignore, buildDirs)
+		return WatchScanMsg{Generation: generation, Fingerprint: fingerprint, Error: err}
+	}
+}
+
+// handleWatchTick polls the sources unless a previous session's tick arrives late
+func (a *Application) handleWatchTick(msg WatchTickMsg) (tea.Model, tea.Cmd) {
+	if !a.watchActive || msg.Generation != a.watchGeneration {
+		return a, nil
+	}
+	return a, a.cmdWatchScan(msg.Generation)
+}
+
+// handleWatchScan records a change, cancels a build that is now stale,
+// and starts the next cycle once the sources have been quiet for the delay
+func (a *Application) handleWatchScan(msg WatchScanMsg) (tea.Model, tea.Cmd) {
+	if !a.watchActive || msg.Generation != a.watchGeneration {
+		return a, nil
+	}
+	if msg.Error != nil {
+		a.footerHint = "Watch: " + msg.Error.Error()
+	}
+
+	if msg.Fingerprint != a.watchFingerprint {
+		a.watchFingerprint = msg.Fingerprint
+		a.watchChangedAt = time.Now()
+		a.watchPending = true
+		if a.asyncState.IsActive() && !a.watchCancelled {
+			a.watchCancelled = true
+			if a.cancelContext != nil {
+				a.cancelContext()
+			}
+			if a.killTree != nil {
+				a.killTree()
+				a.killTree = nil
+			}
+		}
+	}
+
+	if a.watchPending && !a.asyncState.IsActive() && time.Since(a.watchChangedAt) >= a.watchDelay() {
+		return a, tea.Batch(a.startWatchCycle(), a.cmdWatchTick())
+	}
+	return a, a.cmdWatchTick()
+}
+
+// handleWatchBuildComplete reports the cycle's result; the next change starts another cycle
+func (a *Application) handleWatchBuildComplete(msg BuildCompleteMsg) (tea.Model, tea.Cmd) {
+	elapsed := time.Since(a.watchCycleStart).Round(100 * time.Millisecond)
+	result := historyResult(msg.Success)
+	if a.watchCancelled {
+		result = config.HistoryResultAborted
+	}
+	a.outputBuffer.Append("", ui.TypeStdout)
+	switch {
+	case a.watchCancelled:
+		a.watchCancelled = false
+		a.outputBuffer.Append(fmt.Sprintf("Cycle %d cancelled: sources changed, restarting", a.watchCycle), ui.TypeWarning)
+	case msg.Success && len(msg.Diagnostics) > 0:
+		a.outputBuffer.Append(fmt.Sprintf("Cycle %d: build succeeded in %s, %s", a.watchCycle, elapsed, diagnosticsSummaryHint(msg.Diagnostics)), ui.TypeWarning)
+	case msg.Success:
+		a.outputBuffer.Append(fmt.Sprintf("Cycle %d: build succeeded in %s", a.watchCycle, elapsed), ui.TypeStatus)
+	case len(msg.Diagnostics) > 0:
+		a.outputBuffer.Append(fmt.Sprintf("Cycle %d: build failed in %s, %s", a.watchCycle, elapsed, diagnosticsSummaryHint(msg.Diagnostics)), ui.TypeStderr)
+	default:
+		a.outputBuffer.Append(fmt.Sprintf("Cycle %d: build failed in %s: %s", a.watchCycle, elapsed, msg.Error), ui.TypeStderr)
+	}
+	a.recordHistory(result, msg.ExitCode)
+	a.footerHint = ""
+	return a, nil
+}
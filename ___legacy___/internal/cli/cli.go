@@ -0,0 +1,437 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ops"
+	"github.com/jrengmusic/cake/internal/state"
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+// Headless subcommand names (SSOT)
+const (
+	CommandGenerate = "generate"
+	CommandBuild    = "build"
+	CommandClean    = "clean"
+	CommandCleanAll = "clean-all"
+)
+
+// Process exit codes for headless runs.
+// Failed cmake invocations exit with cmake's own code instead of ExitFailure.
+const (
+	ExitSuccess = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// options holds the flags shared by every headless subcommand
+type options struct {
+	projectDir    string
+	generator     string
+	configuration string
+	variant       string
+	preset        string
+	target        string
+	output        string
+}
+
+// TUIOptions holds the flags accepted when cake starts the interactive TUI
+type TUIOptions struct {
+	ProjectRoot string // Absolute project root (--project, default: current directory)
+}
+
+// IsSubcommand reports whether args start with a headless subcommand.
+// main() uses it to decide between the TUI and a headless run.
+func IsSubcommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case CommandGenerate, CommandBuild, CommandClean, CommandCleanAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run executes a headless subcommand and returns the process exit code.
+// Output goes to stdout/stderr; the same ops functions as the TUI do the work,
+// so both share the Builds/<Generator>/ layout.
+func Run(args []string) int {
+	if !IsSubcommand(args) {
+		printUsage(os.Stderr)
+		return ExitUsage
+	}
+
+	command := args[0]
+	opts, parseErr := parseOptions(command, args[1:])
+	if parseErr != nil {
+		if parseErr == flag.ErrHelp {
+			return ExitSuccess
+		}
+		return ExitUsage
+	}
+
+	out, outputErr := newEmitter(opts.output)
+	if outputErr != nil {
+		fmt.Fprintf(os.Stderr, "cake: %v\n", outputErr)
+		return ExitUsage
+	}
+
+	projectRoot, rootErr := state.ResolveProjectRoot(opts.projectDir)
+	if rootErr != nil {
+		fmt.Fprintf(os.Stderr, "cake: invalid --project: %v\n", rootErr)
+		return ExitUsage
+	}
+
+	// The user config is not read headless: CI runs see only flags and the committed .cake.toml
+	projectConfig, projectConfigErr := config.LoadProjectConfig(projectRoot)
+	if projectConfigErr != nil {
+		fmt.Fprintf(os.Stderr, "cake: %v\n", projectConfigErr)
+		return ExitFailure
+	}
+	settings := config.Resolve(nil, projectConfig, nil)
+	opts = applyProjectDefaults(opts, settings)
+
+	vsEnv := utils.MergeEnv(utils.CaptureVSEnvironment(), settings.EnvList())
+
+	projectState := state.NewProjectState(projectRoot)
+	projectState.SetVSEnv(vsEnv)
+	projectState.ForceRefresh()
+
+	if command == CommandCleanAll {
+		return runCleanAll(projectState, out)
+	}
+
+	if !projectState.HasCMakeLists {
+		fmt.Fprintf(os.Stderr, "cake: no %s in %s\n", internal.CMakeListsFile, projectState.WorkingDirectory)
+		return ExitFailure
+	}
+
+	if selectErr := applyOptions(projectState, opts); selectErr != nil {
+		fmt.Fprintf(os.Stderr, "cake: %v\n", selectErr)
+		return ExitUsage
+	}
+
+	// The child runs in its own process group, so Ctrl+C at the terminal reaches only cake:
+	// cancel the operation and take the whole cmake/ninja/compiler tree down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		utils.TerminateProcessTrees()
+	}()
+
+	switch command {
+	case CommandGenerate:
+		return runGenerate(ctx, projectState, settings, vsEnv, out)
+	case CommandBuild:
+		return runBuild(ctx, projectState, settings, vsEnv, out)
+	case CommandClean:
+		return runClean(projectState, out)
+	}
+	return ExitUsage
+}
+
+// ParseTUIArgs parses the flags accepted without a subcommand (--project) and
+// resolves the project root so the TUI never depends on the process cwd
+func ParseTUIArgs(args []string) (TUIOptions, error) {
+	var projectDir string
+
+	flags := flag.NewFlagSet(internal.AppName, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() { printUsage(os.Stderr) }
+	flags.StringVar(&projectDir, "project", "", "project root containing CMakeLists.txt (default: current directory)")
+
+	if err := flags.Parse(args); err != nil {
+		return TUIOptions{}, err
+	}
+	if flags.NArg() > 0 {
+		return TUIOptions{}, fmt.Errorf("unknown command %q", flags.Arg(0))
+	}
+
+	projectRoot, rootErr := state.ResolveProjectRoot(projectDir)
+	if rootErr != nil {
+		return TUIOptions{}, fmt.Errorf("invalid --project: %w", rootErr)
+	}
+	return TUIOptions{ProjectRoot: projectRoot}, nil
+}
+
+// parseOptions parses subcommand flags (--project, --generator/-g, --config/-c, --variant, --preset, --target, --output)
+func parseOptions(command string, args []string) (options, error) {
+	opts := options{}
+
+	flags := flag.NewFlagSet(internal.AppName+" "+command, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&opts.projectDir, "project", "", "project root containing CMakeLists.txt (default: current directory)")
+	flags.StringVar(&opts.generator, "generator", "", "CMake generator (default: first available)")
+	flags.StringVar(&opts.generator, "g", "", "shorthand for --generator")
+	flags.StringVar(&opts.configuration, "config", "", "build configuration (Debug, Release; default: Debug)")
+	flags.StringVar(&opts.configuration, "c", "", "shorthand for --config")
+	flags.StringVar(&opts.variant, "variant", utils.VariantDefault, "build variant ("+strings.Join(utils.Variants(), ", ")+")")
+	flags.StringVar(&opts.preset, "preset", "", "configure preset from CMakePresets.json (replaces --generator)")
+	flags.StringVar(&opts.target, "target", "", "build only this target (default: all)")
+	flags.StringVar(&opts.output, "output", OutputText, "output format: text or json (one JSON event per line)")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "cake %s: unexpected argument %q\n", command, flags.Arg(0))
+		return opts, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	return opts, nil
+}
+
+// applyProjectDefaults fills the generator and configuration not given as flags from .cake.toml
+// (settings resolved without a user config, so the configuration falls back to Debug)
+func applyProjectDefaults(opts options, settings config.Effective) options {
+	if opts.generator == "" && opts.preset == "" {
+		opts.generator = settings.Generator
+	}
+	if opts.configuration == "" {
+		opts.configuration = settings.Configuration
+	}
+	return opts
+}
+
+// applyOptions selects generator (or preset), configuration, variant and target on projectState, rejecting unknown values
+func applyOptions(projectState *state.ProjectState, opts options) error {
+	if opts.preset != "" {
+		if opts.generator != "" {
+			return fmt.Errorf("--preset and --generator are mutually exclusive")
+		}
+		if projectState.PresetError != "" {
+			return fmt.Errorf("cannot load presets: %s", projectState.PresetError)
+		}
+		projectState.SetSelectedPreset(opts.preset)
+		if projectState.SelectedPreset != opts.preset {
+			return fmt.Errorf("preset %q not available (available: %s)", opts.preset, availablePresetList(projectState))
+		}
+	} else if opts.generator != "" {
+		projectState.SetSelectedProject(opts.generator)
+		if projectState.SelectedProject != opts.generator {
+			return fmt.Errorf("generator %q not available (available: %s)", opts.generator, availableGeneratorList(projectState))
+		}
+	}
+	if projectState.SelectedProject == "" && projectState.SelectedPreset == "" {
+		return fmt.Errorf("no CMake generator available on this system")
+	}
+
+	projectState.SetConfiguration(opts.configuration)
+	if projectState.Configuration != opts.configuration {
+		return fmt.Errorf("configuration %q not supported (use %s or %s)", opts.configuration, internal.ConfigDebug, internal.ConfigRelease)
+	}
+
+	if opts.variant != utils.VariantDefault && projectState.SelectedPreset != "" {
+		return fmt.Errorf("--variant does not apply to presets: the preset decides the build flags")
+	}
+	if !utils.IsVariant(opts.variant) {
+		return fmt.Errorf("variant %q not supported (use %s)", opts.variant, strings.Join(utils.Variants(), ", "))
+	}
+	projectState.SetVariant(opts.variant)
+	if projectState.Variant != opts.variant {
+		return fmt.Errorf("variant %q not supported by %s", opts.variant, projectState.SelectedProject)
+	}
+
+	// Checked against the build tree when it is configured; otherwise cmake reports unknown targets
+	projectState.SetSelectedTarget(opts.target)
+	if projectState.SelectedTarget != opts.target {
+		return fmt.Errorf("target %q not found (available: %s)", opts.target, strings.Join(projectState.GetTargets(), ", "))
+	}
+	return nil
+}
+
+func availableGeneratorList(projectState *state.ProjectState) string {
+	names := make([]string, 0, len(projectState.AvailableProjects))
+	for _, gen := range projectState.AvailableProjects {
+		names = append(names, gen.Name)
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+func availablePresetList(projectState *state.ProjectState) string {
+	if !projectState.HasPresets() {
+		return "none"
+	}
+	names := make([]string, 0, len(projectState.Presets.Configure))
+	for _, preset := range projectState.Presets.Configure {
+		names = append(names, preset.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// runGenerate configures the selected generator into Builds/<Generator>/,
+// or runs `cmake --preset` when a preset is selected
+func runGenerate(ctx context.Context, projectState *state.ProjectState, settings config.Effective, vsEnv []string, out emitter) int {
+	appendCallback, replaceCallback := callbacks(out)
+	out.phaseStarted(PhaseConfigure)
+
+	definitions := settings.Definitions
+	if settings.CompileCommands {
+		definitions = append([]string{utils.CompileCommandsDefinition}, definitions...)
+	}
+
+	var result ops.SetupResult
+	if projectState.SelectedPreset != "" {
+		result = ops.ExecuteSetupPreset(
+			ctx,
+			projectState.WorkingDirectory,
+			projectState.SelectedPreset,
+			projectState.GetBuildPath(),
+			projectState.PresetBinaryDirOverride(),
+			definitions,
+			nil,
+			vsEnv,
+			appendCallback,
+			replaceCallback,
+			nil,
+		)
+	} else {
+		result = ops.ExecuteSetupProject(
+			ctx,
+			projectState.WorkingDirectory,
+			projectState.SelectedProject,
+			projectState.Configuration,
+			projectState.Variant,
+			definitions,
+			nil,
+			vsEnv,
+			appendCallback,
+			replaceCallback,
+			nil,
+		)
+	}
+
+	if result.Success && settings.CompileCommands {
+		if _, err := utils.LinkCompileCommands(projectState.WorkingDirectory, projectState.GetBuildPath()); err != nil {
+			appendCallback("WARNING: "+err.Error(), ui.TypeWarning)
+		}
+	}
+
+	code := exitCode(result.Success, result.ExitCode)
+	out.phaseFinished(PhaseConfigure, code)
+	return code
+}
+
+// runBuild builds the selected generator, configuring first when the build
+// directory does not exist yet (same chain as the TUI's buildAfterGenerate)
+func runBuild(ctx context.Context, projectState *state.ProjectState, settings config.Effective, vsEnv []string, out emitter) int {
+	if !projectState.CanBuild() {
+		if code := runGenerate(ctx, projectState, settings, vsEnv, out); code != ExitSuccess {
+			return code
+		}
+	}
+
+	appendCallback, replaceCallback := callbacks(out)
+	out.phaseStarted(PhaseBuild)
+
+	var result ops.BuildResult
+	if projectState.SelectedPreset != "" {
+		buildPreset, _ := projectState.GetSelectedBuildPreset()
+		result = ops.ExecuteBuildPreset(
+			ctx,
+			projectState.WorkingDirectory,
+			buildPreset.Name,
+			projectState.GetBuildPath(),
+			projectState.ActiveGenerator(),
+			projectState.Configuration,
+			projectState.SelectedTarget,
+			settings.Jobs,
+			false, // Headless output feeds CI logs and --output=json: always pipes, no escape sequences
+			vsEnv,
+			appendCallback,
+			replaceCallback,
+			nil,
+		)
+	} else {
+		result = ops.ExecuteBuildProject(
+			ctx,
+			projectState.SelectedProject,
+			projectState.Configuration,
+			projectState.Variant,
+			projectState.SelectedTarget,
+			projectState.WorkingDirectory,
+			settings.Jobs,
+			false, // Headless output feeds CI logs and --output=json: always pipes, no escape sequences
+			vsEnv,
+			appendCallback,
+			replaceCallback,
+			nil,
+		)
+	}
+
+	code := exitCode(result.Success, result.ExitCode)
+	out.phaseFinished(PhaseBuild, code)
+	return code
+}
+
+// runClean removes the build directory of the selected generator (or preset)
+func runClean(projectState *state.ProjectState, out emitter) int {
+	appendCallback, _ := callbacks(out)
+	out.phaseStarted(PhaseClean)
+
+	result := ops.ExecuteCleanDirectory(projectState.GetBuildPath(), appendCallback)
+
+	code := exitCode(result.Success, 0)
+	out.phaseFinished(PhaseClean, code)
+	return code
+}
+
+// runCleanAll removes the entire Builds/ directory
+func runCleanAll(projectState *state.ProjectState, out emitter) int {
+	appendCallback, _ := callbacks(out)
+	out.phaseStarted(PhaseCleanAll)
+
+	result := ops.ExecuteCleanAllProject(projectState.WorkingDirectory, appendCallback)
+
+	code := exitCode(result.Success, 0)
+	out.phaseFinished(PhaseCleanAll, code)
+	return code
+}
+
+// exitCode maps an operation result to a process exit code.
+// Failures without a process exit status (e.g. cmake not found) map to ExitFailure.
+func exitCode(success bool, processExitCode int) int {
+	if success {
+		return ExitSuccess
+	}
+	if processExitCode != 0 {
+		return processExitCode
+	}
+	return ExitFailure
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [--project <dir>]\n", internal.AppName)
+	fmt.Fprintf(w, "       %s <command> [flags]\n\n", internal.AppName)
+	fmt.Fprintln(w, "Without a command, cake starts the interactive TUI.")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintf(w, "  %-10s Configure Builds/<Generator>/ with cmake\n", CommandGenerate)
+	fmt.Fprintf(w, "  %-10s Build (configures first if needed)\n", CommandBuild)
+	fmt.Fprintf(w, "  %-10s Remove the selected generator's build directory\n", CommandClean)
+	fmt.Fprintf(w, "  %-10s Remove the entire Builds/ directory\n", CommandCleanAll)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "      --project    Project root containing CMakeLists.txt (default: current directory)")
+	fmt.Fprintln(w, "  -g, --generator  CMake generator (default: .cake.toml, else first available)")
+	fmt.Fprintln(w, "  -c, --config     Build configuration: Debug or Release (default: .cake.toml, else Debug)")
+	fmt.Fprintln(w, "      --variant    Build variant: default, asan, ubsan, tsan or coverage (own build directory)")
+	fmt.Fprintln(w, "      --preset     Configure preset from CMakePresets.json (instead of --generator)")
+	fmt.Fprintln(w, "      --target     Build only this target (build; default: all)")
+	fmt.Fprintln(w, "      --output     Output format: text or json (default: text)")
+}
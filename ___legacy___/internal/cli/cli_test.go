@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/jrengmusic/cake/internal/config"
+	"github.com/jrengmusic/cake/internal/ui"
+)
+
+func TestIsSubcommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"no args starts TUI", []string{}, false},
+		{"generate", []string{"generate"}, true},
+		{"build with flags", []string{"build", "-g", "Ninja"}, true},
+		{"clean", []string{"clean"}, true},
+		{"clean-all", []string{"clean-all"}, true},
+		{"unknown command", []string{"deploy"}, false},
+		{"flag first", []string{"--config", "Release"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsSubcommand(tc.args); got != tc.want {
+				t.Errorf("IsSubcommand(%v) = %v, want %v", tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name            string
+		success         bool
+		processExitCode int
+		want            int
+	}{
+		{"success", true, 0, ExitSuccess},
+		{"failure propagates process code", false, 2, 2},
+		{"failure without process code", false, 0, ExitFailure},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := exitCode(tc.success, tc.processExitCode); got != tc.want {
+				t.Errorf("exitCode(%v, %d) = %d, want %d", tc.success, tc.processExitCode, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := parseOptions(CommandBuild, []string{"--generator", "Ninja", "-c", "Release"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.generator != "Ninja" {
+		t.Errorf("generator: got %q, want %q", opts.generator, "Ninja")
+	}
+	if opts.configuration != "Release" {
+		t.Errorf("configuration: got %q, want %q", opts.configuration, "Release")
+	}
+
+	if _, err := parseOptions(CommandBuild, []string{"extra"}); err == nil {
+		t.Error("expected error for positional argument")
+	}
+
+	opts, err = parseOptions(CommandGenerate, []string{"--preset", "dev"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.preset != "dev" {
+		t.Errorf("preset: got %q, want %q", opts.preset, "dev")
+	}
+
+	opts, err = parseOptions(CommandBuild, []string{"--target", "MyPlugin_Standalone"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.target != "MyPlugin_Standalone" {
+		t.Errorf("target: got %q, want %q", opts.target, "MyPlugin_Standalone")
+	}
+}
+
+func TestApplyProjectDefaults(t *testing.T) {
+	settings := config.Resolve(nil, &config.ProjectConfig{Generator: "Ninja", Configuration: "Release"}, nil)
+
+	tests := []struct {
+		name          string
+		opts          options
+		wantGenerator string
+		wantConfig    string
+	}{
+		{"no flags", options{}, "Ninja", "Release"},
+		{"flags win", options{generator: "Xcode", configuration: "Debug"}, "Xcode", "Debug"},
+		{"preset keeps its generator", options{preset: "dev"}, "", "Release"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := applyProjectDefaults(tc.opts, settings)
+			if got.generator != tc.wantGenerator || got.configuration != tc.wantConfig {
+				t.Errorf("got generator %q config %q, want %q %q", got.generator, got.configuration, tc.wantGenerator, tc.wantConfig)
+			}
+		})
+	}
+
+	if got := applyProjectDefaults(options{}, config.Resolve(nil, nil, nil)); got.configuration != "Debug" {
+		t.Errorf("configuration without .cake.toml: got %q, want Debug", got.configuration)
+	}
+}
+
+func TestNewEmitter(t *testing.T) {
+	if _, err := newEmitter(OutputText); err != nil {
+		t.Errorf("text: unexpected error: %v", err)
+	}
+	if _, err := newEmitter(OutputJSON); err != nil {
+		t.Errorf("json: unexpected error: %v", err)
+	}
+	if _, err := newEmitter("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestJSONEmitter_OneEventPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	out := &jsonEmitter{encoder: json.NewEncoder(&buf)}
+
+	out.phaseStarted(PhaseBuild)
+	out.appendLine("[1/2] Building foo.o", ui.TypeStdout)
+	out.replaceLine("[2/2] Linking foo", ui.TypeStdout)
+	out.phaseFinished(PhaseBuild, 2)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	events := make([]Event, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal(line, &events[i]); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if events[i].Time == "" {
+			t.Errorf("line %d: missing time", i)
+		}
+	}
+
+	if events[0].Type != TypePhase || events[0].Event != EventStarted || events[0].Phase != PhaseBuild {
+		t.Errorf("start marker: got %+v", events[0])
+	}
+	if events[1].Type != ui.TypeStdout || events[1].Replace {
+		t.Errorf("append event: got %+v", events[1])
+	}
+	if !events[2].Replace {
+		t.Errorf("replace event: expected Replace=true, got %+v", events[2])
+	}
+	if events[3].Event != EventFinished || events[3].ExitCode == nil || *events[3].ExitCode != 2 {
+		t.Errorf("finish marker: got %+v", events[3])
+	}
+	if events[3].Text != "build finished, exit=2" {
+		t.Errorf("finish text: got %q", events[3].Text)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jrengmusic/cake/internal/ui"
+)
+
+// Output formats selected by --output
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Phase names carried by phase marker events
+const (
+	PhaseConfigure = "configure"
+	PhaseBuild     = "build"
+	PhaseClean     = "clean"
+	PhaseCleanAll  = "clean-all"
+)
+
+// Phase marker event names
+const (
+	EventStarted  = "started"
+	EventFinished = "finished"
+)
+
+// TypePhase marks a phase boundary event; all other events carry a ui.OutputLineType
+const TypePhase ui.OutputLineType = "phase"
+
+// jsonTimeFormat is RFC 3339 with millisecond precision
+const jsonTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Event is one line of --output=json. Output lines carry Type and Text;
+// phase markers carry Type "phase", Phase, Event and — when finished — ExitCode.
+type Event struct {
+	Time     string            `json:"time"`
+	Type     ui.OutputLineType `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	Replace  bool              `json:"replace,omitempty"` // progress update that overwrites the previous line
+	Phase    string            `json:"phase,omitempty"`
+	Event    string            `json:"event,omitempty"`
+	ExitCode *int              `json:"exit,omitempty"`
+}
+
+// emitter receives operation output and phase boundaries for one headless run.
+// Append/replace may be called concurrently from the stdout and stderr readers.
+type emitter interface {
+	appendLine(text string, lineType ui.OutputLineType)
+	replaceLine(text string, lineType ui.OutputLineType)
+	phaseStarted(phase string)
+	phaseFinished(phase string, exitCode int)
+}
+
+// newEmitter returns the emitter for an --output value
+func newEmitter(format string) (emitter, error) {
+	switch format {
+	case OutputText:
+		return textEmitter{}, nil
+	case OutputJSON:
+		return &jsonEmitter{encoder: json.NewEncoder(os.Stdout)}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (use %s or %s)", format, OutputText, OutputJSON)
+	}
+}
+
+// callbacks adapts an emitter to the append/replace callback pair ops functions expect
+func callbacks(out emitter) (func(string, ui.OutputLineType), func(string, ui.OutputLineType)) {
+	return out.appendLine, out.replaceLine
+}
+
+// textEmitter writes plain lines: errors and warnings to stderr, everything else to stdout.
+// Progress replacements are printed as new lines — CI logs cannot overwrite.
+type textEmitter struct{}
+
+func (textEmitter) appendLine(text string, lineType ui.OutputLineType) {
+	fmt.Fprintln(writerFor(lineType), text)
+}
+
+func (textEmitter) replaceLine(text string, lineType ui.OutputLineType) {
+	fmt.Fprintln(writerFor(lineType), text)
+}
+
+func (textEmitter) phaseStarted(phase string) {}
+
+func (textEmitter) phaseFinished(phase string, exitCode int) {}
+
+func writerFor(lineType ui.OutputLineType) io.Writer {
+	switch lineType {
+	case ui.TypeStderr, ui.TypeWarning:
+		return os.Stderr
+	default:
+		return os.Stdout
+	}
+}
+
+// jsonEmitter writes one Event per line to stdout.
+// The mutex keeps lines from the stdout and stderr readers from interleaving.
+type jsonEmitter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (e *jsonEmitter) appendLine(text string, lineType ui.OutputLineType) {
+	e.emit(Event{Type: lineType, Text: text})
+}
+
+func (e *jsonEmitter) replaceLine(text string, lineType ui.OutputLineType) {
+	e.emit(Event{Type: lineType, Text: text, Replace: true})
+}
+
+func (e *jsonEmitter) phaseStarted(phase string) {
+	e.emit(Event{Type: TypePhase, Phase: phase, Event: EventStarted, Text: phase + " started"})
+}
+
+func (e *jsonEmitter) phaseFinished(phase string, exitCode int) {
+	e.emit(Event{
+		Type:     TypePhase,
+		Phase:    phase,
+		Event:    EventFinished,
+		Text:     fmt.Sprintf("%s finished, exit=%d", phase, exitCode),
+		ExitCode: &exitCode,
+	})
+}
+
+func (e *jsonEmitter) emit(event Event) {
+	event.Time = time.Now().Format(jsonTimeFormat)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// discard: stdout write failure leaves nothing actionable for a headless run
+	_ = e.encoder.Encode(event)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/jrengmusic/cake/internal"
 
@@ -15,12 +16,46 @@ type Config struct {
 	AutoScan   AutoScanConfig   `toml:"auto_scan"`
 	Appearance AppearanceConfig `toml:"appearance"`
 	Build      BuildConfig      `toml:"build"`
+	Debug      DebugConfig      `toml:"debug"`
+	Editor     EditorConfig     `toml:"editor"`
 }
 
 // BuildConfig holds build-related settings (last chosen options)
 type BuildConfig struct {
-	LastProject       string `toml:"last_project"`
-	LastConfiguration string `toml:"last_configuration"`
+	LastProject           string                     `toml:"last_project"`
+	LastConfiguration     string                     `toml:"last_configuration"`
+	LastTargets           map[string]string          `toml:"last_targets"`            // Last --target by project root; absent = all targets
+	Generators            map[string]GeneratorConfig `toml:"generators"`              // Named lists of extra configure arguments by generator name
+	ExportCompileCommands bool                       `toml:"export_compile_commands"` // Export compile_commands.json and link it into the project root
+	PTY                   bool                       `toml:"pty"`                     // Run builds on a pseudo-terminal (Linux, macOS)
+	LastRunArgs           map[string][]string        `toml:"last_run_args"`           // Arguments last typed for Run, by project root
+}
+
+// GeneratorConfig holds the named lists of extra configure arguments kept for one generator;
+// only the active list is passed to its configures
+type GeneratorConfig struct {
+	Active string             `toml:"active"` // Name of the list in use; empty = none
+	Lists  map[string]ArgList `toml:"lists"`  // By list name, e.g. "juce" or "qt"
+}
+
+// ArgList is one named list of extra configure arguments
+type ArgList struct {
+	Definitions []string `toml:"definitions"` // NAME[:TYPE]=VALUE, passed as -D
+	Args        []string `toml:"args"`        // Extra cmake arguments, one per entry, passed verbatim
+}
+
+// DebugConfig holds the Debug action's debugger settings (also the [debug] table of .cake.toml)
+type DebugConfig struct {
+	Debugger     string   `toml:"debugger"`      // gdb, lldb or a path; empty = first one installed
+	InitCommands []string `toml:"init_commands"` // Debugger commands run before the prompt, e.g. "break main"
+}
+
+// EditorConfig holds the editor Open Editor and the error list launch
+type EditorConfig struct {
+	Command string            `toml:"command"` // Editor command, may carry arguments ("code --reuse-window"); empty = $VISUAL, $EDITOR, nvim
+	Open    string            `toml:"open"`    // Arguments that open the project, {dir} = project root; empty = "{dir}"
+	Goto    map[string]string `toml:"goto"`    // Arguments that open {file} at {line}:{column}, by editor name; adds to the built-in ones
+	GUI     *bool             `toml:"gui"`     // Start detached in its own window; absent = known GUI editors (code, clion, ...)
 }
 
 // AutoScanConfig holds auto-scan settings
@@ -31,9 +66,16 @@ type AutoScanConfig struct {
 
 // AppearanceConfig holds appearance settings
 type AppearanceConfig struct {
-	Theme string `toml:"theme"`
+	Theme         string `toml:"theme"`
+	ConsoleColors string `toml:"console_colors"` // ConsoleColorsTool or ConsoleColorsTheme; empty = tool
 }
 
+// Console color modes (SSOT)
+const (
+	ConsoleColorsTool  = "tool"  // Keep the colors compilers and CMake write
+	ConsoleColorsTheme = "theme" // Color each line by its type from the theme's Output*Color
+)
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -142,6 +184,20 @@ func (c *Config) SetTheme(theme string) error {
 	return Save(c)
 }
 
+// ConsoleColors returns the console color mode, ConsoleColorsTool unless the theme was chosen
+func (c *Config) ConsoleColors() string {
+	if c.Appearance.ConsoleColors == ConsoleColorsTheme {
+		return ConsoleColorsTheme
+	}
+	return ConsoleColorsTool
+}
+
+// SetConsoleColors updates the console color mode and saves the config
+func (c *Config) SetConsoleColors(mode string) error {
+	c.Appearance.ConsoleColors = mode
+	return Save(c)
+}
+
 // IsAutoScanEnabled returns whether auto-scan is enabled
 func (c *Config) IsAutoScanEnabled() bool {
 	return c.AutoScan.Enabled
@@ -188,3 +244,148 @@ func (c *Config) SetLastConfiguration(configuration string) error {
 	c.Build.LastConfiguration = configuration
 	return Save(c)
 }
+
+// ExportCompileCommands returns whether configures export compile_commands.json
+func (c *Config) ExportCompileCommands() bool {
+	return c.Build.ExportCompileCommands
+}
+
+// SetExportCompileCommands updates the compile_commands.json export setting and saves
+func (c *Config) SetExportCompileCommands(enabled bool) error {
+	c.Build.ExportCompileCommands = enabled
+	return Save(c)
+}
+
+// PTY returns whether builds run on a pseudo-terminal
+func (c *Config) PTY() bool {
+	return c.Build.PTY
+}
+
+// SetPTY updates the pseudo-terminal build setting and saves
+func (c *Config) SetPTY(enabled bool) error {
+	c.Build.PTY = enabled
+	return Save(c)
+}
+
+// LastTarget returns the last chosen build target for the project at projectRoot ("" = all targets)
+func (c *Config) LastTarget(projectRoot string) string {
+	return c.Build.LastTargets[projectRoot]
+}
+
+// SetLastTarget updates the last chosen build target for the project at projectRoot and saves.
+// An empty target removes the entry.
+func (c *Config) SetLastTarget(projectRoot, target string) error {
+	if target == "" {
+		delete(c.Build.LastTargets, projectRoot)
+	} else {
+		if c.Build.LastTargets == nil {
+			c.Build.LastTargets = make(map[string]string)
+		}
+		c.Build.LastTargets[projectRoot] = target
+	}
+	return Save(c)
+}
+
+// LastRunArgs returns the arguments last typed for Run in the project at projectRoot.
+// ok is false when none were typed, so the .cake.toml defaults apply.
+func (c *Config) LastRunArgs(projectRoot string) (args []string, ok bool) {
+	args, ok = c.Build.LastRunArgs[projectRoot]
+	return args, ok
+}
+
+// SetLastRunArgs updates the arguments typed for Run in the project at projectRoot and saves.
+// Empty args are kept: they mean "run without arguments", not "use the defaults".
+func (c *Config) SetLastRunArgs(projectRoot string, args []string) error {
+	if c.Build.LastRunArgs == nil {
+		c.Build.LastRunArgs = make(map[string][]string)
+	}
+	if args == nil {
+		args = []string{}
+	}
+	c.Build.LastRunArgs[projectRoot] = args
+	return Save(c)
+}
+
+// GeneratorArgLists returns the names of generator's argument lists, sorted
+func (c *Config) GeneratorArgLists(generator string) []string {
+	var names []string
+	for name := range c.Build.Generators[generator].Lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveArgList returns the name of the list passed to generator's configures; empty = none
+func (c *Config) ActiveArgList(generator string) string {
+	return c.Build.Generators[generator].Active
+}
+
+// GeneratorArgList returns generator's list called name; empty when there is none
+func (c *Config) GeneratorArgList(generator, name string) ArgList {
+	return c.Build.Generators[generator].Lists[name]
+}
+
+// GeneratorDefinitions returns the cache definitions of generator's active list (NAME[:TYPE]=VALUE)
+func (c *Config) GeneratorDefinitions(generator string) []string {
+	entry := c.Build.Generators[generator]
+	return entry.Lists[entry.Active].Definitions
+}
+
+// GeneratorArgs returns the extra cmake arguments of generator's active list
+func (c *Config) GeneratorArgs(generator string) []string {
+	entry := c.Build.Generators[generator]
+	return entry.Lists[entry.Active].Args
+}
+
+// SetGeneratorArgList creates or replaces generator's list called name and saves.
+// A generator's first list becomes its active one.
+func (c *Config) SetGeneratorArgList(generator, name string, list ArgList) error {
+	if name == "" {
+		return fmt.Errorf("argument list needs a name")
+	}
+	if c.Build.Generators == nil {
+		c.Build.Generators = make(map[string]GeneratorConfig)
+	}
+	entry := c.Build.Generators[generator]
+	if entry.Lists == nil {
+		entry.Lists = make(map[string]ArgList)
+		entry.Active = name
+	}
+	entry.Lists[name] = list
+	c.Build.Generators[generator] = entry
+	return Save(c)
+}
+
+// RemoveGeneratorArgList deletes generator's list called name and saves.
+// Removing the active list leaves none active; removing the last list removes the entry.
+func (c *Config) RemoveGeneratorArgList(generator, name string) error {
+	entry, ok := c.Build.Generators[generator]
+	if !ok {
+		return nil
+	}
+	delete(entry.Lists, name)
+	if entry.Active == name {
+		entry.Active = ""
+	}
+	if len(entry.Lists) == 0 {
+		delete(c.Build.Generators, generator)
+	} else {
+		c.Build.Generators[generator] = entry
+	}
+	return Save(c)
+}
+
+// SetActiveArgList picks the list passed to generator's configures and saves; empty = none
+func (c *Config) SetActiveArgList(generator, name string) error {
+	entry, ok := c.Build.Generators[generator]
+	if _, exists := entry.Lists[name]; name != "" && !exists {
+		return fmt.Errorf("no argument list %q for %s", name, generator)
+	}
+	if !ok {
+		return nil
+	}
+	entry.Active = name
+	c.Build.Generators[generator] = entry
+	return Save(c)
+}
@@ -1,7 +1,13 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jrengmusic/cake/internal"
 )
@@ -146,3 +152,305 @@ func TestLastConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestLastTarget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // SetLastTarget saves to ~/.config/cake/config.toml
+
+	cfg := DefaultConfig()
+	if got := cfg.LastTarget("/src/a"); got != "" {
+		t.Errorf("expected empty target, got %q", got)
+	}
+
+	if err := cfg.SetLastTarget("/src/a", "MyPlugin_Standalone"); err != nil {
+		t.Fatalf("SetLastTarget: %v", err)
+	}
+	if err := cfg.SetLastTarget("/src/b", "tests"); err != nil {
+		t.Fatalf("SetLastTarget: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded.LastTarget("/src/a"); got != "MyPlugin_Standalone" {
+		t.Errorf("project a: expected %q, got %q", "MyPlugin_Standalone", got)
+	}
+	if got := loaded.LastTarget("/src/b"); got != "tests" {
+		t.Errorf("project b: expected %q, got %q", "tests", got)
+	}
+
+	if err := loaded.SetLastTarget("/src/a", ""); err != nil {
+		t.Fatalf("SetLastTarget: %v", err)
+	}
+	if _, ok := loaded.Build.LastTargets["/src/a"]; ok {
+		t.Error("empty target must remove the entry")
+	}
+}
+
+func TestGeneratorArgLists(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // SetGeneratorArgList saves to ~/.config/cake/config.toml
+
+	cfg := DefaultConfig()
+	if got := cfg.GeneratorDefinitions("Ninja"); len(got) != 0 {
+		t.Errorf("expected no definitions, got %v", got)
+	}
+
+	juce := ArgList{
+		Definitions: []string{"JUCE_COPY_PLUGIN_AFTER_BUILD=ON"},
+		Args:        []string{"--log-level=VERBOSE"},
+	}
+	qt := ArgList{Definitions: []string{"CMAKE_PREFIX_PATH:PATH=/opt/qt"}}
+	if err := cfg.SetGeneratorArgList("Ninja", "juce", juce); err != nil {
+		t.Fatalf("SetGeneratorArgList: %v", err)
+	}
+	if err := cfg.SetGeneratorArgList("Ninja", "qt", qt); err != nil {
+		t.Fatalf("SetGeneratorArgList: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := strings.Join(loaded.GeneratorArgLists("Ninja"), " "); got != "juce qt" {
+		t.Errorf("lists: got %q", got)
+	}
+	// The first list is the one in use
+	if loaded.ActiveArgList("Ninja") != "juce" {
+		t.Errorf("active: got %q, want juce", loaded.ActiveArgList("Ninja"))
+	}
+	if got := loaded.GeneratorDefinitions("Ninja"); len(got) != 1 || got[0] != "JUCE_COPY_PLUGIN_AFTER_BUILD=ON" {
+		t.Errorf("definitions: got %v", got)
+	}
+	if got := loaded.GeneratorArgs("Ninja"); len(got) != 1 || got[0] != "--log-level=VERBOSE" {
+		t.Errorf("args: got %v", got)
+	}
+	if got := loaded.GeneratorArgs("Xcode"); len(got) != 0 {
+		t.Errorf("other generators must be unaffected, got %v", got)
+	}
+
+	if err := loaded.SetActiveArgList("Ninja", "qt"); err != nil {
+		t.Fatalf("SetActiveArgList: %v", err)
+	}
+	if got := loaded.GeneratorDefinitions("Ninja"); len(got) != 1 || got[0] != "CMAKE_PREFIX_PATH:PATH=/opt/qt" {
+		t.Errorf("definitions after switching: got %v", got)
+	}
+	if err := loaded.SetActiveArgList("Ninja", "missing"); err == nil {
+		t.Error("expected an error for an unknown list")
+	}
+
+	if err := loaded.RemoveGeneratorArgList("Ninja", "qt"); err != nil {
+		t.Fatalf("RemoveGeneratorArgList: %v", err)
+	}
+	if loaded.ActiveArgList("Ninja") != "" || len(loaded.GeneratorDefinitions("Ninja")) != 0 {
+		t.Error("removing the list in use must leave none in use")
+	}
+	if err := loaded.RemoveGeneratorArgList("Ninja", "juce"); err != nil {
+		t.Fatalf("RemoveGeneratorArgList: %v", err)
+	}
+	if _, ok := loaded.Build.Generators["Ninja"]; ok {
+		t.Error("removing the last list must remove the entry")
+	}
+}
+
+func TestLoadProjectConfig(t *testing.T) {
+	t.Run("absent", func(t *testing.T) {
+		projectConfig, err := LoadProjectConfig(t.TempDir())
+		if projectConfig != nil || err != nil {
+			t.Errorf("expected nil, nil for a project without %s, got %v, %v", internal.ProjectConfigFile, projectConfig, err)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		root := t.TempDir()
+		data := `generator = "Ninja"
+configuration = "Release"
+definitions = ["JUCE_COPY_PLUGIN:BOOL=OFF", "FOO=bar"]
+jobs = 8
+
+[env]
+CC = "clang"
+`
+		if err := os.WriteFile(filepath.Join(root, internal.ProjectConfigFile), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		projectConfig, err := LoadProjectConfig(root)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if projectConfig.Generator != "Ninja" || projectConfig.Configuration != "Release" || projectConfig.Jobs != 8 {
+			t.Errorf("unexpected config: %+v", projectConfig)
+		}
+		if len(projectConfig.Definitions) != 2 || projectConfig.Env["CC"] != "clang" {
+			t.Errorf("unexpected definitions/env: %v, %v", projectConfig.Definitions, projectConfig.Env)
+		}
+	})
+
+	invalid := map[string]string{
+		"malformed":      "generator = ",
+		"negative jobs":  "jobs = -1",
+		"negative delay": "[watch]\ndelay_ms = -5",
+		"negative poll":  "[watch]\npoll_ms = -1",
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, internal.ProjectConfigFile), []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadProjectConfig(root); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	user := &Config{Build: BuildConfig{LastProject: "Xcode", LastConfiguration: internal.ConfigRelease}}
+	project := &ProjectConfig{
+		Generator:   "Ninja",
+		Definitions: []string{"FOO=bar"},
+		Env:         map[string]string{"CC": "clang", "AR": "llvm-ar"},
+		Jobs:        4,
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		effective := Resolve(nil, nil, nil)
+		if effective.Generator != "" || effective.Configuration != internal.ConfigDebug {
+			t.Errorf("unexpected defaults: %+v", effective)
+		}
+		if got := effective.Source(SettingConfiguration); got != SourceDefault {
+			t.Errorf("expected source %q, got %q", SourceDefault, got)
+		}
+	})
+
+	t.Run("project beats user", func(t *testing.T) {
+		effective := Resolve(user, project, nil)
+		if effective.Generator != "Ninja" || effective.Source(SettingGenerator) != SourceProject {
+			t.Errorf("expected generator Ninja from %s, got %q from %q", SourceProject, effective.Generator, effective.Source(SettingGenerator))
+		}
+		// Not set in .cake.toml: the user value stays
+		if effective.Configuration != internal.ConfigRelease || effective.Source(SettingConfiguration) != SourceUser {
+			t.Errorf("expected configuration Release from %s, got %q from %q", SourceUser, effective.Configuration, effective.Source(SettingConfiguration))
+		}
+		if effective.Jobs != 4 || effective.Source(SettingJobs) != SourceProject {
+			t.Errorf("expected jobs 4 from %s, got %d", SourceProject, effective.Jobs)
+		}
+		if got := strings.Join(effective.EnvList(), " "); got != "AR=llvm-ar CC=clang" {
+			t.Errorf("expected sorted env list, got %q", got)
+		}
+	})
+
+	t.Run("compile commands", func(t *testing.T) {
+		enabled := &Config{Build: BuildConfig{ExportCompileCommands: true}}
+		if effective := Resolve(enabled, nil, nil); !effective.CompileCommands || effective.Source(SettingCompileCommands) != SourceUser {
+			t.Errorf("expected compile commands on from %s, got %+v", SourceUser, effective)
+		}
+		off := false
+		effective := Resolve(enabled, &ProjectConfig{CompileCommands: &off}, nil)
+		if effective.CompileCommands || effective.Source(SettingCompileCommands) != SourceProject {
+			t.Errorf("expected .cake.toml to turn compile commands off, got %+v", effective)
+		}
+	})
+
+	t.Run("pty", func(t *testing.T) {
+		if effective := Resolve(user, nil, nil); effective.PTY || effective.Source(SettingPTY) != SourceDefault {
+			t.Errorf("expected pipes by default, got %+v", effective)
+		}
+		on := true
+		effective := Resolve(user, &ProjectConfig{PTY: &on}, nil)
+		if !effective.PTY || effective.Source(SettingPTY) != SourceProject {
+			t.Errorf("expected .cake.toml to turn the pty on, got %+v", effective)
+		}
+	})
+
+	t.Run("run", func(t *testing.T) {
+		if effective := Resolve(user, project, nil); effective.Source(SettingRun) != SourceDefault {
+			t.Errorf("expected no run settings, got %+v", effective.Run)
+		}
+		withRun := &ProjectConfig{Run: RunConfig{Args: []string{"--fast"}, Env: map[string]string{"B": "2", "A": "1"}}}
+		effective := Resolve(user, withRun, nil)
+		if effective.Source(SettingRun) != SourceProject || len(effective.Run.Args) != 1 {
+			t.Errorf("expected [run] from %s, got %+v", SourceProject, effective.Run)
+		}
+		if got := strings.Join(effective.Run.EnvList(), " "); got != "A=1 B=2" {
+			t.Errorf("expected sorted run env, got %q", got)
+		}
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		withDebugger := &Config{Debug: DebugConfig{Debugger: "lldb", InitCommands: []string{"settings set target.x86-disassembly-flavor intel"}}}
+		effective := Resolve(withDebugger, &ProjectConfig{Debug: DebugConfig{InitCommands: []string{"break main"}}}, nil)
+		if effective.Debug.Debugger != "lldb" {
+			t.Errorf("user debugger must stay when .cake.toml sets none, got %q", effective.Debug.Debugger)
+		}
+		if len(effective.Debug.InitCommands) != 1 || effective.Debug.InitCommands[0] != "break main" || effective.Source(SettingDebug) != SourceProject {
+			t.Errorf("expected init commands from %s, got %+v", SourceProject, effective.Debug)
+		}
+	})
+
+	t.Run("invalid project ignored", func(t *testing.T) {
+		effective := Resolve(user, nil, errors.New("failed to parse"))
+		if effective.Generator != "Xcode" || effective.ProjectError == "" {
+			t.Errorf("expected user generator and a project error, got %+v", effective)
+		}
+	})
+}
+
+func TestHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // AppendHistory saves to ~/.config/cake/history.toml
+
+	history, err := LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(history.Entries) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(history.Entries))
+	}
+
+	start := time.Date(2026, 10, 17, 14, 23, 5, 0, time.UTC)
+	for i := 0; i < HistoryMaxEntries+2; i++ {
+		project := "/src/a"
+		if i%2 == 1 {
+			project = "/src/b"
+		}
+		entry := HistoryEntry{
+			Project:    project,
+			Operation:  "build",
+			Generator:  "Ninja",
+			Start:      start.Add(time.Duration(i) * time.Minute),
+			DurationMs: 1500,
+			Result:     HistoryResultFailed,
+			ExitCode:   i,
+			Log:        fmt.Sprintf("/src/a/Builds/Ninja/.cake/logs/%03d-build.log", i),
+		}
+		if err := AppendHistory(entry); err != nil {
+			t.Fatalf("AppendHistory: %v", err)
+		}
+	}
+
+	loaded, err := LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(loaded.Entries) != HistoryMaxEntries {
+		t.Fatalf("expected history capped at %d, got %d", HistoryMaxEntries, len(loaded.Entries))
+	}
+	if loaded.Entries[0].ExitCode != 2 {
+		t.Errorf("expected the two oldest entries dropped, first exit code %d", loaded.Entries[0].ExitCode)
+	}
+
+	entries := loaded.ForProject("/src/a")
+	if len(entries) != HistoryMaxEntries/2 {
+		t.Fatalf("project a: expected %d entries, got %d", HistoryMaxEntries/2, len(entries))
+	}
+	newest := entries[0]
+	if newest.ExitCode != HistoryMaxEntries {
+		t.Errorf("expected newest entry first, got exit code %d", newest.ExitCode)
+	}
+	if !newest.Start.Equal(start.Add(HistoryMaxEntries * time.Minute)) {
+		t.Errorf("start: got %v", newest.Start)
+	}
+	if newest.Duration() != 1500*time.Millisecond {
+		t.Errorf("duration: got %v", newest.Duration())
+	}
+}
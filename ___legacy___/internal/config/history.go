@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// HistoryMaxEntries caps the history index; the oldest entries are dropped first
+const HistoryMaxEntries = 200
+
+// Operation results recorded in the history
+const (
+	HistoryResultSuccess = "success"
+	HistoryResultFailed  = "failed"
+	HistoryResultAborted = "aborted"
+)
+
+// HistoryEntry is one finished operation in the history index
+type HistoryEntry struct {
+	Project       string    `toml:"project"`       // Absolute project root
+	Operation     string    `toml:"operation"`     // "build", "generate", "test", "run", "clean", "clean all" or "regenerate"
+	Generator     string    `toml:"generator"`     // Selected generator
+	Preset        string    `toml:"preset"`        // Configure preset; empty = cake's own command line
+	Configuration string    `toml:"configuration"` // Selected configuration
+	Start         time.Time `toml:"start"`
+	DurationMs    int64     `toml:"duration_ms"`
+	Result        string    `toml:"result"`    // HistoryResultSuccess, HistoryResultFailed or HistoryResultAborted
+	ExitCode      int       `toml:"exit_code"` // Exit code of a failed tool; 0 otherwise
+	Log           string    `toml:"log"`       // Absolute path of the saved console log; empty when none was written
+}
+
+// Duration returns how long the operation ran
+func (e HistoryEntry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// History is the operation history index, oldest entry first
+type History struct {
+	Entries []HistoryEntry `toml:"entry"`
+}
+
+// GetHistoryPath returns the path to the operation history index
+func GetHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".cake/history.toml"
+	}
+	return filepath.Join(home, ".config", "cake", "history.toml")
+}
+
+// LoadHistory reads the history index; a missing index is an empty history
+func LoadHistory() (*History, error) {
+	data, err := os.ReadFile(GetHistoryPath())
+	if os.IsNotExist(err) {
+		return &History{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	history := &History{}
+	if err := toml.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse history: %w", err)
+	}
+	return history, nil
+}
+
+// SaveHistory writes the history index
+func SaveHistory(history *History) error {
+	historyPath := GetHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := toml.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+	if err := os.WriteFile(historyPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// AppendHistory adds entry to the history index, dropping the oldest entries past HistoryMaxEntries
+func AppendHistory(entry HistoryEntry) error {
+	history, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+	history.Entries = append(history.Entries, entry)
+	if excess := len(history.Entries) - HistoryMaxEntries; excess > 0 {
+		history.Entries = history.Entries[excess:]
+	}
+	return SaveHistory(history)
+}
+
+// ForProject returns the entries of projectRoot, newest first
+func (h *History) ForProject(projectRoot string) []HistoryEntry {
+	var entries []HistoryEntry
+	for i := len(h.Entries) - 1; i >= 0; i-- {
+		if h.Entries[i].Project == projectRoot {
+			entries = append(entries, h.Entries[i])
+		}
+	}
+	return entries
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jrengmusic/cake/internal"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Setting sources, lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceUser    = "user"
+	SourceProject = internal.ProjectConfigFile
+)
+
+// ProjectConfig is the project-local .cake.toml in the project root.
+// It is meant to be committed, and takes precedence over the user config.
+type ProjectConfig struct {
+	Generator       string            `toml:"generator"`        // Default generator, e.g. "Ninja"
+	Configuration   string            `toml:"configuration"`    // Default configuration, e.g. "Release"
+	Definitions     []string          `toml:"definitions"`      // Extra cache definitions, NAME[:TYPE]=VALUE (passed as -D)
+	Env             map[string]string `toml:"env"`              // Environment for cmake, the build tool and ctest
+	Jobs            int               `toml:"jobs"`             // Parallel build jobs; 0 = build tool default
+	CompileCommands *bool             `toml:"compile_commands"` // Export and link compile_commands.json; absent = user setting
+	PTY             *bool             `toml:"pty"`              // Run builds on a pseudo-terminal; absent = user setting
+	Run             RunConfig         `toml:"run"`              // How Run launches the built executable
+	Debug           DebugConfig       `toml:"debug"`            // Debugger for the Debug action
+	Watch           WatchConfig       `toml:"watch"`            // What watch mode skips and how long it waits
+}
+
+// RunConfig is the [run] table of .cake.toml
+type RunConfig struct {
+	Target string            `toml:"target"` // Executable target to run; empty = Target row, else the first executable
+	Args   []string          `toml:"args"`   // Default arguments, one per entry
+	Cwd    string            `toml:"cwd"`    // Working directory, relative to the project root; empty = the executable's directory
+	Env    map[string]string `toml:"env"`    // Environment on top of the [env] table
+}
+
+// IsEmpty reports whether no [run] setting is given
+func (r RunConfig) IsEmpty() bool {
+	return r.Target == "" && len(r.Args) == 0 && r.Cwd == "" && len(r.Env) == 0
+}
+
+// EnvList returns the run environment as sorted NAME=VALUE entries
+func (r RunConfig) EnvList() []string {
+	return envList(r.Env)
+}
+
+// WatchConfig is the [watch] table of .cake.toml
+type WatchConfig struct {
+	Ignore  []string `toml:"ignore"`   // Patterns left out of watching, on top of Builds/ and hidden entries
+	DelayMs int      `toml:"delay_ms"` // Quiet time before a change is built; 0 = default
+	PollMs  int      `toml:"poll_ms"`  // Interval between source scans; 0 = default
+}
+
+// GetProjectConfigPath returns the path of projectRoot's .cake.toml
+func GetProjectConfigPath(projectRoot string) string {
+	return filepath.Join(projectRoot, internal.ProjectConfigFile)
+}
+
+// LoadProjectConfig reads projectRoot's .cake.toml. Returns nil, nil when the project has none.
+func LoadProjectConfig(projectRoot string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(GetProjectConfigPath(projectRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", internal.ProjectConfigFile, err)
+	}
+
+	var projectConfig ProjectConfig
+	if err := toml.Unmarshal(data, &projectConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", internal.ProjectConfigFile, err)
+	}
+	if projectConfig.Jobs < 0 {
+		return nil, fmt.Errorf("invalid %s: jobs must not be negative", internal.ProjectConfigFile)
+	}
+	if projectConfig.Watch.DelayMs < 0 {
+		return nil, fmt.Errorf("invalid %s: watch delay_ms must not be negative", internal.ProjectConfigFile)
+	}
+	if projectConfig.Watch.PollMs < 0 {
+		return nil, fmt.Errorf("invalid %s: watch poll_ms must not be negative", internal.ProjectConfigFile)
+	}
+	return &projectConfig, nil
+}
+
+// Setting keys, as written in .cake.toml
+const (
+	SettingGenerator       = "generator"
+	SettingConfiguration   = "configuration"
+	SettingDefinitions     = "definitions"
+	SettingEnv             = "env"
+	SettingJobs            = "jobs"
+	SettingCompileCommands = "compile_commands"
+	SettingPTY             = "pty"
+	SettingRun             = "run"
+	SettingDebug           = "debug"
+	SettingWatch           = "watch"
+)
+
+// Effective is the merged view of defaults, the user config and the project's .cake.toml
+type Effective struct {
+	Generator       string // Empty = first available generator
+	Configuration   string
+	Definitions     []string
+	Env             map[string]string
+	Jobs            int               // 0 = build tool default
+	CompileCommands bool              // Export compile_commands.json and link it into the project root
+	PTY             bool              // Run builds on a pseudo-terminal (TUI only, Linux and macOS)
+	Run             RunConfig         // .cake.toml [run]; typed Run arguments replace Run.Args
+	Debug           DebugConfig       // [debug]: each .cake.toml field beats the user one
+	Watch           WatchConfig       // .cake.toml [watch]
+	Sources         map[string]string // Setting key -> SourceUser or SourceProject; absent = SourceDefault
+	ProjectError    string            // Why .cake.toml was ignored, if it was
+}
+
+// Resolve merges the settings: .cake.toml beats the user config, which beats the defaults.
+// user and project may be nil; projectErr is the LoadProjectConfig error, if any.
+func Resolve(user *Config, project *ProjectConfig, projectErr error) Effective {
+	effective := Effective{
+		Configuration: internal.ConfigDebug,
+		Sources:       map[string]string{},
+	}
+
+	if user != nil {
+		if user.Build.LastProject != "" {
+			effective.Generator = user.Build.LastProject
+			effective.Sources[SettingGenerator] = SourceUser
+		}
+		if user.Build.LastConfiguration != "" {
+			effective.Configuration = user.Build.LastConfiguration
+			effective.Sources[SettingConfiguration] = SourceUser
+		}
+		if user.Build.ExportCompileCommands {
+			effective.CompileCommands = true
+			effective.Sources[SettingCompileCommands] = SourceUser
+		}
+		if user.Build.PTY {
+			effective.PTY = true
+			effective.Sources[SettingPTY] = SourceUser
+		}
+		if user.Debug.Debugger != "" || len(user.Debug.InitCommands) > 0 {
+			effective.Debug = user.Debug
+			effective.Sources[SettingDebug] = SourceUser
+		}
+	}
+
+	if projectErr != nil {
+		effective.ProjectError = projectErr.Error()
+		return effective
+	}
+	if project == nil {
+		return effective
+	}
+
+	if project.Generator != "" {
+		effective.Generator = project.Generator
+		effective.Sources[SettingGenerator] = SourceProject
+	}
+	if project.Configuration != "" {
+		effective.Configuration = project.Configuration
+		effective.Sources[SettingConfiguration] = SourceProject
+	}
+	if len(project.Definitions) > 0 {
+		effective.Definitions = project.Definitions
+		effective.Sources[SettingDefinitions] = SourceProject
+	}
+	if len(project.Env) > 0 {
+		effective.Env = project.Env
+		effective.Sources[SettingEnv] = SourceProject
+	}
+	if project.Jobs > 0 {
+		effective.Jobs = project.Jobs
+		effective.Sources[SettingJobs] = SourceProject
+	}
+	if project.CompileCommands != nil {
+		effective.CompileCommands = *project.CompileCommands
+		effective.Sources[SettingCompileCommands] = SourceProject
+	}
+	if project.PTY != nil {
+		effective.PTY = *project.PTY
+		effective.Sources[SettingPTY] = SourceProject
+	}
+	if !project.Run.IsEmpty() {
+		effective.Run = project.Run
+		effective.Sources[SettingRun] = SourceProject
+	}
+	if project.Debug.Debugger != "" {
+		effective.Debug.Debugger = project.Debug.Debugger
+		effective.Sources[SettingDebug] = SourceProject
+	}
+	if len(project.Debug.InitCommands) > 0 {
+		effective.Debug.InitCommands = project.Debug.InitCommands
+		effective.Sources[SettingDebug] = SourceProject
+	}
+	if len(project.Watch.Ignore) > 0 || project.Watch.DelayMs > 0 || project.Watch.PollMs > 0 {
+		effective.Watch = project.Watch
+		effective.Sources[SettingWatch] = SourceProject
+	}
+	return effective
+}
+
+// Source returns where the effective value of setting came from
+func (e Effective) Source(setting string) string {
+	if source, ok := e.Sources[setting]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// EnvList returns the environment overrides as sorted NAME=VALUE entries
+func (e Effective) EnvList() []string {
+	return envList(e.Env)
+}
+
+func envList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for name, value := range env {
+		list = append(list, name+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -24,8 +24,12 @@ func init() {
 
 // Filesystem names (SSOT)
 const (
-	BuildsDirName  = "Builds"          // Root directory for all build artifacts
-	CMakeListsFile = "CMakeLists.txt"  // CMake project definition file
+	BuildsDirName       = "Builds"                // Root directory for all build artifacts
+	CMakeListsFile      = "CMakeLists.txt"        // CMake project definition file
+	ProjectConfigFile   = ".cake.toml"            // Per-project settings, committed with the repo
+	CompileCommandsFile = "compile_commands.json" // Compilation database for clangd and other tools
+	QuickfixFile        = "errors.err"            // Vim errorfile of the last build's diagnostics, in the build tree
+	LogsDirName         = ".cake/logs"            // Operation logs, in the build tree
 )
 
 // Build configuration names (SSOT)
@@ -2,29 +2,66 @@ package ops
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
-	"path/filepath"
+	"strconv"
 
-	"github.com/jrengmusic/cake/internal"
 	"github.com/jrengmusic/cake/internal/ui"
 	"github.com/jrengmusic/cake/internal/utils"
 )
 
 type BuildResult struct {
-	Success  bool
-	ExitCode int
-	Error    string
+	Success     bool
+	ExitCode    int
+	Error       string
+	Diagnostics []utils.Diagnostic // Compiler and linker errors and warnings, in output order
 }
 
-func ExecuteBuildProject(ctx context.Context, generator, config, projectRoot string, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) BuildResult {
-	buildDir := filepath.Join(projectRoot, internal.BuildsDirName, utils.GetDirectoryName(generator))
+// diagnosticsCallback wraps appendCallback so every streamed line is also parsed for diagnostics
+func diagnosticsCallback(appendCallback func(string, ui.OutputLineType)) (func(string, ui.OutputLineType), *utils.DiagnosticCollector) {
+	collector := utils.NewDiagnosticCollector()
+	return func(line string, lineType ui.OutputLineType) {
+		collector.Add(line)
+		appendCallback(line, lineType)
+	}, collector
+}
+
+// streamBuild streams cmd on a pseudo-terminal when usePTY is set and the platform has one, else on pipes
+func streamBuild(cmd *exec.Cmd, usePTY bool, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) (*utils.ProcessTree, error) {
+	if usePTY && utils.PTYSupported {
+		return utils.StreamCommandPTY(cmd, appendCallback, replaceCallback, onProcessTreeStarted)
+	}
+	return utils.StreamCommand(cmd, appendCallback, replaceCallback, onProcessTreeStarted)
+}
 
-	args := []string{"--build", buildDir, "--config", config}
+// ExecuteBuildProject builds Builds/<Generator>/ (or the variant's tree) with `cmake --build`.
+// target is passed as --target; empty builds everything. jobs > 0 is passed as --parallel.
+// usePTY runs the build on a pseudo-terminal (see utils.StreamCommandPTY).
+func ExecuteBuildProject(ctx context.Context, generator, config, variant, target, projectRoot string, jobs int, usePTY bool, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) BuildResult {
+	buildDir := utils.GetBuildDirectory(projectRoot, generator, config, variant)
+
+	args := []string{"--build", buildDir}
+	if utils.IsGeneratorMultiConfig(generator) {
+		// Single-config trees are fixed at configure time; --config would be ignored
+		args = append(args, "--config", config)
+	}
+	if target != "" {
+		args = append(args, "--target", target)
+	}
+	if jobs > 0 {
+		args = append(args, "--parallel", strconv.Itoa(jobs))
+	}
 
 	appendCallback("Building: "+buildDir, ui.TypeInfo)
 	appendCallback("Project: "+generator, ui.TypeInfo)
 	appendCallback("Configuration: "+config, ui.TypeInfo)
+	if variant != "" && variant != utils.VariantDefault {
+		appendCallback("Variant: "+variant, ui.TypeInfo)
+	}
+	if target != "" {
+		appendCallback("Target: "+target, ui.TypeInfo)
+	}
 	appendCallback("", ui.TypeStdout)
 
 	cmakePath := utils.FindExecutableInEnv("cmake", vsEnv)
@@ -34,7 +71,8 @@ func ExecuteBuildProject(ctx context.Context, generator, config, projectRoot str
 		cmd.Env = vsEnv
 	}
 
-	tree, streamErr := utils.StreamCommand(cmd, appendCallback, replaceCallback, onProcessTreeStarted)
+	collectCallback, diagnostics := diagnosticsCallback(appendCallback)
+	tree, streamErr := streamBuild(cmd, usePTY, collectCallback, replaceCallback, onProcessTreeStarted)
 
 	result := BuildResult{Success: false}
 	if streamErr != nil {
@@ -51,6 +89,7 @@ func ExecuteBuildProject(ctx context.Context, generator, config, projectRoot str
 		} else if waitErr != nil {
 			appendCallback("", ui.TypeStdout)
 			appendCallback("ERROR: Build failed", ui.TypeStderr)
+			result.ExitCode = exitCodeOf(waitErr)
 			result.Error = fmt.Errorf("ExecuteBuildProject: cmake --build failed: %w", waitErr).Error()
 		} else {
 			appendCallback("", ui.TypeStdout)
@@ -60,5 +99,20 @@ func ExecuteBuildProject(ctx context.Context, generator, config, projectRoot str
 		}
 	}
 
+	result.Diagnostics = diagnostics.Diagnostics()
 	return result
 }
+
+// exitCodeOf extracts the process exit code from a cmd.Wait error.
+// Errors that are not exit statuses (I/O, signals) map to 1 so callers never report success.
+func exitCodeOf(waitErr error) int {
+	code := 0
+	if waitErr != nil {
+		code = 1
+		var exitErr *exec.ExitError
+		if errors.As(waitErr, &exitErr) && exitErr.ExitCode() > 0 {
+			code = exitErr.ExitCode()
+		}
+	}
+	return code
+}
@@ -13,14 +13,16 @@ type CleanResult struct {
 	Error   string
 }
 
-func ExecuteCleanProject(generator, config, projectRoot string, outputCallback func(string, ui.OutputLineType)) CleanResult {
-	buildDir := filepath.Join(projectRoot, internal.BuildsDirName, utils.GetDirectoryName(generator))
+func ExecuteCleanProject(generator, config, variant, projectRoot string, outputCallback func(string, ui.OutputLineType)) CleanResult {
+	return ExecuteCleanDirectory(utils.GetBuildDirectory(projectRoot, generator, config, variant), outputCallback)
+}
 
+// ExecuteCleanDirectory removes one build directory (e.g. a preset's binaryDir outside Builds/)
+func ExecuteCleanDirectory(buildDir string, outputCallback func(string, ui.OutputLineType)) CleanResult {
 	outputCallback("Cleaning...", ui.TypeInfo)
 
 	if _, err := os.Stat(buildDir); os.IsNotExist(err) {
 		outputCallback("Project directory clean.", ui.TypeStatus)
-		outputCallback("Press ESC to return to menu", ui.TypeInfo)
 		return CleanResult{Success: true}
 	}
 
@@ -33,6 +35,28 @@ func ExecuteCleanProject(generator, config, projectRoot string, outputCallback f
 
 	outputCallback("ok", ui.TypeStatus)
 	outputCallback("Project directory clean.", ui.TypeStatus)
-	outputCallback("Press ESC to return to menu", ui.TypeInfo)
+	return CleanResult{Success: true}
+}
+
+// ExecuteCleanAllProject removes the entire Builds/ directory (all generators, all configurations)
+func ExecuteCleanAllProject(projectRoot string, outputCallback func(string, ui.OutputLineType)) CleanResult {
+	buildsDir := filepath.Join(projectRoot, internal.BuildsDirName)
+
+	outputCallback("", ui.TypeStdout)
+	outputCallback("Cleaning all projects...", ui.TypeInfo)
+	outputCallback("Target: "+buildsDir, ui.TypeStdout)
+	outputCallback("", ui.TypeStdout)
+
+	// The root compile_commands.json would point into the removed trees
+	if err := utils.UnlinkCompileCommands(projectRoot); err != nil {
+		outputCallback("WARNING: "+err.Error(), ui.TypeWarning)
+	}
+
+	if err := os.RemoveAll(buildsDir); err != nil {
+		outputCallback("Error: Failed to remove Builds directory: "+err.Error(), ui.TypeStderr)
+		return CleanResult{Success: false, Error: err.Error()}
+	}
+
+	outputCallback("✓ All build artifacts removed successfully", ui.TypeInfo)
 	return CleanResult{Success: true}
 }
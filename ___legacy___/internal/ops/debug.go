@@ -0,0 +1,24 @@
+package ops
+
+import (
+	"os/exec"
+
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+// DebugCommand returns the debugger command for executable with args: `gdb --args` or
+// `lldb --`, with initCommands run first. debugger empty picks the first installed one.
+// The caller runs it on the terminal (tea.ExecProcess), so stdio is left unset.
+func DebugCommand(debugger string, initCommands []string, executable string, args []string, workingDir string, env []string) (*exec.Cmd, error) {
+	debuggerPath, err := utils.FindDebugger(debugger)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(debuggerPath, utils.DebuggerArgs(debuggerPath, initCommands, executable, args)...)
+	cmd.Dir = workingDir
+	if len(env) > 0 {
+		cmd.Env = env
+	}
+	return cmd, nil
+}
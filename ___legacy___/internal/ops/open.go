@@ -1,7 +1,8 @@
 package ops
 
 import (
-	"github.com/jrengmusic/cake/internal"
+	"fmt"
+
 	"github.com/jrengmusic/cake/internal/ui"
 	"github.com/jrengmusic/cake/internal/utils"
 	"os"
@@ -16,13 +17,11 @@ type OpenResult struct {
 }
 
 // ExecuteOpenIDE opens the IDE project for the given build directory
-func ExecuteOpenIDE(generator, config, projectRoot string, outputCallback func(string, ui.OutputLineType)) OpenResult {
-	buildDir := filepath.Join(projectRoot, internal.BuildsDirName, utils.GetDirectoryName(generator))
-
+func ExecuteOpenIDE(generator, buildDir string, outputCallback func(string, ui.OutputLineType)) OpenResult {
 	var projectFile string
 
-	switch generator {
-	case "Xcode":
+	switch {
+	case generator == utils.GeneratorXcode:
 		projectFile = findXcodeProject(buildDir)
 		if projectFile == "" {
 			return OpenResult{Success: false, Error: "No Xcode project found"}
@@ -34,7 +33,7 @@ func ExecuteOpenIDE(generator, config, projectRoot string, outputCallback func(s
 		}
 		return OpenResult{Success: true}
 
-	case utils.GeneratorVS2026, utils.GeneratorVS2022:
+	case utils.IsGeneratorVS(generator):
 		projectFile = findVisualStudioSolution(buildDir)
 		if projectFile == "" {
 			return OpenResult{Success: false, Error: "No Visual Studio solution found"}
@@ -51,20 +50,72 @@ func ExecuteOpenIDE(generator, config, projectRoot string, outputCallback func(s
 	}
 }
 
-// ExecuteOpenEditor opens the editor (Neovim) in the build directory
-func ExecuteOpenEditor(buildDir string, outputCallback func(string, ui.OutputLineType)) OpenResult {
-	cmd := exec.Command("nvim", ".")
-	cmd.Dir = buildDir
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// EditorOpenCommand returns the command that opens the project root in editor, with the
+// arguments from template ({dir}; empty = the directory alone). The caller either hands it
+// the terminal (tea.ExecProcess) or starts it detached, so stdio is left unset.
+func EditorOpenCommand(editor, template, projectRoot string) (*exec.Cmd, error) {
+	executable, args, err := editorExecutable(editor)
+	if err != nil {
+		return nil, err
+	}
+	openArgs, err := utils.EditorOpenArgs(template, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(executable, append(args, openArgs...)...)
+	cmd.Dir = projectRoot
+	return cmd, nil
+}
+
+// EditorGotoCommand returns the command that opens file at line:column in editor, with the
+// arguments from templates (by editor name) or the built-in ones. The caller runs it on
+// the terminal (tea.ExecProcess) or starts it detached, so stdio is left unset.
+func EditorGotoCommand(editor string, templates map[string]string, file string, line, column int, workingDir string) (*exec.Cmd, error) {
+	executable, args, err := editorExecutable(editor)
+	if err != nil {
+		return nil, err
+	}
+	gotoArgs, err := utils.EditorGotoArgs(executable, templates, file, line, column)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(executable, append(args, gotoArgs...)...)
+	cmd.Dir = workingDir
+	return cmd, nil
+}
+
+// EditorQuickfixCommand returns `editor -q errorfile`, which loads every diagnostic into
+// vim's quickfix list and jumps to the first one. Only vim-family editors take it.
+func EditorQuickfixCommand(editor, errorfile, workingDir string) (*exec.Cmd, error) {
+	executable, args, err := editorExecutable(editor)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.IsVimEditor(executable) {
+		return nil, fmt.Errorf("%s does not read a vim errorfile", utils.EditorName(executable))
+	}
 
-	outputCallback("Opening Neovim in: "+buildDir, ui.TypeInfo)
+	cmd := exec.Command(executable, append(args, "-q", errorfile)...)
+	cmd.Dir = workingDir
+	return cmd, nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return OpenResult{Success: false, Error: err.Error()}
+// editorExecutable splits an editor command into the executable's path and its own arguments
+func editorExecutable(editor string) (string, []string, error) {
+	parts, err := utils.SplitArgs(editor)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid editor command: %w", err)
+	}
+	if len(parts) == 0 {
+		return "", nil, fmt.Errorf("no editor configured")
+	}
+	executable, err := exec.LookPath(parts[0])
+	if err != nil {
+		return "", nil, fmt.Errorf("editor %s not found: %w", parts[0], err)
 	}
-	return OpenResult{Success: true}
+	return executable, parts[1:], nil
 }
 
 // findXcodeProject searches for an Xcode project file (*.xcodeproj) in the build directory
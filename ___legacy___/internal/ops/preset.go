@@ -0,0 +1,146 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+// ExecuteSetupPreset configures with `cmake --preset <preset>` from the project root.
+// buildDir is the preset's resolved binary directory, where the File API query is written.
+// binaryDirOverride is passed as -B for presets that do not define binaryDir; empty otherwise.
+// definitions are passed as -D<definition> and override the preset's cacheVariables;
+// extraArgs follow them verbatim.
+func ExecuteSetupPreset(ctx context.Context, projectRoot, preset, buildDir, binaryDirOverride string, definitions, extraArgs []string, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) SetupResult {
+	if projectRoot == "" {
+		return SetupResult{Success: false, Error: "Working directory is empty"}
+	}
+
+	if preset == "" {
+		return SetupResult{Success: false, Error: "Preset is empty"}
+	}
+
+	args := []string{"--preset", preset}
+	if binaryDirOverride != "" {
+		args = append(args, "-B", binaryDirOverride)
+	}
+	for _, definition := range definitions {
+		args = append(args, "-D"+definition)
+	}
+	args = append(args, extraArgs...)
+
+	if buildDir != "" {
+		if err := utils.WriteFileAPIQuery(buildDir); err != nil {
+			appendCallback("WARNING: "+err.Error(), ui.TypeWarning)
+		}
+	}
+
+	appendCallback("Running: cmake "+strings.Join(args, " "), ui.TypeInfo)
+	appendCallback("", ui.TypeStdout)
+
+	cmakePath := utils.FindExecutableInEnv("cmake", vsEnv)
+	cmd := exec.CommandContext(ctx, cmakePath, args...)
+	cmd.Dir = projectRoot
+	if len(vsEnv) > 0 {
+		cmd.Env = vsEnv
+	}
+
+	tree, streamErr := utils.StreamCommand(cmd, appendCallback, replaceCallback, onProcessTreeStarted)
+
+	result := SetupResult{Success: false}
+	if streamErr != nil {
+		appendCallback("ERROR: "+streamErr.Error(), ui.TypeStderr)
+		result.Error = fmt.Errorf("ExecuteSetupPreset: StreamCommand: %w", streamErr).Error()
+	} else {
+		defer tree.Close()
+
+		waitErr := cmd.Wait()
+		abortedByUser := ctx.Err() == context.Canceled
+
+		if abortedByUser {
+			result.Error = "aborted"
+		} else if waitErr != nil {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("ERROR: "+waitErr.Error(), ui.TypeStderr)
+			result.ExitCode = exitCodeOf(waitErr)
+			result.Error = fmt.Errorf("ExecuteSetupPreset: cmake --preset failed: %w", waitErr).Error()
+		} else {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("Setup completed successfully: preset "+preset, ui.TypeStatus)
+			result.Success = true
+		}
+	}
+
+	return result
+}
+
+// ExecuteBuildPreset builds a preset's binary directory.
+// With a build preset it runs `cmake --build --preset <buildPreset>`; without one it falls back
+// to `cmake --build <buildDir>`, adding --config only for multi-config generators.
+// target is passed as --target in both cases; empty builds what the preset (or the tree) builds by default.
+// jobs > 0 is passed as --parallel and overrides the preset's jobs. usePTY runs the build on a pseudo-terminal.
+func ExecuteBuildPreset(ctx context.Context, projectRoot, buildPreset, buildDir, generator, config, target string, jobs int, usePTY bool, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) BuildResult {
+	var args []string
+	if buildPreset != "" {
+		args = []string{"--build", "--preset", buildPreset}
+		appendCallback("Building preset: "+buildPreset, ui.TypeInfo)
+	} else {
+		args = []string{"--build", buildDir}
+		if utils.IsGeneratorMultiConfig(generator) {
+			args = append(args, "--config", config)
+		}
+		appendCallback("Building: "+buildDir, ui.TypeInfo)
+		appendCallback("Configuration: "+config, ui.TypeInfo)
+	}
+	if target != "" {
+		args = append(args, "--target", target)
+		appendCallback("Target: "+target, ui.TypeInfo)
+	}
+	if jobs > 0 {
+		args = append(args, "--parallel", strconv.Itoa(jobs))
+	}
+	appendCallback("Running: cmake "+strings.Join(args, " "), ui.TypeInfo)
+	appendCallback("", ui.TypeStdout)
+
+	cmakePath := utils.FindExecutableInEnv("cmake", vsEnv)
+	cmd := exec.CommandContext(ctx, cmakePath, args...)
+	cmd.Dir = projectRoot
+	if len(vsEnv) > 0 {
+		cmd.Env = vsEnv
+	}
+
+	collectCallback, diagnostics := diagnosticsCallback(appendCallback)
+	tree, streamErr := streamBuild(cmd, usePTY, collectCallback, replaceCallback, onProcessTreeStarted)
+
+	result := BuildResult{Success: false}
+	if streamErr != nil {
+		appendCallback("ERROR: "+streamErr.Error(), ui.TypeStderr)
+		result.Error = fmt.Errorf("ExecuteBuildPreset: StreamCommand: %w", streamErr).Error()
+	} else {
+		defer tree.Close()
+
+		waitErr := cmd.Wait()
+		abortedByUser := ctx.Err() == context.Canceled
+
+		if abortedByUser {
+			result.Error = "aborted"
+		} else if waitErr != nil {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("ERROR: Build failed", ui.TypeStderr)
+			result.ExitCode = exitCodeOf(waitErr)
+			result.Error = fmt.Errorf("ExecuteBuildPreset: cmake --build failed: %w", waitErr).Error()
+		} else {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("Build completed successfully", ui.TypeStatus)
+			result.Success = true
+		}
+	}
+
+	result.Diagnostics = diagnostics.Diagnostics()
+	return result
+}
@@ -0,0 +1,62 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+type RunResult struct {
+	Success  bool
+	ExitCode int
+	Error    string
+}
+
+// ExecuteRun launches a built executable with args in workingDir and streams its output.
+// env is the complete child environment; empty inherits cake's own.
+func ExecuteRun(ctx context.Context, executable string, args []string, workingDir string, env []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) RunResult {
+	if executable == "" {
+		return RunResult{Success: false, Error: "Executable is empty"}
+	}
+
+	appendCallback("Running: "+utils.JoinArgs(append([]string{executable}, args...)), ui.TypeInfo)
+	appendCallback("Working directory: "+workingDir, ui.TypeInfo)
+	appendCallback("", ui.TypeStdout)
+
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Dir = workingDir
+	if len(env) > 0 {
+		cmd.Env = env
+	}
+
+	tree, streamErr := utils.StreamCommand(cmd, appendCallback, replaceCallback, onProcessTreeStarted)
+
+	result := RunResult{Success: false}
+	if streamErr != nil {
+		appendCallback("ERROR: "+streamErr.Error(), ui.TypeStderr)
+		result.Error = fmt.Errorf("ExecuteRun: StreamCommand: %w", streamErr).Error()
+	} else {
+		defer tree.Close()
+
+		waitErr := cmd.Wait()
+		abortedByUser := ctx.Err() == context.Canceled
+
+		if abortedByUser {
+			result.Error = "aborted"
+		} else if waitErr != nil {
+			result.ExitCode = exitCodeOf(waitErr)
+			appendCallback("", ui.TypeStdout)
+			appendCallback(fmt.Sprintf("Process exited with code %d", result.ExitCode), ui.TypeStderr)
+			result.Error = fmt.Errorf("ExecuteRun: %w", waitErr).Error()
+		} else {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("Process exited with code 0", ui.TypeStatus)
+			result.Success = true
+		}
+	}
+
+	return result
+}
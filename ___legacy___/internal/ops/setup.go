@@ -4,20 +4,23 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
-	"path/filepath"
 	"strings"
 
-	"github.com/jrengmusic/cake/internal"
 	"github.com/jrengmusic/cake/internal/ui"
 	"github.com/jrengmusic/cake/internal/utils"
 )
 
 type SetupResult struct {
-	Success bool
-	Error   string
+	Success  bool
+	ExitCode int
+	Error    string
 }
 
-func ExecuteSetupProject(ctx context.Context, workingDir, generator, config string, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) SetupResult {
+// ExecuteSetupProject configures with `cmake -G -S -B` into cake's Builds/ layout.
+// A variant other than the default gets its own tree and its flags as cake's own definitions.
+// definitions are passed as -D<definition> (NAME[:TYPE]=VALUE), after cake's own;
+// extraArgs follow them verbatim.
+func ExecuteSetupProject(ctx context.Context, workingDir, generator, config, variant string, definitions, extraArgs []string, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) SetupResult {
 	if workingDir == "" {
 		return SetupResult{Success: false, Error: "Working directory is empty"}
 	}
@@ -26,13 +29,29 @@ func ExecuteSetupProject(ctx context.Context, workingDir, generator, config stri
 		return SetupResult{Success: false, Error: "Generator is empty"}
 	}
 
-	buildDir := filepath.Join(workingDir, internal.BuildsDirName, utils.GetDirectoryName(generator))
+	if !utils.IsVariantSupported(generator, variant) {
+		return SetupResult{Success: false, Error: fmt.Sprintf("Variant %s is not supported by %s", variant, generator)}
+	}
+
+	buildDir := utils.GetBuildDirectory(workingDir, generator, config, variant)
 
 	args := []string{
 		"-G", generator,
 		"-S", workingDir,
 		"-B", buildDir,
-		"-DCMAKE_BUILD_TYPE=" + config,
+	}
+	if !utils.IsGeneratorMultiConfig(generator) {
+		// Multi-config generators ignore CMAKE_BUILD_TYPE; the config is chosen at build time
+		args = append(args, "-DCMAKE_BUILD_TYPE="+config)
+	}
+	for _, definition := range append(utils.VariantDefinitions(generator, variant), definitions...) {
+		args = append(args, "-D"+definition)
+	}
+	args = append(args, extraArgs...)
+
+	// Not fatal: without a reply cake falls back to scanning the build tree
+	if err := utils.WriteFileAPIQuery(buildDir); err != nil {
+		appendCallback("WARNING: "+err.Error(), ui.TypeWarning)
 	}
 
 	appendCallback("Running: cmake "+strings.Join(args, " "), ui.TypeInfo)
@@ -62,6 +81,7 @@ func ExecuteSetupProject(ctx context.Context, workingDir, generator, config stri
 		} else if waitErr != nil {
 			appendCallback("", ui.TypeStdout)
 			appendCallback("ERROR: "+waitErr.Error(), ui.TypeStderr)
+			result.ExitCode = exitCodeOf(waitErr)
 			result.Error = fmt.Errorf("ExecuteSetupProject: cmake configure failed: %w", waitErr).Error()
 		} else {
 			appendCallback("", ui.TypeStdout)
@@ -0,0 +1,91 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/jrengmusic/cake/internal/ui"
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+type TestResult struct {
+	Success  bool
+	ExitCode int
+	Error    string
+	Cases    []utils.CTestCase // Per-test outcomes in the order ctest finished them
+}
+
+// ExecuteTestProject runs `ctest --test-dir <buildDir> -C <config>` and collects per-test results
+// from the streamed output. filter is passed as -R (regex); rerunFailed adds --rerun-failed.
+func ExecuteTestProject(ctx context.Context, buildDir, config, filter string, rerunFailed bool, vsEnv []string, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*utils.ProcessTree)) TestResult {
+	if buildDir == "" {
+		return TestResult{Success: false, Error: "Build directory is empty"}
+	}
+
+	args := []string{"--test-dir", buildDir, "-C", config, "--output-on-failure"}
+	if filter != "" {
+		args = append(args, "-R", filter)
+	}
+	if rerunFailed {
+		args = append(args, "--rerun-failed")
+	}
+
+	appendCallback("Testing: "+buildDir, ui.TypeInfo)
+	appendCallback("Configuration: "+config, ui.TypeInfo)
+	appendCallback("Running: ctest "+strings.Join(args, " "), ui.TypeInfo)
+	appendCallback("", ui.TypeStdout)
+
+	// stdout and stderr stream on separate goroutines
+	var casesMu sync.Mutex
+	var cases []utils.CTestCase
+	collectCallback := func(line string, lineType ui.OutputLineType) {
+		if testCase, ok := utils.ParseCTestLine(line); ok {
+			casesMu.Lock()
+			cases = append(cases, testCase)
+			casesMu.Unlock()
+		}
+		appendCallback(line, lineType)
+	}
+
+	ctestPath := utils.FindExecutableInEnv("ctest", vsEnv)
+	cmd := exec.CommandContext(ctx, ctestPath, args...)
+	cmd.Dir = buildDir
+	if len(vsEnv) > 0 {
+		cmd.Env = vsEnv
+	}
+
+	tree, streamErr := utils.StreamCommand(cmd, collectCallback, replaceCallback, onProcessTreeStarted)
+
+	result := TestResult{Success: false}
+	if streamErr != nil {
+		appendCallback("ERROR: "+streamErr.Error(), ui.TypeStderr)
+		result.Error = fmt.Errorf("ExecuteTestProject: StreamCommand: %w", streamErr).Error()
+	} else {
+		defer tree.Close()
+
+		waitErr := cmd.Wait()
+		abortedByUser := ctx.Err() == context.Canceled
+
+		if abortedByUser {
+			result.Error = "aborted"
+		} else if waitErr != nil {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("ERROR: Tests failed", ui.TypeStderr)
+			result.ExitCode = exitCodeOf(waitErr)
+			result.Error = fmt.Errorf("ExecuteTestProject: ctest failed: %w", waitErr).Error()
+		} else {
+			appendCallback("", ui.TypeStdout)
+			appendCallback("Tests completed successfully", ui.TypeStatus)
+			result.Success = true
+		}
+	}
+
+	casesMu.Lock()
+	result.Cases = cases
+	casesMu.Unlock()
+
+	return result
+}
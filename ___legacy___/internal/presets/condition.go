@@ -0,0 +1,100 @@
+package presets
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Condition is a preset "condition" object (schema version 3+).
+// The JSON literals true, false and null are accepted as shorthand for a const condition.
+type Condition struct {
+	Type       string       `json:"type"`
+	Value      bool         `json:"value"`      // const
+	LHS        string       `json:"lhs"`        // equals, notEquals
+	RHS        string       `json:"rhs"`        // equals, notEquals
+	String     string       `json:"string"`     // inList, notInList, matches, notMatches
+	List       []string     `json:"list"`       // inList, notInList
+	Regex      string       `json:"regex"`      // matches, notMatches
+	Conditions []*Condition `json:"conditions"` // anyOf, allOf
+	Condition  *Condition   `json:"condition"`  // not
+}
+
+// Condition types (SSOT)
+const (
+	conditionConst      = "const"
+	conditionEquals     = "equals"
+	conditionNotEquals  = "notEquals"
+	conditionInList     = "inList"
+	conditionNotInList  = "notInList"
+	conditionMatches    = "matches"
+	conditionNotMatches = "notMatches"
+	conditionAnyOf      = "anyOf"
+	conditionAllOf      = "allOf"
+	conditionNot        = "not"
+)
+
+// UnmarshalJSON accepts a condition object or a boolean/null literal
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	var literal *bool
+	if err := json.Unmarshal(data, &literal); err == nil {
+		// null means "always true", like an absent condition
+		*c = Condition{Type: conditionConst, Value: literal == nil || *literal}
+		return nil
+	}
+
+	type plain Condition // avoids recursing into this method
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*c = Condition(p)
+	return nil
+}
+
+// Evaluate reports whether the condition holds. A nil condition is always true.
+// String operands are macro-expanded with m before comparison.
+func (c *Condition) Evaluate(m macroContext) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	switch c.Type {
+	case conditionConst:
+		return c.Value, nil
+	case conditionEquals, conditionNotEquals:
+		equal := m.expand(c.LHS) == m.expand(c.RHS)
+		return equal == (c.Type == conditionEquals), nil
+	case conditionInList, conditionNotInList:
+		value := m.expand(c.String)
+		found := slices.ContainsFunc(c.List, func(item string) bool { return m.expand(item) == value })
+		return found == (c.Type == conditionInList), nil
+	case conditionMatches, conditionNotMatches:
+		re, err := regexp.Compile(m.expand(c.Regex))
+		if err != nil {
+			return false, fmt.Errorf("Evaluate: %s: %w", c.Type, err)
+		}
+		return re.MatchString(m.expand(c.String)) == (c.Type == conditionMatches), nil
+	case conditionAnyOf, conditionAllOf:
+		wantAny := c.Type == conditionAnyOf
+		for _, sub := range c.Conditions {
+			result, err := sub.Evaluate(m)
+			if err != nil {
+				return false, err
+			}
+			if result == wantAny {
+				return wantAny, nil
+			}
+		}
+		return !wantAny, nil
+	case conditionNot:
+		if c.Condition == nil {
+			return false, fmt.Errorf("Evaluate: not: missing condition")
+		}
+		result, err := c.Condition.Evaluate(m)
+		return !result, err
+	default:
+		return false, fmt.Errorf("Evaluate: unknown condition type %q", c.Type)
+	}
+}
@@ -0,0 +1,105 @@
+package presets
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// macroPattern matches ${name}, $env{VAR}, $penv{VAR} and $vendor{...}
+var macroPattern = regexp.MustCompile(`\$(env|penv|vendor)?\{([^{}]*)\}`)
+
+// maxEnvExpansionDepth bounds $env{} references between preset environment entries
+const maxEnvExpansionDepth = 8
+
+// macroContext holds the values preset macros expand to for one preset
+type macroContext struct {
+	sourceDir   string
+	presetName  string
+	generator   string
+	fileDir     string
+	environment map[string]*string // preset "environment" — consulted before the process env by $env{}
+	depth       int
+}
+
+// macros builds the expansion context for a preset declared in fileDir
+func (l *loader) macros(presetName, generator, fileDir string, environment map[string]*string) macroContext {
+	return macroContext{
+		sourceDir:   l.sourceDir,
+		presetName:  presetName,
+		generator:   generator,
+		fileDir:     fileDir,
+		environment: environment,
+	}
+}
+
+// expand replaces the CMake preset macros in s.
+// Unknown macros and $vendor{} are left unchanged.
+func (m macroContext) expand(s string) string {
+	return macroPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := macroPattern.FindStringSubmatch(match)
+		namespace, name := parts[1], parts[2]
+
+		switch namespace {
+		case "env":
+			return m.envValue(name)
+		case "penv":
+			return os.Getenv(name)
+		case "vendor":
+			return match
+		}
+
+		switch name {
+		case "sourceDir":
+			return m.sourceDir
+		case "sourceParentDir":
+			return filepath.Dir(m.sourceDir)
+		case "sourceDirName":
+			return filepath.Base(m.sourceDir)
+		case "presetName":
+			return m.presetName
+		case "generator":
+			return m.generator
+		case "hostSystemName":
+			return HostSystemName()
+		case "fileDir":
+			return m.fileDir
+		case "dollar":
+			return "$"
+		case "pathListSep":
+			return string(os.PathListSeparator)
+		default:
+			return match
+		}
+	})
+}
+
+// envValue resolves $env{name}: the preset environment first (itself macro-expanded), then the process env.
+// A null entry in the preset environment unsets the variable.
+func (m macroContext) envValue(name string) string {
+	if value, ok := m.environment[name]; ok {
+		if value == nil || m.depth >= maxEnvExpansionDepth {
+			return ""
+		}
+		nested := m
+		nested.depth++
+		return nested.expand(*value)
+	}
+	return os.Getenv(name)
+}
+
+// HostSystemName returns the value of ${hostSystemName}: CMAKE_HOST_SYSTEM_NAME on this host
+func HostSystemName() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "Darwin"
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	default:
+		return strings.ToUpper(runtime.GOOS[:1]) + runtime.GOOS[1:]
+	}
+}
@@ -0,0 +1,162 @@
+package presets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Preset file names CMake reads from the source directory (SSOT)
+const (
+	FileName     = "CMakePresets.json"
+	UserFileName = "CMakeUserPresets.json"
+)
+
+// NameList is a preset field that may be a single string or an array of strings ("inherits", "targets")
+type NameList []string
+
+// UnmarshalJSON accepts both "name" and ["a", "b"]
+func (n *NameList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*n = NameList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected string or array of strings: %w", err)
+	}
+	*n = list
+	return nil
+}
+
+// ConfigurePreset is one entry of "configurePresets".
+// After Load, inherited fields are filled in and BinaryDir is absolute with macros expanded.
+type ConfigurePreset struct {
+	Name        string             `json:"name"`
+	DisplayName string             `json:"displayName"`
+	Description string             `json:"description"`
+	Hidden      bool               `json:"hidden"`
+	Inherits    NameList           `json:"inherits"`
+	Condition   *Condition         `json:"condition"`
+	Generator   string             `json:"generator"`
+	BinaryDir   string             `json:"binaryDir"` // empty when the preset leaves -B to the command line
+	Environment map[string]*string `json:"environment"`
+
+	fileDir string // directory of the file that declared the preset (${fileDir})
+}
+
+// BuildPreset is one entry of "buildPresets"
+type BuildPreset struct {
+	Name            string             `json:"name"`
+	DisplayName     string             `json:"displayName"`
+	Description     string             `json:"description"`
+	Hidden          bool               `json:"hidden"`
+	Inherits        NameList           `json:"inherits"`
+	Condition       *Condition         `json:"condition"`
+	ConfigurePreset string             `json:"configurePreset"`
+	Configuration   string             `json:"configuration"`
+	Targets         NameList           `json:"targets"`
+	Environment     map[string]*string `json:"environment"`
+
+	fileDir string
+}
+
+// TestPreset is one entry of "testPresets"
+type TestPreset struct {
+	Name            string             `json:"name"`
+	DisplayName     string             `json:"displayName"`
+	Description     string             `json:"description"`
+	Hidden          bool               `json:"hidden"`
+	Inherits        NameList           `json:"inherits"`
+	Condition       *Condition         `json:"condition"`
+	ConfigurePreset string             `json:"configurePreset"`
+	Configuration   string             `json:"configuration"`
+	Environment     map[string]*string `json:"environment"`
+
+	fileDir string
+}
+
+// Title returns displayName when set, otherwise the preset name
+func (p ConfigurePreset) Title() string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
+	return p.Name
+}
+
+// presetFile is the on-disk layout of CMakePresets.json / CMakeUserPresets.json
+type presetFile struct {
+	Version          int               `json:"version"`
+	Include          []string          `json:"include"`
+	ConfigurePresets []ConfigurePreset `json:"configurePresets"`
+	BuildPresets     []BuildPreset     `json:"buildPresets"`
+	TestPresets      []TestPreset      `json:"testPresets"`
+}
+
+// Set holds the usable presets of a project: inheritance resolved, macros expanded,
+// hidden presets and presets whose condition evaluates false removed.
+// Slices keep declaration order (CMakePresets.json first, then CMakeUserPresets.json).
+type Set struct {
+	Configure []ConfigurePreset
+	Build     []BuildPreset
+	Test      []TestPreset
+}
+
+// Load reads the preset files in sourceDir.
+// Returns (nil, nil) when the project has neither CMakePresets.json nor CMakeUserPresets.json.
+func Load(sourceDir string) (*Set, error) {
+	l := &loader{sourceDir: sourceDir, seen: map[string]bool{}}
+
+	found := false
+	for _, name := range []string{FileName, UserFileName} {
+		path := filepath.Join(sourceDir, name)
+		if _, statErr := os.Stat(path); statErr != nil {
+			if errors.Is(statErr, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("Load: %w", statErr)
+		}
+		found = true
+		if err := l.readFile(path); err != nil {
+			return nil, fmt.Errorf("Load: %w", err)
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	set, err := l.resolve()
+	if err != nil {
+		return nil, fmt.Errorf("Load: %w", err)
+	}
+	return set, nil
+}
+
+// FindConfigure returns the usable configure preset with the given name
+func (s *Set) FindConfigure(name string) (ConfigurePreset, bool) {
+	if s != nil {
+		for _, p := range s.Configure {
+			if p.Name == name {
+				return p, true
+			}
+		}
+	}
+	return ConfigurePreset{}, false
+}
+
+// BuildPresetFor returns the build preset for a configure preset that builds configuration;
+// an empty configuration finds one that names none. ok is false when no build preset of
+// configurePreset builds that configuration: another one would build a different one.
+func (s *Set) BuildPresetFor(configurePreset, configuration string) (BuildPreset, bool) {
+	if s != nil {
+		for _, p := range s.Build {
+			if p.ConfigurePreset == configurePreset && p.Configuration == configuration {
+				return p, true
+			}
+		}
+	}
+	return BuildPreset{}, false
+}
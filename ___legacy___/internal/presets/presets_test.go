@@ -0,0 +1,326 @@
+package presets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// --- helpers ---
+
+func writePresetFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func configureNames(set *Set) []string {
+	var names []string
+	for _, p := range set.Configure {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// --- Load ---
+
+func TestLoad_NoPresetFiles(t *testing.T) {
+	set, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if set != nil {
+		t.Errorf("expected nil set, got %+v", set)
+	}
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{"version": 3, "configurePresets": [`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for malformed preset file")
+	}
+}
+
+func TestLoad_InheritsAndHidden(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "base", "hidden": true, "generator": "Ninja", "binaryDir": "${sourceDir}/out/${presetName}"},
+			{"name": "debug", "inherits": "base", "displayName": "Debug"},
+			{"name": "release", "inherits": ["base"], "binaryDir": "build/rel"}
+		],
+		"buildPresets": [
+			{"name": "debug", "configurePreset": "debug"},
+			{"name": "hidden-build", "configurePreset": "base", "hidden": true}
+		]
+	}`)
+
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := configureNames(set)
+	if len(names) != 2 || names[0] != "debug" || names[1] != "release" {
+		t.Fatalf("configure presets: got %v, want [debug release]", names)
+	}
+
+	debug, _ := set.FindConfigure("debug")
+	if debug.Generator != "Ninja" {
+		t.Errorf("inherited generator: got %q", debug.Generator)
+	}
+	if want := filepath.Join(dir, "out", "debug"); debug.BinaryDir != want {
+		t.Errorf("binaryDir: got %q, want %q (${presetName} is the child's name)", debug.BinaryDir, want)
+	}
+	if debug.Title() != "Debug" {
+		t.Errorf("Title: got %q", debug.Title())
+	}
+
+	release, _ := set.FindConfigure("release")
+	if want := filepath.Join(dir, "build", "rel"); release.BinaryDir != want {
+		t.Errorf("relative binaryDir: got %q, want %q", release.BinaryDir, want)
+	}
+
+	if len(set.Build) != 1 || set.Build[0].Name != "debug" {
+		t.Errorf("build presets: got %+v", set.Build)
+	}
+}
+
+func TestLoad_DisplayNameNotInherited(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "base", "hidden": true, "displayName": "Base", "description": "Shared settings", "generator": "Ninja"},
+			{"name": "dev", "inherits": "base"}
+		],
+		"buildPresets": [
+			{"name": "build-base", "hidden": true, "displayName": "Build base", "configurePreset": "dev"},
+			{"name": "build-dev", "inherits": "build-base"}
+		]
+	}`)
+
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dev, _ := set.FindConfigure("dev")
+	if dev.Title() != "dev" || dev.Description != "" {
+		t.Errorf("configure: got Title %q, Description %q, want the child's own name and no description", dev.Title(), dev.Description)
+	}
+	if len(set.Build) != 1 || set.Build[0].DisplayName != "" {
+		t.Errorf("build presets: got %+v, want build-dev without a display name", set.Build)
+	}
+}
+
+func TestLoad_InheritsOrderEarlierParentWins(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "a", "hidden": true, "generator": "Ninja"},
+			{"name": "b", "hidden": true, "generator": "Unix Makefiles", "binaryDir": "b"},
+			{"name": "child", "inherits": ["a", "b"]}
+		]
+	}`)
+
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	child, ok := set.FindConfigure("child")
+	if !ok {
+		t.Fatal("child preset missing")
+	}
+	if child.Generator != "Ninja" {
+		t.Errorf("generator: got %q, want Ninja from first parent", child.Generator)
+	}
+	if child.BinaryDir != filepath.Join(dir, "b") {
+		t.Errorf("binaryDir: got %q, want value from second parent", child.BinaryDir)
+	}
+}
+
+func TestLoad_CircularInherits(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "a", "inherits": "b"},
+			{"name": "b", "inherits": "a"}
+		]
+	}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for circular inherits")
+	}
+}
+
+func TestLoad_DuplicateName(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{"version": 3, "configurePresets": [{"name": "a"}]}`)
+	writePresetFile(t, dir, UserFileName, `{"version": 3, "configurePresets": [{"name": "a"}]}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for duplicate preset across files")
+	}
+}
+
+func TestLoad_UserPresetsInheritProjectPresets(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{"version": 3, "configurePresets": [{"name": "base", "generator": "Ninja", "binaryDir": "out"}]}`)
+	writePresetFile(t, dir, UserFileName, `{"version": 3, "configurePresets": [{"name": "mine", "inherits": "base"}]}`)
+
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := configureNames(set)
+	if len(names) != 2 || names[0] != "base" || names[1] != "mine" {
+		t.Errorf("got %v, want [base mine]", names)
+	}
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "cmake"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writePresetFile(t, filepath.Join(dir, "cmake"), "common.json", `{"version": 4, "configurePresets": [{"name": "common", "binaryDir": "${fileDir}/out"}]}`)
+	writePresetFile(t, dir, FileName, `{"version": 4, "include": ["cmake/common.json"], "configurePresets": [{"name": "top", "inherits": "common"}]}`)
+
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	common, ok := set.FindConfigure("common")
+	if !ok {
+		t.Fatal("included preset missing")
+	}
+	if want := filepath.Join(dir, "cmake", "out"); common.BinaryDir != want {
+		t.Errorf("included binaryDir: got %q, want %q", common.BinaryDir, want)
+	}
+	// Like CMake, ${fileDir} expands against the file of the preset being resolved
+	top, _ := set.FindConfigure("top")
+	if want := filepath.Join(dir, "out"); top.BinaryDir != want {
+		t.Errorf("inherited binaryDir: got %q, want %q", top.BinaryDir, want)
+	}
+}
+
+func TestLoad_Condition(t *testing.T) {
+	dir := t.TempDir()
+	writePresetFile(t, dir, FileName, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "host", "condition": {"type": "equals", "lhs": "${hostSystemName}", "rhs": "`+HostSystemName()+`"}},
+			{"name": "other-host", "condition": {"type": "notEquals", "lhs": "${hostSystemName}", "rhs": "`+HostSystemName()+`"}},
+			{"name": "never", "condition": false},
+			{"name": "always", "condition": null},
+			{"name": "inherits-never", "inherits": "never"}
+		],
+		"buildPresets": [
+			{"name": "never-build", "configurePreset": "never"}
+		]
+	}`)
+
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := configureNames(set)
+	if len(names) != 2 || names[0] != "host" || names[1] != "always" {
+		t.Errorf("got %v, want [host always]", names)
+	}
+	if len(set.Build) != 0 {
+		t.Errorf("build preset of a disabled configure preset must be dropped, got %+v", set.Build)
+	}
+}
+
+// --- Condition.Evaluate ---
+
+func TestConditionEvaluate(t *testing.T) {
+	t.Setenv("CAKE_PRESET_TEST", "yes")
+	m := macroContext{
+		sourceDir:   "/src/proj",
+		presetName:  "dev",
+		environment: map[string]*string{"LOCAL": strPtr("$env{CAKE_PRESET_TEST}-local")},
+	}
+
+	tests := []struct {
+		name string
+		cond *Condition
+		want bool
+	}{
+		{"nil is true", nil, true},
+		{"const false", &Condition{Type: "const", Value: false}, false},
+		{"equals env", &Condition{Type: "equals", LHS: "$env{CAKE_PRESET_TEST}", RHS: "yes"}, true},
+		{"equals preset env", &Condition{Type: "equals", LHS: "$env{LOCAL}", RHS: "yes-local"}, true},
+		{"inList", &Condition{Type: "inList", String: "${presetName}", List: []string{"ci", "dev"}}, true},
+		{"notInList", &Condition{Type: "notInList", String: "${presetName}", List: []string{"dev"}}, false},
+		{"matches", &Condition{Type: "matches", String: "${sourceDir}", Regex: "proj$"}, true},
+		{"notMatches", &Condition{Type: "notMatches", String: "${sourceDirName}", Regex: "^proj$"}, false},
+		{"anyOf", &Condition{Type: "anyOf", Conditions: []*Condition{{Type: "const"}, {Type: "const", Value: true}}}, true},
+		{"allOf", &Condition{Type: "allOf", Conditions: []*Condition{{Type: "const"}, {Type: "const", Value: true}}}, false},
+		{"not", &Condition{Type: "not", Condition: &Condition{Type: "const"}}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.cond.Evaluate(m)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConditionEvaluate_UnknownType(t *testing.T) {
+	if _, err := (&Condition{Type: "bogus"}).Evaluate(macroContext{}); err == nil {
+		t.Error("expected error for unknown condition type")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// --- BuildPresetFor ---
+
+func TestBuildPresetFor(t *testing.T) {
+	set := &Set{
+		Build: []BuildPreset{
+			{Name: "dev-debug", ConfigurePreset: "dev", Configuration: "Debug"},
+			{Name: "dev-release", ConfigurePreset: "dev", Configuration: "Release"},
+			{Name: "ci", ConfigurePreset: "ci"},
+		},
+	}
+
+	tests := []struct {
+		configure, configuration string
+		want                     string
+		wantOK                   bool
+	}{
+		{"dev", "Release", "dev-release", true},
+		{"dev", "RelWithDebInfo", "", false}, // dev-debug would build Debug
+		{"ci", "", "ci", true},
+		{"ci", "Debug", "", false},
+		{"missing", "Debug", "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.configure+"/"+tc.configuration, func(t *testing.T) {
+			got, ok := set.BuildPresetFor(tc.configure, tc.configuration)
+			if ok != tc.wantOK || got.Name != tc.want {
+				t.Errorf("got (%q, %v), want (%q, %v)", got.Name, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+
+	var nilSet *Set
+	if _, ok := nilSet.BuildPresetFor("dev", "Debug"); ok {
+		t.Error("nil set must not return a preset")
+	}
+}
@@ -0,0 +1,280 @@
+package presets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// loader accumulates presets from every file (includes followed) before resolving inheritance
+type loader struct {
+	sourceDir string
+	seen      map[string]bool // files already read — include cycles and diamonds read once
+
+	configure []ConfigurePreset
+	build     []BuildPreset
+	test      []TestPreset
+}
+
+// readFile parses one preset file and, depth first, the files it includes
+func (l *loader) readFile(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("readFile: %w", err)
+	}
+	if l.seen[absPath] {
+		return nil
+	}
+	l.seen[absPath] = true
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("readFile: %w", err)
+	}
+	var file presetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("readFile: %s: %w", filepath.Base(absPath), err)
+	}
+
+	fileDir := filepath.Dir(absPath)
+	for _, include := range file.Include {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(fileDir, includePath)
+		}
+		if err := l.readFile(includePath); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range file.ConfigurePresets {
+		p.fileDir = fileDir
+		l.configure = append(l.configure, p)
+	}
+	for _, p := range file.BuildPresets {
+		p.fileDir = fileDir
+		l.build = append(l.build, p)
+	}
+	for _, p := range file.TestPresets {
+		p.fileDir = fileDir
+		l.test = append(l.test, p)
+	}
+	return nil
+}
+
+// resolve applies inheritance, expands macros and drops hidden or disabled presets
+func (l *loader) resolve() (*Set, error) {
+	configureByName, err := indexByName(l.configure, func(p ConfigurePreset) string { return p.Name })
+	if err != nil {
+		return nil, fmt.Errorf("configurePresets: %w", err)
+	}
+	buildByName, err := indexByName(l.build, func(p BuildPreset) string { return p.Name })
+	if err != nil {
+		return nil, fmt.Errorf("buildPresets: %w", err)
+	}
+	testByName, err := indexByName(l.test, func(p TestPreset) string { return p.Name })
+	if err != nil {
+		return nil, fmt.Errorf("testPresets: %w", err)
+	}
+
+	set := &Set{}
+
+	resolvedConfigure := map[string]ConfigurePreset{}
+	for _, raw := range l.configure {
+		p, err := resolveConfigure(raw.Name, configureByName, resolvedConfigure, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		macros := l.macros(p.Name, p.Generator, p.fileDir, p.Environment)
+		enabled, err := p.Condition.Evaluate(macros)
+		if err != nil {
+			return nil, fmt.Errorf("configure preset %q: condition: %w", p.Name, err)
+		}
+		if p.Hidden || !enabled {
+			continue
+		}
+		if p.BinaryDir != "" {
+			p.BinaryDir = macros.expand(p.BinaryDir)
+			if !filepath.IsAbs(p.BinaryDir) {
+				p.BinaryDir = filepath.Join(l.sourceDir, p.BinaryDir)
+			}
+			p.BinaryDir = filepath.Clean(p.BinaryDir)
+		}
+		set.Configure = append(set.Configure, p)
+	}
+
+	resolvedBuild := map[string]BuildPreset{}
+	for _, raw := range l.build {
+		p, err := resolveBuild(raw.Name, buildByName, resolvedBuild, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		enabled, err := p.Condition.Evaluate(l.macros(p.Name, "", p.fileDir, p.Environment))
+		if err != nil {
+			return nil, fmt.Errorf("build preset %q: condition: %w", p.Name, err)
+		}
+		if p.Hidden || !enabled || !set.hasConfigure(p.ConfigurePreset) {
+			continue
+		}
+		set.Build = append(set.Build, p)
+	}
+
+	resolvedTest := map[string]TestPreset{}
+	for _, raw := range l.test {
+		p, err := resolveTest(raw.Name, testByName, resolvedTest, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		enabled, err := p.Condition.Evaluate(l.macros(p.Name, "", p.fileDir, p.Environment))
+		if err != nil {
+			return nil, fmt.Errorf("test preset %q: condition: %w", p.Name, err)
+		}
+		if p.Hidden || !enabled || !set.hasConfigure(p.ConfigurePreset) {
+			continue
+		}
+		set.Test = append(set.Test, p)
+	}
+
+	return set, nil
+}
+
+func (s *Set) hasConfigure(name string) bool {
+	_, ok := s.FindConfigure(name)
+	return ok
+}
+
+// indexByName maps presets by name, rejecting duplicates as CMake does
+func indexByName[T any](list []T, name func(T) string) (map[string]T, error) {
+	index := make(map[string]T, len(list))
+	for _, p := range list {
+		n := name(p)
+		if n == "" {
+			return nil, fmt.Errorf("preset without a name")
+		}
+		if _, exists := index[n]; exists {
+			return nil, fmt.Errorf("duplicate preset %q", n)
+		}
+		index[n] = p
+	}
+	return index, nil
+}
+
+// resolveConfigure returns the preset with inherited fields filled in.
+// Earlier entries in "inherits" win; the child's own non-empty fields win over all parents.
+// "hidden", "displayName" and "description" are never inherited.
+func resolveConfigure(name string, byName map[string]ConfigurePreset, resolved map[string]ConfigurePreset, visiting map[string]bool) (ConfigurePreset, error) {
+	if p, ok := resolved[name]; ok {
+		return p, nil
+	}
+	p, ok := byName[name]
+	if !ok {
+		return p, fmt.Errorf("configure preset %q not found", name)
+	}
+	if visiting[name] {
+		return p, fmt.Errorf("configure preset %q: circular inherits", name)
+	}
+	visiting[name] = true
+
+	for _, parentName := range p.Inherits {
+		parent, err := resolveConfigure(parentName, byName, resolved, visiting)
+		if err != nil {
+			return p, fmt.Errorf("configure preset %q: %w", name, err)
+		}
+		inheritString(&p.Generator, parent.Generator)
+		inheritString(&p.BinaryDir, parent.BinaryDir)
+		if p.Condition == nil {
+			p.Condition = parent.Condition
+		}
+		p.Environment = inheritEnvironment(p.Environment, parent.Environment)
+	}
+
+	resolved[name] = p
+	return p, nil
+}
+
+// resolveBuild is resolveConfigure for build presets
+func resolveBuild(name string, byName map[string]BuildPreset, resolved map[string]BuildPreset, visiting map[string]bool) (BuildPreset, error) {
+	if p, ok := resolved[name]; ok {
+		return p, nil
+	}
+	p, ok := byName[name]
+	if !ok {
+		return p, fmt.Errorf("build preset %q not found", name)
+	}
+	if visiting[name] {
+		return p, fmt.Errorf("build preset %q: circular inherits", name)
+	}
+	visiting[name] = true
+
+	for _, parentName := range p.Inherits {
+		parent, err := resolveBuild(parentName, byName, resolved, visiting)
+		if err != nil {
+			return p, fmt.Errorf("build preset %q: %w", name, err)
+		}
+		inheritString(&p.ConfigurePreset, parent.ConfigurePreset)
+		inheritString(&p.Configuration, parent.Configuration)
+		if len(p.Targets) == 0 {
+			p.Targets = parent.Targets
+		}
+		if p.Condition == nil {
+			p.Condition = parent.Condition
+		}
+		p.Environment = inheritEnvironment(p.Environment, parent.Environment)
+	}
+
+	resolved[name] = p
+	return p, nil
+}
+
+// resolveTest is resolveConfigure for test presets
+func resolveTest(name string, byName map[string]TestPreset, resolved map[string]TestPreset, visiting map[string]bool) (TestPreset, error) {
+	if p, ok := resolved[name]; ok {
+		return p, nil
+	}
+	p, ok := byName[name]
+	if !ok {
+		return p, fmt.Errorf("test preset %q not found", name)
+	}
+	if visiting[name] {
+		return p, fmt.Errorf("test preset %q: circular inherits", name)
+	}
+	visiting[name] = true
+
+	for _, parentName := range p.Inherits {
+		parent, err := resolveTest(parentName, byName, resolved, visiting)
+		if err != nil {
+			return p, fmt.Errorf("test preset %q: %w", name, err)
+		}
+		inheritString(&p.ConfigurePreset, parent.ConfigurePreset)
+		inheritString(&p.Configuration, parent.Configuration)
+		if p.Condition == nil {
+			p.Condition = parent.Condition
+		}
+		p.Environment = inheritEnvironment(p.Environment, parent.Environment)
+	}
+
+	resolved[name] = p
+	return p, nil
+}
+
+func inheritString(field *string, parentValue string) {
+	if *field == "" {
+		*field = parentValue
+	}
+}
+
+// inheritEnvironment merges a parent's environment under the child's: child keys win
+func inheritEnvironment(child, parent map[string]*string) map[string]*string {
+	if len(parent) == 0 {
+		return child
+	}
+	merged := make(map[string]*string, len(child)+len(parent))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range child {
+		merged[k] = v
+	}
+	return merged
+}
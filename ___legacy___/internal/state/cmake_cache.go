@@ -0,0 +1,142 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CMakeCacheFile is the cache cmake writes at the top of every configured build tree
+const CMakeCacheFile = "CMakeCache.txt"
+
+// Cache entry types
+const (
+	CacheTypeBool          = "BOOL"
+	CacheTypePath          = "PATH"
+	CacheTypeFilepath      = "FILEPATH"
+	CacheTypeString        = "STRING"
+	CacheTypeInternal      = "INTERNAL"
+	CacheTypeStatic        = "STATIC"
+	CacheTypeUninitialized = "UNINITIALIZED"
+)
+
+// Property entries cmake stores as NAME-<PROPERTY>:INTERNAL=...
+const (
+	cachePropertyAdvanced = "-ADVANCED"
+	cachePropertyStrings  = "-STRINGS"
+)
+
+// ReadCMakeCache reads the user-editable entries of buildPath's CMakeCache.txt, sorted by name
+func ReadCMakeCache(buildPath string) ([]CacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(buildPath, CMakeCacheFile))
+	if err != nil {
+		return nil, fmt.Errorf("ReadCMakeCache: %w", err)
+	}
+	return ParseCMakeCache(data), nil
+}
+
+// ParseCMakeCache parses CMakeCache.txt content. INTERNAL and STATIC entries are dropped;
+// their ADVANCED and STRINGS properties are folded into the entries they describe.
+func ParseCMakeCache(data []byte) []CacheEntry {
+	var entries []CacheEntry
+	advanced := map[string]bool{}
+	allowed := map[string][]string{}
+	var help []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			help = nil
+			continue
+		case strings.HasPrefix(line, "//"):
+			help = append(help, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		name, entryType, value, ok := parseCacheLine(line)
+		if !ok {
+			help = nil
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(name, cachePropertyAdvanced):
+			advanced[strings.TrimSuffix(name, cachePropertyAdvanced)] = IsCMakeTrue(value)
+		case strings.HasSuffix(name, cachePropertyStrings):
+			allowed[strings.TrimSuffix(name, cachePropertyStrings)] = strings.Split(value, ";")
+		case entryType == CacheTypeInternal || entryType == CacheTypeStatic:
+			// cmake's own bookkeeping, not user-editable
+		default:
+			entries = append(entries, CacheEntry{
+				Name:  name,
+				Value: value,
+				Type:  entryType,
+				Help:  strings.Join(help, " "),
+			})
+		}
+		help = nil
+	}
+
+	for i := range entries {
+		entries[i].Advanced = advanced[entries[i].Name]
+		entries[i].Strings = allowed[entries[i].Name]
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// parseCacheLine splits `NAME:TYPE=VALUE`; NAME may be quoted
+func parseCacheLine(line string) (name, entryType, value string, ok bool) {
+	rest := line
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return "", "", "", false
+		}
+		name = line[1 : end+1]
+		rest = line[end+2:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", "", false
+		}
+		rest = rest[1:]
+	} else {
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return "", "", "", false
+		}
+		name = line[:colon]
+		rest = line[colon+1:]
+	}
+
+	entryType, value, ok = strings.Cut(rest, "=")
+	return name, entryType, value, ok && name != ""
+}
+
+// IsCMakeTrue reports whether value is true by if() rules: ON, YES, TRUE, Y or a non-zero number
+func IsCMakeTrue(value string) bool {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "ON", "YES", "TRUE", "Y":
+		return true
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return err == nil && number != 0
+}
+
+// CacheDefinition returns the -D argument body (NAME:TYPE=VALUE) that sets entry to value
+func CacheDefinition(entry CacheEntry, value string) string {
+	if entry.Type == "" || entry.Type == CacheTypeUninitialized {
+		return entry.Name + "=" + value
+	}
+	return entry.Name + ":" + entry.Type + "=" + value
+}
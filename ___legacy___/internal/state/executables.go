@@ -0,0 +1,67 @@
+package state
+
+import (
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// scanSkipDirs are build tree directories that hold cmake's own files, never project artifacts
+var scanSkipDirs = map[string]bool{
+	"CMakeFiles": true,
+	".cmake":     true,
+	"Testing":    true,
+	"_deps":      true,
+}
+
+// libraryExtensions mark shared libraries, which carry the executable bit on Linux
+var libraryExtensions = []string{".so", ".dylib", ".dll"}
+
+// scanExecutables walks buildDir for executable files, for trees without a File API reply.
+// Results are sorted by path so the pick is stable.
+func scanExecutables(buildDir string) []Executable {
+	if buildDir == "" {
+		return nil
+	}
+
+	var executables []Executable
+	// Unreadable directories are skipped, not fatal
+	_ = filepath.WalkDir(buildDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if path != buildDir && scanSkipDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isExecutableFile(entry) {
+			name := strings.TrimSuffix(entry.Name(), ".exe")
+			executables = append(executables, Executable{Name: name, Path: path})
+		}
+		return nil
+	})
+
+	sort.Slice(executables, func(i, j int) bool { return executables[i].Path < executables[j].Path })
+	return executables
+}
+
+func isExecutableFile(entry fs.DirEntry) bool {
+	if !entry.Type().IsRegular() {
+		return false
+	}
+	name := entry.Name()
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(name), ".exe")
+	}
+	for _, ext := range libraryExtensions {
+		if strings.HasSuffix(name, ext) || strings.Contains(name, ext+".") {
+			return false
+		}
+	}
+	info, err := entry.Info()
+	return err == nil && info.Mode().Perm()&0111 != 0
+}
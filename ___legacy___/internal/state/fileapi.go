@@ -0,0 +1,308 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+// Target types reported by codemodel-v2
+const (
+	TargetExecutable       = "EXECUTABLE"
+	TargetStaticLibrary    = "STATIC_LIBRARY"
+	TargetSharedLibrary    = "SHARED_LIBRARY"
+	TargetModuleLibrary    = "MODULE_LIBRARY"
+	TargetObjectLibrary    = "OBJECT_LIBRARY"
+	TargetInterfaceLibrary = "INTERFACE_LIBRARY"
+	TargetUtility          = "UTILITY"
+)
+
+// FileAPIReply is what cmake answered to cake's File API query for one build tree
+type FileAPIReply struct {
+	Configurations []FileAPIConfiguration // One per configuration: a single entry for single-config trees
+	Cache          []CacheEntry           // CMakeCache.txt entries, in reply order
+	Toolchains     []Toolchain            // One per enabled language
+}
+
+// FileAPIConfiguration is one configuration of the codemodel
+type FileAPIConfiguration struct {
+	Name    string // CMAKE_BUILD_TYPE for single-config trees (may be empty), else one of CMAKE_CONFIGURATION_TYPES
+	Targets []Target
+}
+
+// Target is one build system target of a configuration
+type Target struct {
+	Name      string
+	Type      string   // TargetExecutable, TargetStaticLibrary, ...
+	Artifacts []string // Absolute paths of the files the target produces
+}
+
+// CacheEntry is one CMake cache variable
+type CacheEntry struct {
+	Name     string
+	Value    string
+	Type     string // BOOL, PATH, FILEPATH, STRING, INTERNAL, STATIC, UNINITIALIZED
+	Help     string
+	Advanced bool
+	Strings  []string // Allowed values (STRINGS property) — empty means free-form
+}
+
+// Toolchain is the compiler CMake found for one language
+type Toolchain struct {
+	Language        string
+	CompilerID      string
+	CompilerPath    string
+	CompilerVersion string
+}
+
+// Configuration returns the named configuration. A single-config tree matches any name:
+// its one configuration is whatever CMAKE_BUILD_TYPE the tree was configured with.
+func (r *FileAPIReply) Configuration(name string) (FileAPIConfiguration, bool) {
+	if r == nil || len(r.Configurations) == 0 {
+		return FileAPIConfiguration{}, false
+	}
+	if len(r.Configurations) == 1 {
+		return r.Configurations[0], true
+	}
+	for _, config := range r.Configurations {
+		if config.Name == name {
+			return config, true
+		}
+	}
+	return FileAPIConfiguration{}, false
+}
+
+// ConfigurationNames returns the non-empty configuration names, in reply order
+func (r *FileAPIReply) ConfigurationNames() []string {
+	if r == nil {
+		return nil
+	}
+	var names []string
+	for _, config := range r.Configurations {
+		if config.Name != "" {
+			names = append(names, config.Name)
+		}
+	}
+	return names
+}
+
+// --- reply JSON (only the fields cake reads) ---
+
+type replyIndex struct {
+	Reply map[string]json.RawMessage `json:"reply"`
+}
+
+type replyClient struct {
+	Query struct {
+		Responses []replyObject `json:"responses"`
+	} `json:"query.json"`
+}
+
+type replyObject struct {
+	Kind     string `json:"kind"`
+	JSONFile string `json:"jsonFile"`
+	Error    string `json:"error"`
+}
+
+type replyCodemodel struct {
+	Configurations []struct {
+		Name    string `json:"name"`
+		Targets []struct {
+			Name     string `json:"name"`
+			JSONFile string `json:"jsonFile"`
+		} `json:"targets"`
+	} `json:"configurations"`
+}
+
+type replyTarget struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Artifacts []struct {
+		Path string `json:"path"`
+	} `json:"artifacts"`
+}
+
+type replyCache struct {
+	Entries []struct {
+		Name       string `json:"name"`
+		Value      string `json:"value"`
+		Type       string `json:"type"`
+		Properties []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"properties"`
+	} `json:"entries"`
+}
+
+type replyToolchains struct {
+	Toolchains []struct {
+		Language string `json:"language"`
+		Compiler struct {
+			ID      string `json:"id"`
+			Path    string `json:"path"`
+			Version string `json:"version"`
+		} `json:"compiler"`
+	} `json:"toolchains"`
+}
+
+// ReadFileAPIReply reads the newest reply to cake's query in buildPath.
+// Returns nil, nil when cmake has not answered (tree configured before cake wrote its query).
+func ReadFileAPIReply(buildPath string) (*FileAPIReply, error) {
+	replyDir := utils.GetFileAPIReplyDirectory(buildPath)
+
+	indexPath, err := latestReplyIndex(replyDir)
+	if err != nil || indexPath == "" {
+		return nil, err
+	}
+
+	var index replyIndex
+	if err := readReplyJSON(indexPath, &index); err != nil {
+		return nil, err
+	}
+	rawClient, ok := index.Reply[utils.FileAPIClientName]
+	if !ok {
+		return nil, nil
+	}
+	var client replyClient
+	if err := json.Unmarshal(rawClient, &client); err != nil {
+		return nil, fmt.Errorf("ReadFileAPIReply: %s: %w", utils.FileAPIClientName, err)
+	}
+
+	reply := &FileAPIReply{}
+	for _, object := range client.Query.Responses {
+		if object.Error != "" {
+			return nil, fmt.Errorf("ReadFileAPIReply: %s: %s", object.Kind, object.Error)
+		}
+		objectPath := filepath.Join(replyDir, object.JSONFile)
+
+		switch object.Kind {
+		case utils.FileAPIKindCodemodel:
+			err = readCodemodel(replyDir, objectPath, buildPath, reply)
+		case utils.FileAPIKindCache:
+			err = readCache(objectPath, reply)
+		case utils.FileAPIKindToolchains:
+			err = readToolchains(objectPath, reply)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reply, nil
+}
+
+// latestReplyIndex returns the newest index-*.json; CMake names them so the newest sorts last.
+// Returns "" when the reply directory is missing or empty.
+func latestReplyIndex(replyDir string) (string, error) {
+	entries, err := os.ReadDir(replyDir)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("latestReplyIndex: %w", err)
+	}
+
+	var indexes []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, "index-") && strings.HasSuffix(name, ".json") {
+			indexes = append(indexes, name)
+		}
+	}
+	if len(indexes) == 0 {
+		return "", nil
+	}
+	sort.Strings(indexes)
+	return filepath.Join(replyDir, indexes[len(indexes)-1]), nil
+}
+
+func readCodemodel(replyDir, path, buildPath string, reply *FileAPIReply) error {
+	var codemodel replyCodemodel
+	if err := readReplyJSON(path, &codemodel); err != nil {
+		return err
+	}
+
+	for _, config := range codemodel.Configurations {
+		configuration := FileAPIConfiguration{Name: config.Name}
+		for _, ref := range config.Targets {
+			var target replyTarget
+			if err := readReplyJSON(filepath.Join(replyDir, ref.JSONFile), &target); err != nil {
+				return err
+			}
+
+			// Artifact paths are relative to the build tree unless they live outside it
+			artifacts := make([]string, 0, len(target.Artifacts))
+			for _, artifact := range target.Artifacts {
+				artifactPath := filepath.FromSlash(artifact.Path)
+				if !filepath.IsAbs(artifactPath) {
+					artifactPath = filepath.Join(buildPath, artifactPath)
+				}
+				artifacts = append(artifacts, artifactPath)
+			}
+
+			configuration.Targets = append(configuration.Targets, Target{
+				Name:      target.Name,
+				Type:      target.Type,
+				Artifacts: artifacts,
+			})
+		}
+		reply.Configurations = append(reply.Configurations, configuration)
+	}
+	return nil
+}
+
+func readCache(path string, reply *FileAPIReply) error {
+	var cache replyCache
+	if err := readReplyJSON(path, &cache); err != nil {
+		return err
+	}
+
+	for _, entry := range cache.Entries {
+		cacheEntry := CacheEntry{Name: entry.Name, Value: entry.Value, Type: entry.Type}
+		for _, property := range entry.Properties {
+			switch property.Name {
+			case "HELPSTRING":
+				cacheEntry.Help = property.Value
+			case "ADVANCED":
+				cacheEntry.Advanced = property.Value == "1" || strings.EqualFold(property.Value, "ON")
+			case "STRINGS":
+				cacheEntry.Strings = strings.Split(property.Value, ";")
+			}
+		}
+		reply.Cache = append(reply.Cache, cacheEntry)
+	}
+	return nil
+}
+
+func readToolchains(path string, reply *FileAPIReply) error {
+	var toolchains replyToolchains
+	if err := readReplyJSON(path, &toolchains); err != nil {
+		return err
+	}
+
+	for _, toolchain := range toolchains.Toolchains {
+		reply.Toolchains = append(reply.Toolchains, Toolchain{
+			Language:        toolchain.Language,
+			CompilerID:      toolchain.Compiler.ID,
+			CompilerPath:    toolchain.Compiler.Path,
+			CompilerVersion: toolchain.Compiler.Version,
+		})
+	}
+	return nil
+}
+
+func readReplyJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("readReplyJSON: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("readReplyJSON: %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package state
 
 import (
+	"fmt"
 	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/presets"
+	"github.com/jrengmusic/cake/internal/utils"
 	"os"
 	"path/filepath"
 	"time"
@@ -9,49 +12,85 @@ import (
 
 // Generator represents a CMake generator with metadata
 type Generator struct {
-	Name  string // CMake project name: "Xcode", "Ninja", "Visual Studio 18 2026", "Visual Studio 17 2022"
-	IsIDE bool   // true for Xcode, VS; false for Ninja, Makefiles
+	Name        string // CMake generator name: "Xcode", "Ninja", "Unix Makefiles", "CodeBlocks - Ninja", "Visual Studio 17 2022", ...
+	IsIDE       bool   // true for Xcode, VS; false for Ninja, Makefiles
+	MultiConfig bool   // true for Xcode, VS, Ninja Multi-Config: one build tree, config picked at build time
 }
 
 // BuildInfo represents the state of a build directory
 type BuildInfo struct {
-	Generator    string   // Generator name
-	Path         string   // Full path to build directory
-	Exists       bool     // Whether build directory exists
-	IsConfigured bool     // Whether CMake has been run (CMakeCache.txt exists)
-	Configs      []string // Available configurations (Debug, Release, etc.) - for multi-config generators
+	Generator    string        // Generator name
+	Config       string        // Configuration baked into a single-config tree (Builds/Ninja-Debug); empty for multi-config
+	Variant      string        // Build variant baked into a Builds/ tree (Builds/Ninja-Debug-asan): "default" without a suffix; empty for preset trees
+	Path         string        // Full path to build directory
+	Exists       bool          // Whether build directory exists
+	IsConfigured bool          // Whether CMake has been run (CMakeCache.txt exists)
+	Configs      []string      // Available configurations (Debug, Release, etc.) — from the File API reply when there is one
+	CodeModel    *FileAPIReply // Targets, artifacts, cache and toolchains from cmake; nil until cmake answers cake's query
+	NinjaTargets []string      // `ninja -t targets` fallback for Ninja trees without a File API reply
 }
 
 // ProjectState represents the current state of the CMake project
 type ProjectState struct {
-	WorkingDirectory  string
+	WorkingDirectory  string // Project root — set once at construction, never re-read from the process cwd
 	HasCMakeLists     bool
 	AvailableProjects []Generator          // Projects detected as available on system
 	SelectedProject   string               // Currently selected project (cycled by user)
-	Builds            map[string]BuildInfo // Build state by project name (Builds/<Generator>/)
+	Builds            map[string]BuildInfo // Build state by build directory path (Builds/<Generator>/ or Builds/<Generator>-<Config>/)
 	Configuration     string               // Current configuration: "Debug" or "Release"
+	Variant           string               // Current build variant: "default", "asan", "ubsan", "tsan" or "coverage"
 	IsPluginProject   bool
 	LastRefreshTime   time.Time
 	RefreshInterval   time.Duration
-	IsConfigured      bool     // Whether CMake has been run (CMakeCache.txt exists)
-	Configs           []string // Available configurations (Debug, Release, etc.) - for multi-config generators
-	vsEnv             []string // Captured Visual Studio environment for executable lookup
+	IsConfigured      bool                      // Whether CMake has been run (CMakeCache.txt exists)
+	Configs           []string                  // Available configurations (Debug, Release, etc.) - for multi-config generators
+	vsEnv             []string                  // Captured Visual Studio environment for executable lookup
+	cmakeGenerators   []string                  // Generators reported by `cmake -E capabilities` — queried once, cmake does not change mid-session
+	inspections       map[string]treeInspection // File API reply / ninja targets by build dir, re-read only when their source file changes
+	Presets           *presets.Set              // CMakePresets.json / CMakeUserPresets.json, nil when the project has none
+	PresetError       string                    // Why the preset files could not be loaded; empty when they loaded (or do not exist)
+	SelectedPreset    string                    // Configure preset name; empty = cake's own -G/-S/-B command line
+	SelectedTarget    string                    // Target passed as --target; empty = build everything
 }
 
-// NewProjectState creates a new ProjectState instance
-func NewProjectState() *ProjectState {
-	cwd, err := os.Getwd()
+// ResolveProjectRoot returns the absolute project root for dir.
+// Empty dir means the current working directory. The root must be an existing directory.
+func ResolveProjectRoot(dir string) (string, error) {
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("ResolveProjectRoot: getwd: %w", err)
+		}
+		dir = cwd
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("ResolveProjectRoot: %w", err)
+	}
+
+	info, err := os.Stat(absDir)
 	if err != nil {
-		cwd = "."
+		return "", fmt.Errorf("ResolveProjectRoot: %w", err)
 	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("ResolveProjectRoot: %s is not a directory", absDir)
+	}
+
+	return absDir, nil
+}
 
+// NewProjectState creates a new ProjectState rooted at projectRoot.
+// projectRoot is stored once — scanning, build paths and ops all derive from it.
+func NewProjectState(projectRoot string) *ProjectState {
 	ps := &ProjectState{
-		WorkingDirectory:  cwd,
+		WorkingDirectory:  projectRoot,
 		HasCMakeLists:     false,
 		AvailableProjects: []Generator{},
 		SelectedProject:   "",
 		Builds:            make(map[string]BuildInfo),
 		Configuration:     internal.ConfigDebug,
+		Variant:           utils.VariantDefault,
 		IsPluginProject:   false,
 		RefreshInterval:   time.Second * 2,
 	}
@@ -75,22 +114,17 @@ func (ps *ProjectState) Refresh() {
 
 // ForceRefresh immediately refreshes project state
 func (ps *ProjectState) ForceRefresh() {
-	cwd, err := os.Getwd()
-	if err != nil {
-		ps.WorkingDirectory = "."
-		ps.HasCMakeLists = false
-		return
-	}
-	ps.WorkingDirectory = cwd
-
-	cmakePath := filepath.Join(cwd, internal.CMakeListsFile)
-	_, err = os.Stat(cmakePath)
+	cmakePath := filepath.Join(ps.WorkingDirectory, internal.CMakeListsFile)
+	_, err := os.Stat(cmakePath)
 	ps.HasCMakeLists = (err == nil)
 
 	// Detect available generators — runs after vsEnv is set so VS-bundled tools are visible
 	ps.DetectAvailableProjects()
 
-	ps.scanBuildDirectories(cwd)
+	ps.scanBuildDirectories(ps.WorkingDirectory)
+
+	// Presets after Builds/ so preset binaryDirs outside Builds/ are added to the same map
+	ps.loadPresets()
 
 	// Set default selected project if none selected
 	if ps.SelectedProject == "" && len(ps.AvailableProjects) > 0 {
@@ -126,6 +160,7 @@ func (ps *ProjectState) CycleToNextProject() {
 	} else {
 		ps.SelectedProject = ps.AvailableProjects[0].Name
 	}
+	ps.resetUnsupportedVariant()
 }
 
 // CycleToPrevProject advances to the previous available project
@@ -152,6 +187,7 @@ func (ps *ProjectState) CycleToPrevProject() {
 	} else {
 		ps.SelectedProject = ps.AvailableProjects[0].Name
 	}
+	ps.resetUnsupportedVariant()
 }
 
 // CycleConfiguration toggles between Debug and Release
@@ -183,27 +219,78 @@ func (ps *ProjectState) SetSelectedProject(generator string) {
 	}
 	if found {
 		ps.SelectedProject = generator
+		ps.resetUnsupportedVariant()
+	}
+}
+
+// CycleVariant advances to the next build variant the selected generator's toolchain supports
+func (ps *ProjectState) CycleVariant() {
+	variants := utils.Variants()
+	current := 0
+	for i, variant := range variants {
+		if variant == ps.Variant {
+			current = i
+			break
+		}
+	}
+	for step := 1; step <= len(variants); step++ {
+		next := variants[(current+step)%len(variants)]
+		if utils.IsVariantSupported(ps.SelectedProject, next) {
+			ps.Variant = next
+			return
+		}
+	}
+}
+
+// SetVariant sets the build variant directly; unknown or unsupported variants are ignored
+func (ps *ProjectState) SetVariant(variant string) {
+	if utils.IsVariant(variant) && utils.IsVariantSupported(ps.SelectedProject, variant) {
+		ps.Variant = variant
 	}
 }
 
-// GetBuildPath returns the build directory path for the selected project
+// resetUnsupportedVariant falls back to the default variant when the selected generator
+// cannot build the current one (Visual Studio has no tsan, ubsan or coverage)
+func (ps *ProjectState) resetUnsupportedVariant() {
+	if !utils.IsVariantSupported(ps.SelectedProject, ps.Variant) {
+		ps.Variant = utils.VariantDefault
+	}
+}
+
+// GetBuildPath returns the build directory path for the selected project,
+// or the selected preset's binaryDir when a configure preset is active
 func (ps *ProjectState) GetBuildPath() string {
+	if preset, ok := ps.GetSelectedPreset(); ok {
+		return ps.presetBuildDirectory(preset)
+	}
 	if ps.SelectedProject == "" {
 		return ""
 	}
-	return ps.GetBuildDirectory(ps.SelectedProject)
+	return ps.GetBuildDirectory(ps.SelectedProject, ps.Configuration, ps.Variant)
 }
 
 // GetSelectedBuildInfo returns the build info for the selected project
 func (ps *ProjectState) GetSelectedBuildInfo() BuildInfo {
-	if buildInfo, exists := ps.Builds[ps.SelectedProject]; exists {
+	if buildInfo, exists := ps.Builds[ps.GetBuildPath()]; exists {
 		return buildInfo
 	}
-	return BuildInfo{Generator: ps.SelectedProject, Exists: false}
+	return BuildInfo{Generator: ps.ActiveGenerator(), Exists: false}
+}
+
+// ActiveGenerator returns the generator the next configure uses:
+// the selected preset's generator when a preset is active, otherwise the selected project
+func (ps *ProjectState) ActiveGenerator() string {
+	if preset, ok := ps.GetSelectedPreset(); ok {
+		return preset.Generator
+	}
+	return ps.SelectedProject
 }
 
 // CanGenerate returns true if we can generate the selected project
 func (ps *ProjectState) CanGenerate() bool {
+	if _, ok := ps.GetSelectedPreset(); ok {
+		return ps.HasCMakeLists
+	}
 	return ps.SelectedProject != "" && ps.HasCMakeLists
 }
 
@@ -8,10 +8,10 @@ import (
 	"strings"
 )
 
-// GetBuildDirectory returns the build directory path for the given generator
-func (ps *ProjectState) GetBuildDirectory(generatorName string) string {
-	// All projects are multi-config: Builds/<dir>/
-	return filepath.Join(ps.WorkingDirectory, internal.BuildsDirName, utils.GetDirectoryName(generatorName))
+// GetBuildDirectory returns the build directory path for the given generator, configuration and variant
+func (ps *ProjectState) GetBuildDirectory(generatorName, config, variant string) string {
+	// Multi-config: Builds/<dir>/ — single-config: Builds/<dir>-<config>/ — plus -<variant> unless default
+	return utils.GetBuildDirectory(ps.WorkingDirectory, generatorName, config, variant)
 }
 
 // GetProjectLabel returns a display-friendly project name
@@ -19,11 +19,11 @@ func (ps *ProjectState) GetProjectLabel() string {
 	name := ps.SelectedProject
 
 	// Truncate long names for display
-	switch name {
-	case utils.GeneratorVS2026:
-		return "VS 2026"
-	case utils.GeneratorVS2022:
-		return "VS 2022"
+	switch {
+	case utils.IsGeneratorVS(name):
+		return "VS " + strings.TrimPrefix(utils.GetDirectoryName(name), "VS")
+	case name == utils.GeneratorNinjaMultiConfig:
+		return "Ninja Multi"
 	default:
 		return name
 	}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/presets"
+	"github.com/jrengmusic/cake/internal/utils"
+)
+
+// loadPresets reads CMakePresets.json / CMakeUserPresets.json and records the build
+// directory of every configure preset. A selected preset that disappeared is dropped.
+func (ps *ProjectState) loadPresets() {
+	set, err := presets.Load(ps.WorkingDirectory)
+	ps.Presets = set
+	ps.PresetError = ""
+	if err != nil {
+		ps.PresetError = err.Error()
+	}
+
+	if _, ok := ps.Presets.FindConfigure(ps.SelectedPreset); !ok {
+		ps.SelectedPreset = ""
+	}
+
+	if ps.Presets == nil {
+		return
+	}
+	for _, preset := range ps.Presets.Configure {
+		buildPath := ps.presetBuildDirectory(preset)
+		if info, err := os.Stat(buildPath); err != nil || !info.IsDir() {
+			continue
+		}
+		// Replaces any Builds/ scan entry: the preset, not the directory name, knows the generator
+		ps.Builds[buildPath] = ps.inspectBuildDirectory(buildPath, preset.Generator, "")
+	}
+}
+
+// presetBuildDirectory returns the preset's binaryDir, or Builds/<preset>/ when the
+// preset leaves the build directory to the command line
+func (ps *ProjectState) presetBuildDirectory(preset presets.ConfigurePreset) string {
+	if preset.BinaryDir != "" {
+		return preset.BinaryDir
+	}
+	return filepath.Join(ps.WorkingDirectory, internal.BuildsDirName, preset.Name)
+}
+
+// PresetBinaryDirOverride returns the -B value to pass with `cmake --preset`:
+// empty when the preset defines binaryDir itself
+func (ps *ProjectState) PresetBinaryDirOverride() string {
+	preset, ok := ps.GetSelectedPreset()
+	if !ok || preset.BinaryDir != "" {
+		return ""
+	}
+	return ps.presetBuildDirectory(preset)
+}
+
+// HasPresets returns true if the project defines at least one usable configure preset
+func (ps *ProjectState) HasPresets() bool {
+	return ps.Presets != nil && len(ps.Presets.Configure) > 0
+}
+
+// GetSelectedPreset returns the active configure preset; ok is false when none is selected
+func (ps *ProjectState) GetSelectedPreset() (presets.ConfigurePreset, bool) {
+	if ps.SelectedPreset == "" {
+		return presets.ConfigurePreset{}, false
+	}
+	return ps.Presets.FindConfigure(ps.SelectedPreset)
+}
+
+// GetSelectedBuildPreset returns the build preset for the active configure preset and
+// configuration. A single-config tree builds only what it was configured for, so there a
+// build preset that names no configuration also fits. ok is false when no preset is active
+// or none fits; the build then runs `cmake --build <binaryDir> --config <configuration>`.
+func (ps *ProjectState) GetSelectedBuildPreset() (presets.BuildPreset, bool) {
+	preset, ok := ps.GetSelectedPreset()
+	if !ok {
+		return presets.BuildPreset{}, false
+	}
+	if buildPreset, ok := ps.Presets.BuildPresetFor(ps.SelectedPreset, ps.Configuration); ok {
+		return buildPreset, true
+	}
+	if utils.IsGeneratorMultiConfig(preset.Generator) {
+		return presets.BuildPreset{}, false
+	}
+	return ps.Presets.BuildPresetFor(ps.SelectedPreset, "")
+}
+
+// CyclePreset advances through "no preset" followed by every configure preset, wrapping around
+func (ps *ProjectState) CyclePreset() {
+	if !ps.HasPresets() {
+		ps.SelectedPreset = ""
+		return
+	}
+
+	names := make([]string, 0, len(ps.Presets.Configure)+1)
+	names = append(names, "")
+	for _, preset := range ps.Presets.Configure {
+		names = append(names, preset.Name)
+	}
+
+	currentIndex := 0
+	for i, name := range names {
+		if name == ps.SelectedPreset {
+			currentIndex = i
+			break
+		}
+	}
+	ps.SelectedPreset = names[(currentIndex+1)%len(names)]
+}
+
+// SetSelectedPreset selects a configure preset by name ("" selects none).
+// Unknown names leave the selection unchanged.
+func (ps *ProjectState) SetSelectedPreset(name string) {
+	if name == "" {
+		ps.SelectedPreset = ""
+		return
+	}
+	if _, ok := ps.Presets.FindConfigure(name); ok {
+		ps.SelectedPreset = name
+	}
+}
+
+// GetPresetLabel returns the Preset row value: the preset name, "None" when cake builds
+// its own command line, "Invalid" when the preset files failed to load
+func (ps *ProjectState) GetPresetLabel() string {
+	if ps.PresetError != "" {
+		return "Invalid"
+	}
+	if ps.SelectedPreset == "" {
+		return "None"
+	}
+	return ps.SelectedPreset
+}
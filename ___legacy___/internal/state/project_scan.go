@@ -7,18 +7,85 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 )
 
-// DetectAvailableProjects checks which generators are available on the system
+// DetectAvailableProjects checks which generators are available on the system.
+// Generators come from `cmake -E capabilities`; each is kept only when its build
+// tool (ninja, make, xcodebuild, ...) or Visual Studio installation is present.
+// Falls back to the built-in Xcode/Ninja/VS probe when cmake cannot be queried.
 func (ps *ProjectState) DetectAvailableProjects() {
 	ps.AvailableProjects = []Generator{}
 
+	if ps.cmakeGenerators == nil {
+		generators, err := utils.QueryCMakeGenerators(ps.vsEnv)
+		if err != nil {
+			ps.detectBuiltinProjects()
+			return
+		}
+		ps.cmakeGenerators = generators
+	}
+
+	installedVS := map[string]bool{}
+	if runtime.GOOS == "windows" {
+		for _, vsGen := range ps.checkVSGeneratorAvailable() {
+			installedVS[vsGen] = true
+		}
+	}
+
+	for _, name := range orderGenerators(ps.cmakeGenerators) {
+		available := false
+		if utils.IsGeneratorVS(name) {
+			available = installedVS[name]
+		} else if tool := utils.GetBuildTool(name); tool != "" {
+			available = ps.checkToolAvailable(tool)
+		}
+
+		if available {
+			ps.AvailableProjects = append(ps.AvailableProjects, Generator{
+				Name:        name,
+				IsIDE:       utils.IsGeneratorIDE(name),
+				MultiConfig: utils.IsGeneratorMultiConfig(name),
+			})
+		}
+	}
+}
+
+// generatorPriority lists generators shown first in the Project row; the rest keep cmake's order
+var generatorPriority = []string{
+	utils.GeneratorXcode,
+	utils.GeneratorNinja,
+	utils.GeneratorVS2026,
+	utils.GeneratorVS2022,
+}
+
+// orderGenerators returns names with generatorPriority entries first, preserving relative order otherwise
+func orderGenerators(names []string) []string {
+	ordered := make([]string, 0, len(names))
+	for _, preferred := range generatorPriority {
+		for _, name := range names {
+			if name == preferred {
+				ordered = append(ordered, name)
+			}
+		}
+	}
+	for _, name := range names {
+		if !slices.Contains(generatorPriority, name) {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered
+}
+
+// detectBuiltinProjects probes the generators cake supported before capability discovery
+func (ps *ProjectState) detectBuiltinProjects() {
 	// Check Xcode (macOS only)
 	if runtime.GOOS == "darwin" {
 		if ps.checkCommandExists("xcodebuild") {
 			ps.AvailableProjects = append(ps.AvailableProjects, Generator{
-				Name:  "Xcode",
-				IsIDE: true,
+				Name:        "Xcode",
+				IsIDE:       true,
+				MultiConfig: true,
 			})
 		}
 	}
@@ -26,8 +93,9 @@ func (ps *ProjectState) DetectAvailableProjects() {
 	// Check Ninja (cross-platform, including VS-bundled ninja not on system PATH)
 	if ps.checkNinjaAvailable() {
 		ps.AvailableProjects = append(ps.AvailableProjects, Generator{
-			Name:  "Ninja",
-			IsIDE: false,
+			Name:        "Ninja",
+			IsIDE:       false,
+			MultiConfig: false,
 		})
 	}
 
@@ -36,8 +104,9 @@ func (ps *ProjectState) DetectAvailableProjects() {
 		vsGenerators := ps.checkVSGeneratorAvailable()
 		for _, vsGen := range vsGenerators {
 			ps.AvailableProjects = append(ps.AvailableProjects, Generator{
-				Name:  vsGen,
-				IsIDE: true,
+				Name:        vsGen,
+				IsIDE:       true,
+				MultiConfig: true,
 			})
 		}
 	}
@@ -49,15 +118,16 @@ func (ps *ProjectState) checkCommandExists(cmd string) bool {
 	return err == nil
 }
 
+// checkToolAvailable reports whether a build tool is reachable — either on system PATH
+// or within the VS-captured environment (for VS-bundled ninja, nmake, jom).
+func (ps *ProjectState) checkToolAvailable(tool string) bool {
+	return ps.checkCommandExists(tool) || utils.IsExecutableInVSEnv(tool, ps.vsEnv)
+}
+
 // checkNinjaAvailable reports whether ninja is reachable — either on system PATH
 // or within the VS-captured environment (for VS-bundled ninja).
 func (ps *ProjectState) checkNinjaAvailable() bool {
-	_, lookErr := exec.LookPath("ninja")
-	found := lookErr == nil
-	if !found {
-		found = utils.IsExecutableInVSEnv("ninja", ps.vsEnv)
-	}
-	return found
+	return ps.checkToolAvailable("ninja")
 }
 
 // checkVSGeneratorAvailable returns generator strings for all installed VS versions
@@ -83,27 +153,108 @@ func (ps *ProjectState) scanBuildDirectories(rootDir string) {
 
 		dirName := entry.Name()
 		buildPath := filepath.Join(buildsDir, dirName)
+		generator, config, variant := utils.ParseBuildDirName(dirName)
 
-		buildInfo := BuildInfo{
-			Generator: utils.GetGeneratorNameFromDirectory(dirName),
-			Path:      buildPath,
-			Exists:    true,
-		}
+		buildInfo := ps.inspectBuildDirectory(buildPath, generator, config)
+		buildInfo.Variant = variant
+		ps.Builds[buildPath] = buildInfo
+	}
+}
 
-		// Check if configured (CMakeCache.txt exists)
-		cachePath := filepath.Join(buildPath, "CMakeCache.txt")
-		if _, err := os.Stat(cachePath); err == nil {
-			buildInfo.IsConfigured = true
+// inspectBuildDirectory returns the BuildInfo of an existing build directory.
+// config is the configuration baked into a single-config tree; empty for multi-config.
+func (ps *ProjectState) inspectBuildDirectory(buildPath, generator, config string) BuildInfo {
+	buildInfo := BuildInfo{
+		Generator: generator,
+		Config:    config,
+		Path:      buildPath,
+		Exists:    true,
+	}
 
-			// All projects are multi-config, detect available configurations
-			buildInfo.Configs = ps.detectConfigurations(buildPath)
+	// Check if configured (CMakeCache.txt exists)
+	cachePath := filepath.Join(buildPath, CMakeCacheFile)
+	if _, err := os.Stat(cachePath); err == nil {
+		buildInfo.IsConfigured = true
+
+		inspection := ps.inspectConfiguredTree(buildPath, generator)
+		buildInfo.CodeModel = inspection.codeModel
+		buildInfo.NinjaTargets = inspection.ninjaTargets
+		if inspection.codeModel != nil {
+			buildInfo.Configs = inspection.codeModel.ConfigurationNames()
 		}
 
-		ps.Builds[utils.GetGeneratorNameFromDirectory(dirName)] = buildInfo
+		if len(buildInfo.Configs) == 0 {
+			if utils.IsGeneratorMultiConfig(generator) {
+				// Multi-config trees create one subdirectory per built configuration
+				buildInfo.Configs = ps.detectConfigurations(buildPath)
+			} else if config != "" {
+				buildInfo.Configs = []string{config}
+			}
+		}
+	}
+
+	return buildInfo
+}
+
+// treeInspection is what a configured tree's File API reply or build.ninja yielded
+type treeInspection struct {
+	stamp        inspectionStamp
+	codeModel    *FileAPIReply
+	ninjaTargets []string
+}
+
+// inspectionStamp identifies the file an inspection was read from
+type inspectionStamp struct {
+	path    string // Newest reply index, or build.ninja when there is none
+	modTime int64  // UnixNano
+}
+
+// readInspectionStamp returns the stamp of buildPath's newest File API reply index, or of its
+// build.ninja when there is no reply; cmake writes a new index on every configure
+func readInspectionStamp(buildPath string, usesNinja bool) inspectionStamp {
+	path, err := latestReplyIndex(utils.GetFileAPIReplyDirectory(buildPath))
+	if (err != nil || path == "") && usesNinja {
+		path = filepath.Join(buildPath, "build.ninja")
+	}
+	if path == "" {
+		return inspectionStamp{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return inspectionStamp{}
+	}
+	return inspectionStamp{path: path, modTime: info.ModTime().UnixNano()}
+}
+
+// inspectConfiguredTree reads a configured tree's File API reply, or asks ninja for its targets
+// when there is none. ForceRefresh runs after every operation, so the result is kept per build
+// dir and read again only when the reply index (or build.ninja) changes.
+func (ps *ProjectState) inspectConfiguredTree(buildPath, generator string) treeInspection {
+	usesNinja := utils.GetBuildTool(generator) == "ninja"
+	stamp := readInspectionStamp(buildPath, usesNinja)
+	if cached, ok := ps.inspections[buildPath]; ok && cached.stamp == stamp {
+		return cached
+	}
+
+	inspection := treeInspection{stamp: stamp}
+	// An unreadable reply is not fatal: fall back to guessing from the tree
+	if reply, err := ReadFileAPIReply(buildPath); err == nil && reply != nil {
+		inspection.codeModel = reply
+	}
+	if inspection.codeModel == nil && usesNinja {
+		// Tree configured outside cake: ask ninja for targets instead (failure leaves the list empty)
+		inspection.ninjaTargets, _ = utils.QueryNinjaTargets(buildPath, ps.vsEnv)
+	}
+
+	if ps.inspections == nil {
+		ps.inspections = make(map[string]treeInspection)
 	}
+	ps.inspections[buildPath] = inspection
+	return inspection
 }
 
-// detectConfigurations scans for available build configurations
+// detectConfigurations guesses the built configurations of a multi-config tree from
+// its subdirectory names; used when there is no File API reply
 func (ps *ProjectState) detectConfigurations(buildPath string) []string {
 	var configs []string
 
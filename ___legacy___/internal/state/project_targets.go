@@ -0,0 +1,135 @@
+package state
+
+import "slices"
+
+// GetTargets returns the buildable targets of the selected build tree for the current
+// configuration: from the File API reply, else from `ninja -t targets`. Empty when unknown.
+func (ps *ProjectState) GetTargets() []string {
+	buildInfo := ps.GetSelectedBuildInfo()
+
+	if buildInfo.CodeModel != nil {
+		configuration, ok := buildInfo.CodeModel.Configuration(ps.Configuration)
+		if !ok {
+			return nil
+		}
+		var targets []string
+		for _, target := range configuration.Targets {
+			// Interface libraries produce nothing to build
+			if target.Type != TargetInterfaceLibrary {
+				targets = append(targets, target.Name)
+			}
+		}
+		return targets
+	}
+
+	return buildInfo.NinjaTargets
+}
+
+// GetBuildTarget returns the target to pass as --target: SelectedTarget while the selected
+// tree has it (or its targets are not known yet), otherwise empty (build everything).
+// The selection itself survives switching to a tree without the target.
+func (ps *ProjectState) GetBuildTarget() string {
+	if ps.SelectedTarget == "" {
+		return ""
+	}
+	targets := ps.GetTargets()
+	if len(targets) == 0 || slices.Contains(targets, ps.SelectedTarget) {
+		return ps.SelectedTarget
+	}
+	return ""
+}
+
+// CycleTarget advances through "all targets" followed by every target of the selected tree, wrapping around
+func (ps *ProjectState) CycleTarget() {
+	targets := ps.GetTargets()
+	if len(targets) == 0 {
+		ps.SelectedTarget = ""
+		return
+	}
+
+	names := append([]string{""}, targets...)
+	currentIndex := slices.Index(names, ps.GetBuildTarget())
+	if currentIndex < 0 {
+		currentIndex = 0
+	}
+	ps.SelectedTarget = names[(currentIndex+1)%len(names)]
+}
+
+// SetSelectedTarget sets the target directly (restoring from config).
+// Accepted when the selected tree has it or its targets are not known yet.
+func (ps *ProjectState) SetSelectedTarget(name string) {
+	targets := ps.GetTargets()
+	if name == "" || len(targets) == 0 || slices.Contains(targets, name) {
+		ps.SelectedTarget = name
+	}
+}
+
+// GetTargetLabel returns the Target row value: the target name, or "All"
+func (ps *ProjectState) GetTargetLabel() string {
+	if target := ps.GetBuildTarget(); target != "" {
+		return target
+	}
+	return "All"
+}
+
+// Executable is a runnable artifact of the selected build tree
+type Executable struct {
+	Name string // Target name (File API) or file name (scanned)
+	Path string // Absolute path
+}
+
+// GetExecutables returns the executable targets of the selected tree for the current
+// configuration, from the File API reply. Empty when the tree has no reply.
+func (ps *ProjectState) GetExecutables() []Executable {
+	buildInfo := ps.GetSelectedBuildInfo()
+	if buildInfo.CodeModel == nil {
+		return nil
+	}
+	configuration, ok := buildInfo.CodeModel.Configuration(ps.Configuration)
+	if !ok {
+		return nil
+	}
+
+	var executables []Executable
+	for _, target := range configuration.Targets {
+		if target.Type == TargetExecutable && len(target.Artifacts) > 0 {
+			executables = append(executables, Executable{Name: target.Name, Path: target.Artifacts[0]})
+		}
+	}
+	return executables
+}
+
+// GetRunExecutable picks what Run launches: the preferred target (.cake.toml [run] target),
+// else the Target row's target when it is an executable, else the first executable.
+// Without a File API reply the build tree is scanned for executable files instead.
+func (ps *ProjectState) GetRunExecutable(preferred string) (Executable, bool) {
+	executables := ps.GetExecutables()
+	if len(executables) == 0 {
+		executables = scanExecutables(ps.GetBuildPath())
+	}
+	if len(executables) == 0 {
+		return Executable{}, false
+	}
+
+	for _, name := range []string{preferred, ps.GetBuildTarget()} {
+		if name == "" {
+			continue
+		}
+		for _, executable := range executables {
+			if executable.Name == name {
+				return executable, true
+			}
+		}
+	}
+	return executables[0], true
+}
+
+// GetRunLabel returns the Run row value: the executable Run would launch, or "Auto"
+// while only a scan of the built tree can tell
+func (ps *ProjectState) GetRunLabel(preferred string) string {
+	if len(ps.GetExecutables()) == 0 {
+		return "Auto"
+	}
+	executable, _ := ps.GetRunExecutable(preferred)
+	return executable.Name
+}
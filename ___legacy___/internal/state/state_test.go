@@ -2,8 +2,11 @@ package state
 
 import (
 	"github.com/jrengmusic/cake/internal"
+	"github.com/jrengmusic/cake/internal/presets"
 	"github.com/jrengmusic/cake/internal/utils"
+	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -307,7 +310,7 @@ func TestCanBuild(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ps := makeState(gens("Ninja", "Xcode"), tc.selected)
 			if tc.buildInfo.Generator != "" {
-				ps.Builds[tc.selected] = tc.buildInfo
+				ps.Builds[ps.GetBuildPath()] = tc.buildInfo
 			}
 			if got := ps.CanBuild(); got != tc.wantCanBuild {
 				t.Errorf("got %v, want %v", got, tc.wantCanBuild)
@@ -328,6 +331,9 @@ func TestGetProjectLabel(t *testing.T) {
 		{"Xcode returned as-is", "Xcode", "Xcode"},
 		{"VS2026 abbreviated", utils.GeneratorVS2026, "VS 2026"},
 		{"VS2022 abbreviated", utils.GeneratorVS2022, "VS 2022"},
+		{"VS2019 abbreviated", "Visual Studio 16 2019", "VS 2019"},
+		{"Ninja Multi-Config abbreviated", utils.GeneratorNinjaMultiConfig, "Ninja Multi"},
+		{"Unix Makefiles returned as-is", utils.GeneratorUnixMakefiles, "Unix Makefiles"},
 		{"empty returns empty", "", ""},
 	}
 
@@ -341,32 +347,86 @@ func TestGetProjectLabel(t *testing.T) {
 	}
 }
 
+// --- orderGenerators ---
+
+func TestOrderGenerators(t *testing.T) {
+	input := []string{
+		utils.GeneratorUnixMakefiles,
+		utils.GeneratorVS2022,
+		utils.GeneratorNinjaMultiConfig,
+		"CodeBlocks - Ninja",
+		utils.GeneratorNinja,
+		utils.GeneratorXcode,
+	}
+	want := []string{
+		utils.GeneratorXcode,
+		utils.GeneratorNinja,
+		utils.GeneratorVS2022,
+		utils.GeneratorUnixMakefiles,
+		utils.GeneratorNinjaMultiConfig,
+		"CodeBlocks - Ninja",
+	}
+
+	got := orderGenerators(input)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("index %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
 // --- GetBuildDirectory ---
 
 func TestGetBuildDirectory(t *testing.T) {
 	tests := []struct {
 		name      string
 		generator string
+		config    string
 		wantDir   string
 	}{
 		{
-			name:      "Ninja",
+			name:      "Ninja is single-config: per-config dir",
+			generator: "Ninja",
+			config:    internal.ConfigDebug,
+			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "Ninja-Debug"),
+		},
+		{
+			name:      "Ninja Release gets its own dir",
 			generator: "Ninja",
-			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "Ninja"),
+			config:    internal.ConfigRelease,
+			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "Ninja-Release"),
+		},
+		{
+			name:      "Unix Makefiles is single-config",
+			generator: utils.GeneratorUnixMakefiles,
+			config:    internal.ConfigDebug,
+			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "UnixMakefiles-Debug"),
+		},
+		{
+			name:      "Ninja Multi-Config shares one dir",
+			generator: utils.GeneratorNinjaMultiConfig,
+			config:    internal.ConfigRelease,
+			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "NinjaMultiConfig"),
 		},
 		{
 			name:      "Xcode",
 			generator: "Xcode",
+			config:    internal.ConfigDebug,
 			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "Xcode"),
 		},
 		{
 			name:      "VS2026 uses short dir",
 			generator: utils.GeneratorVS2026,
+			config:    internal.ConfigDebug,
 			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "VS2026"),
 		},
 		{
 			name:      "VS2022 uses short dir",
 			generator: utils.GeneratorVS2022,
+			config:    internal.ConfigRelease,
 			wantDir:   filepath.Join("/tmp/testproject", internal.BuildsDirName, "VS2022"),
 		},
 	}
@@ -374,21 +434,119 @@ func TestGetBuildDirectory(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ps := makeState(nil, "")
-			if got := ps.GetBuildDirectory(tc.generator); got != tc.wantDir {
+			if got := ps.GetBuildDirectory(tc.generator, tc.config, utils.VariantDefault); got != tc.wantDir {
 				t.Errorf("got %q, want %q", got, tc.wantDir)
 			}
 		})
 	}
 }
 
+// --- scanBuildDirectories ---
+
+func TestScanBuildDirectories(t *testing.T) {
+	root := t.TempDir()
+	configured := func(dirName string, subdirs ...string) {
+		buildPath := filepath.Join(root, internal.BuildsDirName, dirName)
+		for _, sub := range append(subdirs, "") {
+			if err := os.MkdirAll(filepath.Join(buildPath, sub), 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(buildPath, "CMakeCache.txt"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configured("Ninja-Debug")
+	configured("Ninja-Debug-asan")
+	configured("Xcode", internal.ConfigDebug, internal.ConfigRelease)
+
+	ps := makeState(gens("Ninja", "Xcode"), "Ninja")
+	ps.WorkingDirectory = root
+	ps.scanBuildDirectories(root)
+
+	t.Run("single-config tree records its config", func(t *testing.T) {
+		info := ps.GetSelectedBuildInfo()
+		if !info.IsConfigured || info.Generator != "Ninja" || info.Config != internal.ConfigDebug {
+			t.Errorf("unexpected BuildInfo: %+v", info)
+		}
+	})
+
+	t.Run("switching config selects a different tree", func(t *testing.T) {
+		ps.SetConfiguration(internal.ConfigRelease)
+		defer ps.SetConfiguration(internal.ConfigDebug)
+		if ps.CanBuild() {
+			t.Error("Ninja Release must not reuse the Debug tree")
+		}
+	})
+
+	t.Run("variant tree is separate from the default one", func(t *testing.T) {
+		ps.SetVariant(utils.VariantASan)
+		defer ps.SetVariant(utils.VariantDefault)
+		info := ps.GetSelectedBuildInfo()
+		if !info.IsConfigured || info.Variant != utils.VariantASan || info.Config != internal.ConfigDebug {
+			t.Errorf("unexpected BuildInfo: %+v", info)
+		}
+		if filepath.Base(ps.GetBuildPath()) != "Ninja-Debug-asan" {
+			t.Errorf("unexpected build path %q", ps.GetBuildPath())
+		}
+	})
+
+	t.Run("multi-config tree lists config subdirectories", func(t *testing.T) {
+		info := ps.Builds[ps.GetBuildDirectory("Xcode", internal.ConfigRelease, utils.VariantDefault)]
+		if info.Config != "" || len(info.Configs) != 2 {
+			t.Errorf("unexpected BuildInfo: %+v", info)
+		}
+	})
+}
+
+// --- CycleVariant ---
+
+func TestCycleVariant(t *testing.T) {
+	ps := makeState(gens("Ninja", utils.GeneratorVS2022), "Ninja")
+	ps.Variant = utils.VariantDefault
+
+	var seen []string
+	for range utils.Variants() {
+		ps.CycleVariant()
+		seen = append(seen, ps.Variant)
+	}
+	if len(seen) != 5 || seen[0] != utils.VariantASan || seen[4] != utils.VariantDefault {
+		t.Errorf("Ninja cycle: got %v", seen)
+	}
+
+	// Visual Studio only offers AddressSanitizer
+	ps.SetVariant(utils.VariantTSan)
+	ps.SetSelectedProject(utils.GeneratorVS2022)
+	if ps.Variant != utils.VariantDefault {
+		t.Errorf("tsan must reset on Visual Studio, got %q", ps.Variant)
+	}
+	ps.CycleVariant()
+	ps.CycleVariant()
+	if ps.Variant != utils.VariantDefault {
+		t.Errorf("VS cycle must be default -> asan -> default, got %q", ps.Variant)
+	}
+
+	// Cycling backward onto Visual Studio resets it too
+	ps.SetSelectedProject("Ninja")
+	ps.SetVariant(utils.VariantUBSan)
+	ps.CycleToPrevProject()
+	if ps.SelectedProject != utils.GeneratorVS2022 {
+		t.Fatalf("expected backward cycle to wrap to %q, got %q", utils.GeneratorVS2022, ps.SelectedProject)
+	}
+	if ps.Variant != utils.VariantDefault {
+		t.Errorf("ubsan must reset on Visual Studio after a backward cycle, got %q", ps.Variant)
+	}
+}
+
 // --- GetSelectedBuildInfo ---
 
 func TestGetSelectedBuildInfo(t *testing.T) {
 	t.Run("returns BuildInfo from map when present", func(t *testing.T) {
 		ps := makeState(gens("Ninja"), "Ninja")
-		ps.Builds["Ninja"] = BuildInfo{
+		ps.Builds["/tmp/testproject/Builds/Ninja-Debug"] = BuildInfo{
 			Generator:    "Ninja",
-			Path:         "/tmp/testproject/Builds/Ninja",
+			Config:       internal.ConfigDebug,
+			Path:         "/tmp/testproject/Builds/Ninja-Debug",
 			Exists:       true,
 			IsConfigured: true,
 		}
@@ -444,3 +602,598 @@ func TestParseProjectCallName(t *testing.T) {
 		})
 	}
 }
+
+// --- ResolveProjectRoot ---
+
+func TestResolveProjectRoot(t *testing.T) {
+	root := t.TempDir()
+
+	t.Run("absolute dir returned as-is", func(t *testing.T) {
+		got, err := ResolveProjectRoot(root)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != root {
+			t.Errorf("got %q, want %q", got, root)
+		}
+	})
+
+	t.Run("empty dir resolves to cwd", func(t *testing.T) {
+		cwd, _ := os.Getwd()
+		got, err := ResolveProjectRoot("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != cwd {
+			t.Errorf("got %q, want %q", got, cwd)
+		}
+	})
+
+	t.Run("missing dir rejected", func(t *testing.T) {
+		if _, err := ResolveProjectRoot(filepath.Join(root, "missing")); err == nil {
+			t.Error("expected error for missing directory")
+		}
+	})
+
+	t.Run("file rejected", func(t *testing.T) {
+		file := filepath.Join(root, internal.CMakeListsFile)
+		if err := os.WriteFile(file, []byte("project(Foo)\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ResolveProjectRoot(file); err == nil {
+			t.Error("expected error for regular file")
+		}
+	})
+}
+
+// --- NewProjectState / ForceRefresh ---
+
+func TestForceRefresh_KeepsProjectRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, internal.CMakeListsFile), []byte("project(Foo)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ps := NewProjectState(root)
+	ps.ForceRefresh()
+
+	if ps.WorkingDirectory != root {
+		t.Errorf("WorkingDirectory: got %q, want %q", ps.WorkingDirectory, root)
+	}
+	if !ps.HasCMakeLists {
+		t.Error("expected HasCMakeLists=true for project root, independent of process cwd")
+	}
+	if got, want := ps.GetBuildDirectory("Ninja", internal.ConfigDebug, utils.VariantDefault), filepath.Join(root, internal.BuildsDirName, "Ninja-Debug"); got != want {
+		t.Errorf("GetBuildDirectory: got %q, want %q", got, want)
+	}
+}
+
+// --- Presets ---
+
+func writePresets(t *testing.T, root, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(root, presets.FileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPresets_CycleAndBuildPath(t *testing.T) {
+	root := t.TempDir()
+	writePresets(t, root, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "dev", "generator": "Ninja", "binaryDir": "${sourceDir}/out/dev"},
+			{"name": "bare", "generator": "Ninja Multi-Config"}
+		],
+		"buildPresets": [
+			{"name": "dev-build", "configurePreset": "dev"}
+		]
+	}`)
+
+	ps := NewProjectState(root)
+	ps.ForceRefresh()
+
+	if !ps.HasPresets() {
+		t.Fatal("expected presets to load")
+	}
+	if ps.GetPresetLabel() != "None" {
+		t.Errorf("initial label: got %q, want None", ps.GetPresetLabel())
+	}
+
+	ps.CyclePreset()
+	if ps.SelectedPreset != "dev" {
+		t.Fatalf("after first cycle: got %q, want dev", ps.SelectedPreset)
+	}
+	if want := filepath.Join(root, "out", "dev"); ps.GetBuildPath() != want {
+		t.Errorf("GetBuildPath: got %q, want binaryDir %q", ps.GetBuildPath(), want)
+	}
+	if ps.PresetBinaryDirOverride() != "" {
+		t.Error("preset with binaryDir needs no -B override")
+	}
+	if ps.ActiveGenerator() != "Ninja" {
+		t.Errorf("ActiveGenerator: got %q, want preset generator", ps.ActiveGenerator())
+	}
+	if bp, ok := ps.GetSelectedBuildPreset(); !ok || bp.Name != "dev-build" {
+		t.Errorf("GetSelectedBuildPreset: got (%q, %v)", bp.Name, ok)
+	}
+
+	ps.CyclePreset()
+	if want := filepath.Join(root, internal.BuildsDirName, "bare"); ps.PresetBinaryDirOverride() != want {
+		t.Errorf("override for preset without binaryDir: got %q, want %q", ps.PresetBinaryDirOverride(), want)
+	}
+	if _, ok := ps.GetSelectedBuildPreset(); ok {
+		t.Error("bare has no build preset")
+	}
+
+	ps.CyclePreset()
+	if ps.SelectedPreset != "" {
+		t.Errorf("cycle must wrap back to no preset, got %q", ps.SelectedPreset)
+	}
+}
+
+func TestPresets_BuildPresetMatchesConfiguration(t *testing.T) {
+	root := t.TempDir()
+	writePresets(t, root, `{
+		"version": 3,
+		"configurePresets": [
+			{"name": "multi", "generator": "Ninja Multi-Config"}
+		],
+		"buildPresets": [
+			{"name": "multi-release", "configurePreset": "multi", "configuration": "Release"},
+			{"name": "multi-default", "configurePreset": "multi"}
+		]
+	}`)
+
+	ps := NewProjectState(root)
+	ps.ForceRefresh()
+	ps.CyclePreset()
+
+	ps.SetConfiguration(internal.ConfigRelease)
+	if bp, ok := ps.GetSelectedBuildPreset(); !ok || bp.Name != "multi-release" {
+		t.Errorf("Release: got (%q, %v), want multi-release", bp.Name, ok)
+	}
+
+	// No preset builds Debug: the build falls back to --build <binaryDir> --config Debug
+	ps.SetConfiguration(internal.ConfigDebug)
+	if bp, ok := ps.GetSelectedBuildPreset(); ok {
+		t.Errorf("Debug: got %q, want no build preset", bp.Name)
+	}
+}
+
+func TestPresets_ScanPresetBuildDirectory(t *testing.T) {
+	root := t.TempDir()
+	writePresets(t, root, `{"version": 3, "configurePresets": [{"name": "dev", "generator": "Ninja", "binaryDir": "out"}]}`)
+	if err := os.MkdirAll(filepath.Join(root, "out"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "out", "CMakeCache.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ps := NewProjectState(root)
+	ps.ForceRefresh()
+	ps.SetSelectedPreset("dev")
+
+	if !ps.CanBuild() {
+		t.Error("configured preset binaryDir outside Builds/ must be buildable")
+	}
+}
+
+func TestPresets_InvalidFileAndRemovedSelection(t *testing.T) {
+	root := t.TempDir()
+	writePresets(t, root, `{"version": 3, "configurePresets": [{"name": "dev"}]}`)
+
+	ps := NewProjectState(root)
+	ps.ForceRefresh()
+	ps.SetSelectedPreset("dev")
+	if ps.SelectedPreset != "dev" {
+		t.Fatalf("SetSelectedPreset: got %q", ps.SelectedPreset)
+	}
+	ps.SetSelectedPreset("missing")
+	if ps.SelectedPreset != "dev" {
+		t.Errorf("unknown preset must leave selection unchanged, got %q", ps.SelectedPreset)
+	}
+
+	writePresets(t, root, `{"version": 3, "configurePresets": [`)
+	ps.ForceRefresh()
+	if ps.PresetError == "" || ps.GetPresetLabel() != "Invalid" {
+		t.Errorf("expected load error, got error=%q label=%q", ps.PresetError, ps.GetPresetLabel())
+	}
+	if ps.SelectedPreset != "" {
+		t.Errorf("selection must be dropped when the preset disappears, got %q", ps.SelectedPreset)
+	}
+}
+
+// --- File API reply ---
+
+// writeFileAPIReply fabricates the reply cmake writes for cake's query in buildPath
+func writeFileAPIReply(t *testing.T, buildPath string, files map[string]string) {
+	t.Helper()
+	replyDir := utils.GetFileAPIReplyDirectory(buildPath)
+	if err := os.MkdirAll(replyDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(replyDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// multiConfigReply is a Ninja Multi-Config style reply: two configurations, one executable
+var multiConfigReply = map[string]string{
+	"index-2026-01-01T00-00-00-0000.json": `{"reply": {"client-other": {}}}`,
+	"index-2026-01-02T00-00-00-0000.json": `{"reply": {"client-cake": {"query.json": {"responses": [
+		{"kind": "codemodel", "version": {"major": 2, "minor": 6}, "jsonFile": "codemodel-v2-1.json"},
+		{"kind": "cache", "version": {"major": 2, "minor": 0}, "jsonFile": "cache-v2-1.json"},
+		{"kind": "toolchains", "version": {"major": 1, "minor": 0}, "jsonFile": "toolchains-v1-1.json"}
+	]}}}}`,
+	"codemodel-v2-1.json": `{"configurations": [
+		{"name": "Debug", "targets": [{"name": "app", "id": "app::@1", "jsonFile": "target-app-Debug.json"}]},
+		{"name": "Release", "targets": [{"name": "app", "id": "app::@1", "jsonFile": "target-app-Release.json"}]}
+	]}`,
+	"target-app-Debug.json":   `{"name": "app", "type": "EXECUTABLE", "artifacts": [{"path": "Debug/app"}]}`,
+	"target-app-Release.json": `{"name": "app", "type": "EXECUTABLE", "artifacts": [{"path": "/opt/out/app"}]}`,
+	"cache-v2-1.json": `{"entries": [
+		{"name": "CMAKE_CXX_FLAGS", "value": "-Wall", "type": "STRING", "properties": [{"name": "ADVANCED", "value": "1"}, {"name": "HELPSTRING", "value": "Flags"}]},
+		{"name": "BUILD_TESTING", "value": "ON", "type": "BOOL", "properties": []}
+	]}`,
+	"toolchains-v1-1.json": `{"toolchains": [{"language": "CXX", "compiler": {"id": "GNU", "path": "/usr/bin/c++", "version": "13.2.0"}}]}`,
+}
+
+func TestReadFileAPIReply(t *testing.T) {
+	buildPath := t.TempDir()
+
+	t.Run("no reply yet", func(t *testing.T) {
+		reply, err := ReadFileAPIReply(buildPath)
+		if err != nil || reply != nil {
+			t.Errorf("got (%+v, %v), want (nil, nil)", reply, err)
+		}
+	})
+
+	writeFileAPIReply(t, buildPath, multiConfigReply)
+	reply, err := ReadFileAPIReply(buildPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if names := reply.ConfigurationNames(); len(names) != 2 || names[0] != "Debug" || names[1] != "Release" {
+		t.Errorf("configurations: got %v", names)
+	}
+
+	debug, ok := reply.Configuration("Debug")
+	if !ok || len(debug.Targets) != 1 || debug.Targets[0].Type != TargetExecutable {
+		t.Fatalf("Debug targets: got %+v", debug)
+	}
+	if want := filepath.Join(buildPath, "Debug", "app"); debug.Targets[0].Artifacts[0] != want {
+		t.Errorf("relative artifact: got %q, want %q", debug.Targets[0].Artifacts[0], want)
+	}
+	release, _ := reply.Configuration("Release")
+	if release.Targets[0].Artifacts[0] != "/opt/out/app" {
+		t.Errorf("absolute artifact: got %q", release.Targets[0].Artifacts[0])
+	}
+	if _, ok := reply.Configuration("MinSizeRel"); ok {
+		t.Error("unknown configuration must not match in a multi-config reply")
+	}
+
+	if len(reply.Cache) != 2 || !reply.Cache[0].Advanced || reply.Cache[0].Help != "Flags" || reply.Cache[1].Advanced {
+		t.Errorf("cache: got %+v", reply.Cache)
+	}
+	if len(reply.Toolchains) != 1 || reply.Toolchains[0].CompilerID != "GNU" {
+		t.Errorf("toolchains: got %+v", reply.Toolchains)
+	}
+}
+
+func TestReadFileAPIReply_ErrorResponse(t *testing.T) {
+	buildPath := t.TempDir()
+	writeFileAPIReply(t, buildPath, map[string]string{
+		"index-1.json": `{"reply": {"client-cake": {"query.json": {"responses": [{"kind": "codemodel", "error": "unknown version"}]}}}}`,
+	})
+	if _, err := ReadFileAPIReply(buildPath); err == nil {
+		t.Error("expected error for an error response")
+	}
+}
+
+func TestScanBuildDirectories_FileAPIConfigs(t *testing.T) {
+	root := t.TempDir()
+	buildPath := filepath.Join(root, internal.BuildsDirName, "NinjaMultiConfig")
+	if err := os.MkdirAll(buildPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(buildPath, "CMakeCache.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ps := makeState(gens(utils.GeneratorNinjaMultiConfig), utils.GeneratorNinjaMultiConfig)
+	ps.WorkingDirectory = root
+
+	// No reply and no config subdirectories: nothing to guess from
+	ps.scanBuildDirectories(root)
+	if info := ps.GetSelectedBuildInfo(); len(info.Configs) != 0 || info.CodeModel != nil {
+		t.Fatalf("before reply: got %+v", info)
+	}
+
+	writeFileAPIReply(t, buildPath, multiConfigReply)
+	ps.scanBuildDirectories(root)
+	info := ps.GetSelectedBuildInfo()
+	if info.CodeModel == nil || len(info.Configs) != 2 {
+		t.Errorf("after reply: got %+v", info)
+	}
+}
+
+func TestScanBuildDirectories_ReusesReplyUntilIndexChanges(t *testing.T) {
+	root := t.TempDir()
+	buildPath := filepath.Join(root, internal.BuildsDirName, "NinjaMultiConfig")
+	if err := os.MkdirAll(buildPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(buildPath, "CMakeCache.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeFileAPIReply(t, buildPath, multiConfigReply)
+
+	ps := makeState(gens(utils.GeneratorNinjaMultiConfig), utils.GeneratorNinjaMultiConfig)
+	ps.WorkingDirectory = root
+	ps.scanBuildDirectories(root)
+
+	// A codemodel rewritten behind the same index is not read again
+	debugOnly := `{"configurations": [{"name": "Debug", "targets": [{"name": "app", "id": "app::@1", "jsonFile": "target-app-Debug.json"}]}]}`
+	writeFileAPIReply(t, buildPath, map[string]string{"codemodel-v2-1.json": debugOnly})
+	ps.scanBuildDirectories(root)
+	if configs := ps.GetSelectedBuildInfo().Configs; len(configs) != 2 {
+		t.Errorf("same index: got %v, want the cached 2 configurations", configs)
+	}
+
+	// A new configure writes a newer index, and the reply is read again
+	writeFileAPIReply(t, buildPath, map[string]string{
+		"index-2026-01-03T00-00-00-0000.json": multiConfigReply["index-2026-01-02T00-00-00-0000.json"],
+	})
+	ps.scanBuildDirectories(root)
+	if configs := ps.GetSelectedBuildInfo().Configs; len(configs) != 1 || configs[0] != "Debug" {
+		t.Errorf("new index: got %v, want [Debug]", configs)
+	}
+}
+
+// --- Targets ---
+
+func TestTargets(t *testing.T) {
+	root := t.TempDir()
+	buildPath := filepath.Join(root, internal.BuildsDirName, "Ninja-Debug")
+
+	ps := makeState(gens("Ninja"), "Ninja")
+	ps.WorkingDirectory = root
+
+	t.Run("unknown until configured", func(t *testing.T) {
+		if targets := ps.GetTargets(); len(targets) != 0 {
+			t.Errorf("got %v", targets)
+		}
+		ps.SetSelectedTarget("app") // restored from config before the tree exists
+		if ps.GetBuildTarget() != "app" {
+			t.Errorf("selection must be kept while targets are unknown, got %q", ps.GetBuildTarget())
+		}
+		ps.SelectedTarget = ""
+	})
+
+	ps.Builds[buildPath] = BuildInfo{
+		Generator:    "Ninja",
+		Config:       internal.ConfigDebug,
+		Path:         buildPath,
+		Exists:       true,
+		IsConfigured: true,
+		CodeModel: &FileAPIReply{Configurations: []FileAPIConfiguration{{
+			Name: internal.ConfigDebug,
+			Targets: []Target{
+				{Name: "app", Type: TargetExecutable},
+				{Name: "headers", Type: TargetInterfaceLibrary},
+				{Name: "tests", Type: TargetExecutable},
+			},
+		}}},
+	}
+
+	if targets := ps.GetTargets(); len(targets) != 2 || targets[0] != "app" || targets[1] != "tests" {
+		t.Fatalf("interface libraries must be skipped, got %v", targets)
+	}
+
+	t.Run("cycle wraps through All", func(t *testing.T) {
+		want := []string{"app", "tests", ""}
+		for _, w := range want {
+			ps.CycleTarget()
+			if ps.SelectedTarget != w {
+				t.Errorf("got %q, want %q", ps.SelectedTarget, w)
+			}
+		}
+		if ps.GetTargetLabel() != "All" {
+			t.Errorf("label: got %q", ps.GetTargetLabel())
+		}
+	})
+
+	t.Run("unknown name rejected once targets are known", func(t *testing.T) {
+		ps.SetSelectedTarget("missing")
+		if ps.SelectedTarget != "" {
+			t.Errorf("got %q", ps.SelectedTarget)
+		}
+	})
+
+	t.Run("selection survives a tree without the target", func(t *testing.T) {
+		ps.SetSelectedTarget("tests")
+		info := ps.Builds[buildPath]
+		info.CodeModel = nil
+		info.NinjaTargets = []string{"app"}
+		ps.Builds[buildPath] = info
+
+		if ps.GetBuildTarget() != "" || ps.GetTargetLabel() != "All" {
+			t.Errorf("missing target must build everything, got %q", ps.GetBuildTarget())
+		}
+		if ps.SelectedTarget != "tests" {
+			t.Errorf("selection lost: %q", ps.SelectedTarget)
+		}
+	})
+}
+
+func TestGetRunExecutable(t *testing.T) {
+	root := t.TempDir()
+	buildPath := filepath.Join(root, internal.BuildsDirName, "Ninja-Debug")
+
+	ps := makeState(gens("Ninja"), "Ninja")
+	ps.WorkingDirectory = root
+
+	t.Run("scanned without a File API reply", func(t *testing.T) {
+		for path, mode := range map[string]os.FileMode{
+			"tools/zeta":                   0755,
+			"app":                          0755,
+			"libcore.so.1":                 0755,
+			"notes.txt":                    0644,
+			"CMakeFiles/CompilerIdC/a.out": 0755,
+		} {
+			full := filepath.Join(buildPath, path)
+			if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(full, nil, mode); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if runtime.GOOS == "windows" {
+			t.Skip("executables are found by extension on Windows")
+		}
+
+		executable, ok := ps.GetRunExecutable("")
+		if !ok || executable.Path != filepath.Join(buildPath, "app") {
+			t.Errorf("expected the first executable by path, got %+v", executable)
+		}
+		if executable, _ := ps.GetRunExecutable("zeta"); executable.Name != "zeta" {
+			t.Errorf("preferred target not picked, got %+v", executable)
+		}
+		if ps.GetRunLabel("") != "Auto" {
+			t.Errorf("label must stay Auto until the File API lists executables, got %q", ps.GetRunLabel(""))
+		}
+	})
+
+	ps.Builds[buildPath] = BuildInfo{
+		Generator:    "Ninja",
+		Config:       internal.ConfigDebug,
+		Path:         buildPath,
+		Exists:       true,
+		IsConfigured: true,
+		CodeModel: &FileAPIReply{Configurations: []FileAPIConfiguration{{
+			Name: internal.ConfigDebug,
+			Targets: []Target{
+				{Name: "core", Type: TargetStaticLibrary, Artifacts: []string{"/b/libcore.a"}},
+				{Name: "app", Type: TargetExecutable, Artifacts: []string{"/b/app"}},
+				{Name: "tests", Type: TargetExecutable, Artifacts: []string{"/b/tests"}},
+			},
+		}}},
+	}
+
+	t.Run("File API executables", func(t *testing.T) {
+		if executable, _ := ps.GetRunExecutable(""); executable.Path != "/b/app" {
+			t.Errorf("expected the first executable, got %+v", executable)
+		}
+		ps.SelectedTarget = "tests"
+		if ps.GetRunLabel("") != "tests" {
+			t.Errorf("Target row executable not picked, got %q", ps.GetRunLabel(""))
+		}
+		ps.SelectedTarget = "core"
+		if ps.GetRunLabel("") != "app" {
+			t.Errorf("a library on the Target row must fall back to the first executable, got %q", ps.GetRunLabel(""))
+		}
+		if ps.GetRunLabel("tests") != "tests" {
+			t.Errorf("preferred target not picked, got %q", ps.GetRunLabel("tests"))
+		}
+	})
+}
+
+// --- CMakeCache.txt ---
+
+const sampleCMakeCache = `# This is the CMakeCache file.
+# For build in directory: /tmp/proj/Builds/Ninja
+
+########################
+# EXTERNAL cache entries
+########################
+
+//Build shared libraries
+BUILD_SHARED_LIBS:BOOL=OFF
+
+//Choose the type of build, options are: None Debug Release
+// RelWithDebInfo MinSizeRel ...
+CMAKE_BUILD_TYPE:STRING=Debug
+
+//Path to a program.
+CMAKE_AR:FILEPATH=/usr/bin/ar
+
+//No help, variable specified on the command line.
+"WEIRD NAME":UNINITIALIZED=x
+
+########################
+# INTERNAL cache entries
+########################
+
+//ADVANCED property for variable: CMAKE_AR
+CMAKE_AR-ADVANCED:INTERNAL=1
+//STRINGS property for variable: CMAKE_BUILD_TYPE
+CMAKE_BUILD_TYPE-STRINGS:INTERNAL=Debug;Release;RelWithDebInfo;MinSizeRel
+//This is the directory where this CMakeCache.txt was created
+CMAKE_CACHEFILE_DIR:INTERNAL=/tmp/proj/Builds/Ninja
+`
+
+func TestParseCMakeCache(t *testing.T) {
+	entries := ParseCMakeCache([]byte(sampleCMakeCache))
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	want := []string{"BUILD_SHARED_LIBS", "CMAKE_AR", "CMAKE_BUILD_TYPE", "WEIRD NAME"}
+	if len(names) != len(want) {
+		t.Fatalf("entries = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("entries = %v, want %v", names, want)
+		}
+	}
+
+	buildType := entries[2]
+	if buildType.Type != CacheTypeString || buildType.Value != "Debug" {
+		t.Errorf("CMAKE_BUILD_TYPE = %+v", buildType)
+	}
+	if buildType.Help != "Choose the type of build, options are: None Debug Release RelWithDebInfo MinSizeRel ..." {
+		t.Errorf("multi-line help not joined: %q", buildType.Help)
+	}
+	if len(buildType.Strings) != 4 || buildType.Strings[1] != "Release" {
+		t.Errorf("STRINGS property not folded in: %v", buildType.Strings)
+	}
+	if !entries[1].Advanced || entries[0].Advanced {
+		t.Error("ADVANCED property should mark CMAKE_AR only")
+	}
+	if entries[3].Type != CacheTypeUninitialized || entries[3].Value != "x" {
+		t.Errorf("quoted name entry = %+v", entries[3])
+	}
+}
+
+func TestReadCMakeCache_Missing(t *testing.T) {
+	if _, err := ReadCMakeCache(t.TempDir()); err == nil {
+		t.Error("expected error for a tree without CMakeCache.txt")
+	}
+}
+
+func TestIsCMakeTrue(t *testing.T) {
+	for value, want := range map[string]bool{
+		"ON": true, "yes": true, "TRUE": true, "Y": true, "1": true, "2": true,
+		"OFF": false, "NO": false, "FALSE": false, "0": false, "": false, "NOTFOUND": false,
+	} {
+		if got := IsCMakeTrue(value); got != want {
+			t.Errorf("IsCMakeTrue(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestCacheDefinition(t *testing.T) {
+	if got := CacheDefinition(CacheEntry{Name: "FOO", Type: CacheTypeBool}, "ON"); got != "FOO:BOOL=ON" {
+		t.Errorf("typed definition = %q", got)
+	}
+	if got := CacheDefinition(CacheEntry{Name: "BAR", Type: CacheTypeUninitialized}, "x"); got != "BAR=x" {
+		t.Errorf("untyped definition = %q", got)
+	}
+}
@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SGRStyle is the text style set by a tool's SGR escape sequences ("\x1b[1;31m").
+// Colors are lipgloss color strings: "0"-"255" for the terminal palette, "#rrggbb" for
+// true color, empty for the console's own color.
+type SGRStyle struct {
+	Foreground string
+	Background string
+	Bold       bool
+	Faint      bool
+	Italic     bool
+	Underline  bool
+	Reverse    bool
+}
+
+// StyledSpan is a run of text in one SGRStyle
+type StyledSpan struct {
+	Text  string
+	Style SGRStyle
+}
+
+// ParseSGR splits text at its escape sequences: it returns the text without them and,
+// when any SGR sequence styled part of it, the styled spans (nil for text with no colors).
+// Escape sequences other than SGR are dropped.
+func ParseSGR(text string) (string, []StyledSpan) {
+	if !strings.Contains(text, "\x1b") {
+		return text, nil
+	}
+
+	var plain, current strings.Builder
+	var spans []StyledSpan
+	var style SGRStyle
+	styled := false
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		if n := len(spans); n > 0 && spans[n-1].Style == style {
+			spans[n-1].Text += current.String()
+		} else {
+			spans = append(spans, StyledSpan{Text: current.String(), Style: style})
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] != '\x1b' {
+			plain.WriteByte(text[i])
+			current.WriteByte(text[i])
+			i++
+			continue
+		}
+		end, final, params := scanEscape(text, i)
+		if final == 'm' {
+			flush()
+			style = applySGR(style, params)
+			styled = styled || style != SGRStyle{}
+		}
+		i = end
+	}
+	flush()
+
+	if !styled {
+		return plain.String(), nil
+	}
+	return plain.String(), spans
+}
+
+// scanEscape returns the end of the escape sequence starting at text[start], its final
+// byte and, for a CSI sequence, its parameters ("1;31")
+func scanEscape(text string, start int) (end int, final byte, params string) {
+	i := start + 1
+	if i >= len(text) {
+		return i, 0, ""
+	}
+	switch text[i] {
+	case '[':
+		// CSI: parameter bytes 0x30-0x3F, intermediate bytes 0x20-0x2F, final byte 0x40-0x7E
+		i++
+		paramStart := i
+		for i < len(text) && text[i] >= 0x30 && text[i] <= 0x3F {
+			i++
+		}
+		paramEnd := i
+		for i < len(text) && text[i] >= 0x20 && text[i] <= 0x2F {
+			i++
+		}
+		if i < len(text) {
+			return i + 1, text[i], text[paramStart:paramEnd]
+		}
+		return i, 0, ""
+	case ']':
+		// OSC: terminated by BEL or ESC \
+		for i++; i < len(text); i++ {
+			if text[i] == '\x07' {
+				return i + 1, 0, ""
+			}
+			if text[i] == '\x1b' && i+1 < len(text) && text[i+1] == '\\' {
+				return i + 2, 0, ""
+			}
+		}
+		return i, 0, ""
+	default:
+		// Two-byte sequence, e.g. "\x1b(B"
+		if text[i] >= 0x20 && text[i] <= 0x2F && i+1 < len(text) {
+			return i + 2, 0, ""
+		}
+		return i + 1, 0, ""
+	}
+}
+
+// applySGR returns style with the SGR parameters applied
+func applySGR(style SGRStyle, params string) SGRStyle {
+	// ':' separates sub-parameters ("38:2::255:0:0"); the empty color-space id is skipped below
+	fields := strings.FieldsFunc(strings.ReplaceAll(params, ":", ";"), func(r rune) bool { return r == ';' })
+	if params == "" {
+		fields = []string{"0"}
+	}
+	codes := make([]int, 0, len(fields))
+	for _, field := range fields {
+		code, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		codes = append(codes, code)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+		switch {
+		case code == 0:
+			style = SGRStyle{}
+		case code == 1:
+			style.Bold = true
+		case code == 2:
+			style.Faint = true
+		case code == 3:
+			style.Italic = true
+		case code == 4:
+			style.Underline = true
+		case code == 7:
+			style.Reverse = true
+		case code == 22:
+			style.Bold, style.Faint = false, false
+		case code == 23:
+			style.Italic = false
+		case code == 24:
+			style.Underline = false
+		case code == 27:
+			style.Reverse = false
+		case code >= 30 && code <= 37:
+			style.Foreground = strconv.Itoa(code - 30)
+		case code >= 90 && code <= 97:
+			style.Foreground = strconv.Itoa(code - 90 + 8)
+		case code == 39:
+			style.Foreground = ""
+		case code >= 40 && code <= 47:
+			style.Background = strconv.Itoa(code - 40)
+		case code >= 100 && code <= 107:
+			style.Background = strconv.Itoa(code - 100 + 8)
+		case code == 49:
+			style.Background = ""
+		case code == 38 || code == 48:
+			color, used := extendedColor(codes[i+1:])
+			i += used
+			if code == 38 {
+				style.Foreground = color
+			} else {
+				style.Background = color
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor reads the "5;n" (palette) or "2;r;g;b" (true color) tail of SGR 38/48 and
+// returns the color and how many codes it used
+func extendedColor(codes []int) (string, int) {
+	if len(codes) >= 2 && codes[0] == 5 {
+		return strconv.Itoa(codes[1]), 2
+	}
+	if len(codes) >= 4 && codes[0] == 2 {
+		return fmt.Sprintf("#%02x%02x%02x", codes[1]&0xff, codes[2]&0xff, codes[3]&0xff), 4
+	}
+	return "", len(codes)
+}
+
+// renderSpans renders spans with their own styles; a span without a foreground color
+// takes defaultColor
+func renderSpans(spans []StyledSpan, defaultColor string) string {
+	var rendered strings.Builder
+	for _, span := range spans {
+		foreground := span.Style.Foreground
+		if foreground == "" {
+			foreground = defaultColor
+		}
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(foreground)).
+			Bold(span.Style.Bold).
+			Faint(span.Style.Faint).
+			Italic(span.Style.Italic).
+			Underline(span.Style.Underline).
+			Reverse(span.Style.Reverse)
+		if span.Style.Background != "" {
+			style = style.Background(lipgloss.Color(span.Style.Background))
+		}
+		rendered.WriteString(style.Render(span.Text))
+	}
+	return rendered.String()
+}
+
+// wrapSpans breaks spans into display lines of at most width cells, splitting a span
+// where a line ends so each piece keeps its style
+func wrapSpans(spans []StyledSpan, width int) [][]StyledSpan {
+	if width < 1 {
+		width = 1
+	}
+	var lines [][]StyledSpan
+	var line []StyledSpan
+	lineWidth := 0
+	for _, span := range spans {
+		var piece strings.Builder
+		for _, r := range span.Text {
+			runeWidth := lipgloss.Width(string(r))
+			if lineWidth+runeWidth > width && lineWidth > 0 {
+				if piece.Len() > 0 {
+					line = append(line, StyledSpan{Text: piece.String(), Style: span.Style})
+					piece.Reset()
+				}
+				lines = append(lines, line)
+				line, lineWidth = nil, 0
+			}
+			piece.WriteRune(r)
+			lineWidth += runeWidth
+		}
+		if piece.Len() > 0 {
+			line = append(line, StyledSpan{Text: piece.String(), Style: span.Style})
+		}
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
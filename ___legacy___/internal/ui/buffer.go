@@ -1,6 +1,10 @@
 package ui
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
 	"sync"
 	"time"
 )
@@ -20,17 +24,22 @@ const (
 
 // OutputLine represents a single line in the output buffer
 type OutputLine struct {
-	Time string         // Timestamp in HH:MM:SS format
-	Type OutputLineType // Line type for color coding
-	Text string         // Line content
+	Time  string         // Timestamp in HH:MM:SS format
+	Type  OutputLineType // Line type for color coding
+	Text  string         // Line content, escape sequences removed
+	Spans []StyledSpan   // Text in the colors the tool wrote; nil when it wrote none
 }
 
 // OutputBuffer is a circular buffer for storing console output
-// Thread-safe singleton pattern (accessed from multiple goroutines)
+// Thread-safe singleton pattern (accessed from multiple goroutines).
+// Only the last maxLines are kept for rendering; SpoolTo writes every line to a log.
 type OutputBuffer struct {
 	mu       sync.RWMutex
 	maxLines int
 	lines    []OutputLine
+
+	log        io.WriteCloser // Operation log every line is spooled to; nil = none
+	logPending *OutputLine    // Last line, written once no progress update can replace it
 }
 
 // Global singleton instance (1000 line buffer)
@@ -44,7 +53,8 @@ func GetBuffer() *OutputBuffer {
 	return globalBuffer
 }
 
-// Append adds a new line to the buffer with automatic timestamp
+// Append adds a new line to the buffer with automatic timestamp.
+// SGR color sequences in text are parsed into Spans; Text keeps the plain content.
 // Thread-safe for concurrent writes
 func (b *OutputBuffer) Append(text string, lineType OutputLineType) {
 	b.mu.Lock()
@@ -53,13 +63,16 @@ func (b *OutputBuffer) Append(text string, lineType OutputLineType) {
 	now := time.Now()
 	timestamp := now.Format("15:04:05") // HH:MM:SS
 
+	plain, spans := ParseSGR(text)
 	line := OutputLine{
-		Time: timestamp,
-		Type: lineType,
-		Text: text,
+		Time:  timestamp,
+		Type:  lineType,
+		Text:  plain,
+		Spans: spans,
 	}
 
 	b.lines = append(b.lines, line)
+	b.spoolLocked(line, false)
 
 	// Maintain circular buffer (remove oldest if exceeds max)
 	if len(b.lines) > b.maxLines {
@@ -78,10 +91,12 @@ func (b *OutputBuffer) ReplaceLast(text string, lineType OutputLineType) {
 	now := time.Now()
 	timestamp := now.Format("15:04:05")
 
+	plain, spans := ParseSGR(text)
 	line := OutputLine{
-		Time: timestamp,
-		Type: lineType,
-		Text: text,
+		Time:  timestamp,
+		Type:  lineType,
+		Text:  plain,
+		Spans: spans,
 	}
 
 	if len(b.lines) > 0 {
@@ -89,6 +104,75 @@ func (b *OutputBuffer) ReplaceLast(text string, lineType OutputLineType) {
 	} else {
 		b.lines = append(b.lines, line)
 	}
+	b.spoolLocked(line, true)
+}
+
+// SpoolTo writes every line added from now on to log, as "HH:MM:SS [type] text", and closes
+// the previous log. A line rewritten by progress updates (ReplaceLast) is written once, in
+// its final form. nil stops spooling. Thread-safe for concurrent writes.
+func (b *OutputBuffer) SpoolTo(log io.WriteCloser) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flushLogLocked()
+	if b.log != nil {
+		// discard: every line is already written; nothing actionable remains if close fails
+		_ = b.log.Close()
+	}
+	b.log = log
+}
+
+// spoolLocked holds line back until the next line shows it is final; replace overwrites the held line
+func (b *OutputBuffer) spoolLocked(line OutputLine, replace bool) {
+	if b.log == nil {
+		return
+	}
+	if !replace {
+		b.flushLogLocked()
+	}
+	b.logPending = &line
+}
+
+func (b *OutputBuffer) flushLogLocked() {
+	if b.log != nil && b.logPending != nil {
+		// discard: a full disk must not stop the operation; the console still shows the output
+		_, _ = fmt.Fprintf(b.log, "%s [%s] %s\n", b.logPending.Time, b.logPending.Type, b.logPending.Text)
+	}
+	b.logPending = nil
+}
+
+// logLinePattern matches a line SpoolTo wrote: "HH:MM:SS [type] text"
+var logLinePattern = regexp.MustCompile(`^(\d\d:\d\d:\d\d) \[(\w+)\](?: (.*))?$`)
+
+// logClockPattern finds the time in a log line outside that format, e.g. the header
+var logClockPattern = regexp.MustCompile(`\d\d:\d\d:\d\d`)
+
+// ReadLog reads an operation log written by SpoolTo into a buffer holding every line of it,
+// for viewing a past operation. Lines outside the log format, such as the header, are kept
+// as info lines.
+func ReadLog(r io.Reader) (*OutputBuffer, error) {
+	var lines []OutputLine
+	lastTime := "00:00:00"
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if match := logLinePattern.FindStringSubmatch(text); match != nil {
+			lastTime = match[1]
+			lines = append(lines, OutputLine{Time: match[1], Type: OutputLineType(match[2]), Text: match[3]})
+			continue
+		}
+		if clock := logClockPattern.FindString(text); clock != "" {
+			lastTime = clock
+		}
+		lines = append(lines, OutputLine{Time: lastTime, Type: TypeInfo, Text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ReadLog: %w", err)
+	}
+
+	return &OutputBuffer{maxLines: len(lines), lines: lines}, nil
 }
 
 // GetLines returns a slice of lines from startIdx to startIdx+count
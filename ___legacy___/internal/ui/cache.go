@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	cacheMaxWidth      = 96
+	cacheMarkColWidth  = 2
+	cacheNameColWidth  = 36
+	cacheTypeColWidth  = 10
+	cacheMinValueWidth = 12
+	cacheHeaderLines   = 2 // summary + separator
+	cacheFooterLines   = 2 // separator + help
+	cacheModifiedMark  = "*"
+)
+
+// CacheRow is one cache entry in the cache browser
+type CacheRow struct {
+	Name     string
+	Type     string // BOOL, PATH, FILEPATH, STRING, UNINITIALIZED
+	Value    string // Pending value when Modified
+	Help     string
+	Modified bool // Edited, not yet applied
+}
+
+// CacheViewState is what the cache browser is rendered from
+type CacheViewState struct {
+	Rows         []CacheRow // Entries matching Search (and the advanced toggle)
+	Total        int        // Entries before filtering
+	Search       string
+	ShowAdvanced bool
+	Pending      int // Edited entries, across the whole cache
+}
+
+// RenderCacheView renders the cache browser: a summary line, NAME | TYPE | VALUE rows
+// scrolled to keep selectedIndex visible, and the selected entry's help string
+func RenderCacheView(state CacheViewState, selectedIndex int, theme Theme, contentHeight int, contentWidth int) string {
+	boxWidth := contentWidth
+	if boxWidth > cacheMaxWidth {
+		boxWidth = cacheMaxWidth
+	}
+	nameColWidth := cacheNameColWidth
+	valueColWidth := boxWidth - cacheMarkColWidth - nameColWidth - cacheTypeColWidth
+	if valueColWidth < cacheMinValueWidth {
+		valueColWidth = cacheMinValueWidth
+		nameColWidth = boxWidth - cacheMarkColWidth - cacheTypeColWidth - valueColWidth
+	}
+
+	lines := []string{
+		renderCacheSummary(state, theme, boxWidth),
+		renderMenuSeparator(theme, boxWidth),
+	}
+
+	start, end := scrollWindow(selectedIndex, len(state.Rows), contentHeight-2-cacheHeaderLines-cacheFooterLines)
+	for i := start; i < end; i++ {
+		lines = append(lines, renderCacheRow(state.Rows[i], i == selectedIndex, theme, nameColWidth, valueColWidth))
+	}
+
+	help := ""
+	if selectedIndex >= 0 && selectedIndex < len(state.Rows) {
+		help = state.Rows[selectedIndex].Help
+	}
+	lines = append(lines,
+		renderMenuSeparator(theme, boxWidth),
+		lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor)).Render(truncateToWidth(help, boxWidth)),
+	)
+
+	return assembleMenuOutput(lines, contentHeight, contentWidth, boxWidth)
+}
+
+func renderCacheSummary(state CacheViewState, theme Theme, width int) string {
+	parts := []string{fmt.Sprintf("%d of %d entries", len(state.Rows), state.Total)}
+	if state.Search != "" {
+		parts = append(parts, "search: "+state.Search)
+	}
+	if state.ShowAdvanced {
+		parts = append(parts, "advanced shown")
+	}
+	if state.Pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", state.Pending))
+	}
+
+	color := theme.LabelTextColor
+	if state.Pending > 0 {
+		color = theme.OutputWarningColor
+	}
+	summary := truncateToWidth(strings.Join(parts, " · "), width)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(summary)
+}
+
+func renderCacheRow(row CacheRow, isSelected bool, theme Theme, nameColWidth, valueColWidth int) string {
+	mark := ""
+	if row.Modified {
+		mark = cacheModifiedMark
+	}
+
+	markCol := PadLineToWidth(mark, cacheMarkColWidth)
+	nameCol := PadLineToWidth(truncateToWidth(row.Name, nameColWidth-1), nameColWidth)
+	typeCol := PadLineToWidth(row.Type, cacheTypeColWidth)
+	valueCol := PadLineToWidth(truncateToWidth(row.Value, valueColWidth), valueColWidth)
+
+	markStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.OutputWarningColor))
+	typeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ContentTextColor))
+	if row.Modified {
+		valueStyle = valueStyle.Foreground(lipgloss.Color(theme.OutputWarningColor))
+	}
+	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LabelTextColor))
+	if isSelected {
+		nameStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.MainBackgroundColor)).
+			Background(lipgloss.Color(theme.MenuSelectionBackground)).
+			Bold(true)
+	}
+
+	return markStyle.Render(markCol) + nameStyle.Render(nameCol) + typeStyle.Render(typeCol) + valueStyle.Render(valueCol)
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	cmakeArgsMaxWidth     = 96
+	cmakeArgsKindColWidth = 6
+	cmakeArgsHeaderLines  = 2 // summary + separator
+	cmakeArgsFooterLines  = 2 // separator + command preview
+)
+
+// CMake argument kinds, as shown in the kind column
+const (
+	CMakeArgDefinition = "-D"
+	CMakeArgExtra      = "arg"
+)
+
+// CMakeArgRow is one saved extra configure argument
+type CMakeArgRow struct {
+	Kind  string // CMakeArgDefinition or CMakeArgExtra
+	Value string // NAME[:TYPE]=VALUE for definitions, the argument itself otherwise
+}
+
+// CMakeArgsViewState is what the extra cmake arguments editor is rendered from
+type CMakeArgsViewState struct {
+	Generator string
+	List      string        // Name of the list being edited; empty when the generator has none
+	ListCount int           // Lists kept for the generator
+	InUse     bool          // The list is the one passed to the generator's configures
+	Rows      []CMakeArgRow // Definitions first, then arguments
+	Preview   string        // What the rows add to the configure command line
+}
+
+// RenderCMakeArgsView renders the extra cmake arguments editor: a summary line, KIND | VALUE rows
+// scrolled to keep selectedIndex visible, and the resulting command line tail
+func RenderCMakeArgsView(state CMakeArgsViewState, selectedIndex int, theme Theme, contentHeight int, contentWidth int) string {
+	boxWidth := contentWidth
+	if boxWidth > cmakeArgsMaxWidth {
+		boxWidth = cmakeArgsMaxWidth
+	}
+	valueColWidth := boxWidth - cmakeArgsKindColWidth
+
+	lines := []string{
+		renderCMakeArgsSummary(state, theme, boxWidth),
+		renderMenuSeparator(theme, boxWidth),
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	if len(state.Rows) == 0 {
+		lines = append(lines, dimStyle.Render(PadLineToWidth("No extra arguments yet", boxWidth)))
+	}
+	start, end := scrollWindow(selectedIndex, len(state.Rows), contentHeight-2-cmakeArgsHeaderLines-cmakeArgsFooterLines)
+	for i := start; i < end; i++ {
+		lines = append(lines, renderCMakeArgRow(state.Rows[i], i == selectedIndex, theme, valueColWidth))
+	}
+
+	lines = append(lines,
+		renderMenuSeparator(theme, boxWidth),
+		dimStyle.Render(truncateToWidth(state.Preview, boxWidth)),
+	)
+
+	return assembleMenuOutput(lines, contentHeight, contentWidth, boxWidth)
+}
+
+func renderCMakeArgsSummary(state CMakeArgsViewState, theme Theme, width int) string {
+	definitions := 0
+	for _, row := range state.Rows {
+		if row.Kind == CMakeArgDefinition {
+			definitions++
+		}
+	}
+	list := "no lists yet"
+	if state.List != "" {
+		list = "list " + state.List + " · not in use"
+		if state.InUse {
+			list = "list " + state.List + " · in use"
+		}
+		list += " · " + plural(state.ListCount, "list")
+	}
+	summary := fmt.Sprintf("%s · %s · definitions: %d · arguments: %d", state.Generator, list, definitions, len(state.Rows)-definitions)
+	summary = truncateToWidth(summary, width)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LabelTextColor)).Bold(true).Render(summary)
+}
+
+func renderCMakeArgRow(row CMakeArgRow, isSelected bool, theme Theme, valueColWidth int) string {
+	kindCol := PadLineToWidth(row.Kind, cmakeArgsKindColWidth)
+	valueCol := PadLineToWidth(truncateToWidth(row.Value, valueColWidth), valueColWidth)
+
+	kindStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ContentTextColor))
+	if isSelected {
+		valueStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.MainBackgroundColor)).
+			Background(lipgloss.Color(theme.MenuSelectionBackground)).
+			Bold(true)
+	}
+	return kindStyle.Render(kindCol) + valueStyle.Render(valueCol)
+}
+
+// CMakeArgsPreview returns the configure command line tail the rows produce
+func CMakeArgsPreview(rows []CMakeArgRow) string {
+	parts := []string{"cmake …"}
+	for _, row := range rows {
+		if row.Kind == CMakeArgDefinition {
+			parts = append(parts, "-D"+row.Value)
+		} else {
+			parts = append(parts, row.Value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
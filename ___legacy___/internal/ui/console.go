@@ -18,6 +18,8 @@ var opLabels = map[OpType]string{
 	OpClean:      "CLEANING",
 	OpCleanAll:   "CLEANING ALL",
 	OpRegenerate: "REGENERATING",
+	OpTest:       "TESTING",
+	OpRun:        "RUNNING",
 }
 
 // ConsoleOutState holds the scrolling state for console output
@@ -55,12 +57,25 @@ func (s *ConsoleOutState) ScrollDown() {
 	}
 }
 
+// ScrollPageUp moves the viewport up by one page
+func (s *ConsoleOutState) ScrollPageUp() {
+	s.ScrollOffset = clampScrollOffset(s.ScrollOffset-s.LinesPerPage, s.MaxScroll)
+}
+
+// ScrollPageDown moves the viewport down by one page
+func (s *ConsoleOutState) ScrollPageDown() {
+	s.ScrollOffset = clampScrollOffset(s.ScrollOffset+s.LinesPerPage, s.MaxScroll)
+}
+
 // RenderConsoleOutput renders console output for full-screen mode (footer handled externally)
-// Takes terminal dimensions directly, returns content that occupies full terminal
+// Takes terminal dimensions directly, returns content that occupies full terminal.
+// toolColors keeps the colors tools wrote (OutputLine.Spans); otherwise every line takes
+// its type's Output*Color from the theme.
 func RenderConsoleOutput(
 	state *ConsoleOutState,
 	buffer *OutputBuffer,
 	palette Theme,
+	toolColors bool,
 	maxWidth int,
 	totalHeight int,
 	operationInProgress bool,
@@ -87,7 +102,7 @@ func RenderConsoleOutput(
 			totalDisplayLines := countDisplayLines(snapshotLines, wrapWidth)
 			applyScrollState(state, totalDisplayLines, contentHeight, autoScroll)
 
-			visibleLines := formatVisibleLines(snapshotLines, totalBufferLines, palette, wrapWidth, state.ScrollOffset, contentHeight)
+			visibleLines := formatVisibleLines(snapshotLines, totalBufferLines, palette, toolColors, wrapWidth, state.ScrollOffset, contentHeight)
 			visibleLines = padLinesToWidth(visibleLines, wrapWidth)
 			visibleLines = padLinesToHeight(visibleLines, contentHeight, wrapWidth)
 
@@ -133,18 +148,33 @@ func countDisplayLines(snapshotLines []OutputLine, wrapWidth int) int {
 }
 
 // renderEntry renders a single buffer entry and splits it into display lines.
-func renderEntry(line OutputLine, palette Theme, wrapWidth int) []string {
+func renderEntry(line OutputLine, palette Theme, toolColors bool, wrapWidth int) []string {
 	colorMap := consoleLineColorMap(palette)
 	color := colorMap[line.Type]
 	if color == "" {
 		color = palette.OutputStdoutColor
 	}
+	if toolColors && line.Spans != nil {
+		return renderStyledEntry(line, color, wrapWidth)
+	}
 	lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
 	formatted := fmt.Sprintf("[%s] %s", line.Time, line.Text)
 	renderedLine := lineStyle.Width(wrapWidth).Render(formatted)
 	return strings.Split(renderedLine, "\n")
 }
 
+// renderStyledEntry renders an entry in the tool's own colors, hard-wrapped at wrapWidth cells
+// (the count countEntryDisplayLines expects). Unstyled text takes the line type's color.
+func renderStyledEntry(line OutputLine, color string, wrapWidth int) []string {
+	spans := append([]StyledSpan{{Text: "[" + line.Time + "] "}}, line.Spans...)
+	wrapped := wrapSpans(spans, wrapWidth)
+	rendered := make([]string, 0, len(wrapped))
+	for _, displayLine := range wrapped {
+		rendered = append(rendered, renderSpans(displayLine, color))
+	}
+	return rendered
+}
+
 // collectVisibleFromEntry takes rendered display lines for one entry and appends
 // those that fall within [scrollOffset, scrollOffset+contentHeight), given the
 // entry starts at displayLineBase in the global display-line coordinate space.
@@ -173,6 +203,7 @@ func formatVisibleLines(
 	snapshotLines []OutputLine,
 	totalBufferLines int,
 	palette Theme,
+	toolColors bool,
 	wrapWidth int,
 	scrollOffset int,
 	contentHeight int,
@@ -191,7 +222,7 @@ func formatVisibleLines(
 			entryEndLine := displayLineAccumulator + entryLineCount
 			entryInWindow := entryEndLine > scrollOffset && len(visibleLines) < contentHeight
 			if entryInWindow {
-				entryLines := renderEntry(line, palette, wrapWidth)
+				entryLines := renderEntry(line, palette, toolColors, wrapWidth)
 				visibleLines = collectVisibleFromEntry(visibleLines, entryLines, displayLineAccumulator, scrollOffset, contentHeight)
 			}
 			displayLineAccumulator += entryLineCount
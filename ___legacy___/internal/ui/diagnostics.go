@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	diagnosticsMaxWidth         = 120
+	diagnosticGlyphColWidth     = 3
+	diagnosticLocationColWidth  = 36
+	diagnosticsMinMessageWidth  = 16
+	diagnosticsHeaderLines      = 2 // summary + separator
+	diagnosticsDetailLines      = 4 // separator + location + message + first note
+	diagnosticsMinLocationWidth = 12
+)
+
+// DiagnosticRow is one error or warning in the error list
+type DiagnosticRow struct {
+	Location string // "file:line:col", or the reporting tool
+	Message  string // Including the MSVC/linker code, if any
+	Notes    []string
+	IsError  bool
+}
+
+// DiagnosticsViewState is what the error list is rendered from
+type DiagnosticsViewState struct {
+	Rows       []DiagnosticRow // Rows shown: all, or only errors when ErrorsOnly
+	Errors     int             // Errors of the last build, before filtering
+	Warnings   int             // Warnings of the last build
+	ErrorsOnly bool
+}
+
+// RenderDiagnostics renders the error list: a count summary, GLYPH | LOCATION | MESSAGE rows
+// scrolled to keep selectedIndex visible, and the selected diagnostic in full with its first note
+func RenderDiagnostics(state DiagnosticsViewState, selectedIndex int, theme Theme, contentHeight int, contentWidth int) string {
+	boxWidth := contentWidth
+	if boxWidth > diagnosticsMaxWidth {
+		boxWidth = diagnosticsMaxWidth
+	}
+	locationColWidth := diagnosticLocationColWidth
+	messageColWidth := boxWidth - diagnosticGlyphColWidth - locationColWidth
+	if messageColWidth < diagnosticsMinMessageWidth {
+		messageColWidth = diagnosticsMinMessageWidth
+		locationColWidth = boxWidth - diagnosticGlyphColWidth - messageColWidth
+		if locationColWidth < diagnosticsMinLocationWidth {
+			locationColWidth = diagnosticsMinLocationWidth
+		}
+	}
+
+	lines := []string{
+		renderDiagnosticsSummary(state, theme, boxWidth),
+		renderMenuSeparator(theme, boxWidth),
+	}
+
+	start, end := scrollWindow(selectedIndex, len(state.Rows), contentHeight-2-diagnosticsHeaderLines-diagnosticsDetailLines)
+	for i := start; i < end; i++ {
+		lines = append(lines, renderDiagnosticRow(state.Rows[i], i == selectedIndex, theme, locationColWidth, messageColWidth))
+	}
+
+	var selected DiagnosticRow
+	if selectedIndex >= 0 && selectedIndex < len(state.Rows) {
+		selected = state.Rows[selectedIndex]
+	}
+	note := ""
+	if len(selected.Notes) > 0 {
+		note = selected.Notes[0]
+		if len(selected.Notes) > 1 {
+			note += fmt.Sprintf(" (+%d more)", len(selected.Notes)-1)
+		}
+	}
+	dimmed := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	lines = append(lines,
+		renderMenuSeparator(theme, boxWidth),
+		lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LabelTextColor)).Render(truncateToWidth(selected.Location, boxWidth)),
+		lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ContentTextColor)).Render(truncateToWidth(selected.Message, boxWidth)),
+		dimmed.Render(truncateToWidth(note, boxWidth)),
+	)
+
+	return assembleMenuOutput(lines, contentHeight, contentWidth, boxWidth)
+}
+
+func renderDiagnosticsSummary(state DiagnosticsViewState, theme Theme, width int) string {
+	parts := []string{plural(state.Errors, "error"), plural(state.Warnings, "warning")}
+	if state.ErrorsOnly {
+		parts = append(parts, "errors only")
+	}
+
+	color := theme.OutputStatusColor
+	switch {
+	case state.Errors > 0:
+		color = theme.OutputStderrColor
+	case state.Warnings > 0:
+		color = theme.OutputWarningColor
+	}
+	summary := truncateToWidth(strings.Join(parts, " · "), width)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(summary)
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+func renderDiagnosticRow(row DiagnosticRow, isSelected bool, theme Theme, locationColWidth, messageColWidth int) string {
+	glyph, color := "⚠", theme.OutputWarningColor
+	if row.IsError {
+		glyph, color = "✘", theme.OutputStderrColor
+	}
+
+	glyphCol := renderEmojiCol(glyph, diagnosticGlyphColWidth)
+	locationCol := PadLineToWidth(truncateToWidth(row.Location, locationColWidth-1), locationColWidth)
+	messageCol := PadLineToWidth(truncateToWidth(row.Message, messageColWidth), messageColWidth)
+
+	glyphStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	messageStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ContentTextColor))
+	locationStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LabelTextColor))
+	if isSelected {
+		locationStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.MainBackgroundColor)).
+			Background(lipgloss.Color(theme.MenuSelectionBackground)).
+			Bold(true)
+	}
+
+	return glyphStyle.Render(glyphCol) + locationStyle.Render(locationCol) + messageStyle.Render(messageCol)
+}
@@ -16,6 +16,21 @@ func PadLineToWidth(line string, width int) string {
 	return line
 }
 
+// truncateToWidth shortens plain text to width display columns, ending in "…" when cut
+func truncateToWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
 // RightAlignLine right-aligns content within width
 func RightAlignLine(content string, width int) string {
 	contentWidth := lipgloss.Width(content)
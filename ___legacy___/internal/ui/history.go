@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	historyMaxWidth          = 110
+	historyGlyphColWidth     = 3
+	historyStartColWidth     = 16
+	historyOperationColWidth = 12
+	historyConfigColWidth    = 16
+	historyDurationColWidth  = 10
+	historyResultColWidth    = 14
+	historyMinGeneratorWidth = 10
+	historyHeaderLines       = 2 // summary + separator
+	historyDetailLines       = 2 // separator + log path
+)
+
+// HistoryRow is one past operation in the history view
+type HistoryRow struct {
+	Start         string // Formatted, e.g. "Oct 17 14:23:05"
+	Operation     string // "build", "generate", "test", "run", "clean", "clean all" or "regenerate"
+	Generator     string // Generator, or "preset <name>"
+	Configuration string
+	Duration      string // Formatted, e.g. "12.4s"
+	Result        string // "success", "failed" or "aborted"
+	ExitCode      int
+	Log           string // Log path as shown; empty when none was written
+	Failed        bool
+	Aborted       bool
+}
+
+// RenderHistory renders the operation history: a summary line, then
+// GLYPH | START | OPERATION | GENERATOR | CONFIG | DURATION | RESULT rows, newest first,
+// scrolled to keep selectedIndex visible, and the selected operation's log path
+func RenderHistory(rows []HistoryRow, selectedIndex int, theme Theme, contentHeight int, contentWidth int) string {
+	boxWidth := contentWidth
+	if boxWidth > historyMaxWidth {
+		boxWidth = historyMaxWidth
+	}
+	generatorColWidth := boxWidth - historyGlyphColWidth - historyStartColWidth - historyOperationColWidth -
+		historyConfigColWidth - historyDurationColWidth - historyResultColWidth
+	if generatorColWidth < historyMinGeneratorWidth {
+		generatorColWidth = historyMinGeneratorWidth
+	}
+
+	lines := []string{
+		renderHistorySummary(rows, theme, boxWidth),
+		renderMenuSeparator(theme, boxWidth),
+	}
+
+	start, end := scrollWindow(selectedIndex, len(rows), contentHeight-2-historyHeaderLines-historyDetailLines)
+	for i := start; i < end; i++ {
+		lines = append(lines, renderHistoryRow(rows[i], i == selectedIndex, theme, generatorColWidth))
+	}
+
+	detail := ""
+	if selectedIndex >= 0 && selectedIndex < len(rows) {
+		detail = "No log was written"
+		if rows[selectedIndex].Log != "" {
+			detail = "Log: " + rows[selectedIndex].Log
+		}
+	}
+	lines = append(lines,
+		renderMenuSeparator(theme, boxWidth),
+		lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor)).Render(truncateToWidth(detail, boxWidth)),
+	)
+
+	return assembleMenuOutput(lines, contentHeight, contentWidth, boxWidth)
+}
+
+func renderHistorySummary(rows []HistoryRow, theme Theme, width int) string {
+	failed := 0
+	for _, row := range rows {
+		if row.Failed {
+			failed++
+		}
+	}
+
+	summary := "No operations recorded for this project"
+	color := theme.DimmedTextColor
+	if len(rows) > 0 {
+		summary = plural(len(rows), "operation") + fmt.Sprintf(" · %d failed", failed)
+		color = theme.OutputStatusColor
+		if failed > 0 {
+			color = theme.OutputStderrColor
+		}
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(truncateToWidth(summary, width))
+}
+
+func renderHistoryRow(row HistoryRow, isSelected bool, theme Theme, generatorColWidth int) string {
+	glyph, color := historyRowGlyph(row, theme)
+
+	result := row.Result
+	if row.Failed && row.ExitCode != 0 {
+		result += fmt.Sprintf(" (%d)", row.ExitCode)
+	}
+
+	glyphCol := renderEmojiCol(glyph, historyGlyphColWidth)
+	startCol := PadLineToWidth(truncateToWidth(row.Start, historyStartColWidth-1), historyStartColWidth)
+	operationCol := PadLineToWidth(truncateToWidth(row.Operation, historyOperationColWidth-1), historyOperationColWidth)
+	generatorCol := PadLineToWidth(truncateToWidth(row.Generator, generatorColWidth-1), generatorColWidth)
+	configCol := PadLineToWidth(truncateToWidth(row.Configuration, historyConfigColWidth-1), historyConfigColWidth)
+	durationCol := PadLineToWidth(truncateToWidth(row.Duration, historyDurationColWidth-1), historyDurationColWidth)
+	resultCol := PadLineToWidth(truncateToWidth(result, historyResultColWidth), historyResultColWidth)
+
+	glyphStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	resultStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	contentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ContentTextColor))
+	operationStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LabelTextColor))
+	if isSelected {
+		operationStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.MainBackgroundColor)).
+			Background(lipgloss.Color(theme.MenuSelectionBackground)).
+			Bold(true)
+	}
+
+	return strings.Join([]string{
+		glyphStyle.Render(glyphCol),
+		dimmedStyle.Render(startCol),
+		operationStyle.Render(operationCol),
+		contentStyle.Render(generatorCol),
+		contentStyle.Render(configCol),
+		dimmedStyle.Render(durationCol),
+		resultStyle.Render(resultCol),
+	}, "")
+}
+
+// historyRowGlyph returns the result glyph and its color
+func historyRowGlyph(row HistoryRow, theme Theme) (string, string) {
+	switch {
+	case row.Aborted:
+		return "○", theme.OutputWarningColor
+	case row.Failed:
+		return "✘", theme.OutputStderrColor
+	default:
+		return "✔", theme.OutputStatusColor
+	}
+}
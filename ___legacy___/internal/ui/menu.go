@@ -1,31 +1,57 @@
 package ui
 
 // MenuRow represents a single menu row
-// Fixed 8 rows: [0]Project [1]Regenerate [2]OpenIDE [3]Separator [4]Configuration [5]Build [6]Clean [7]CleanAll
+// Fixed 14 rows: [0]Project [1]Preset [2]Regenerate [3]OpenIDE [4]Separator [5]Configuration [6]Variant [7]Target [8]Build [9]Test [10]Run [11]Debug [12]Clean [13]CleanAll
 type MenuRow struct {
-	ID            string // "project", "regenerate", "openIde", "separator", "configuration", "build", "clean", "cleanAll"
-	Shortcut      string // Actual key for handler: "", "g", "o", "", "", "b", "c", "x"
-	ShortcutLabel string // Display label (right-aligned): "", "g", "o", "", "", "b", "c", "x"
-	Emoji         string // "⚙️", "🚀", "📂", "", "🏗️", "🔨", "🧹", "💥"
-	Label         string // "Project", "Regenerate", "Open IDE", "", "Configuration", "Build", "Clean", "Clean All"
-	Value         string // "Xcode", "", "", "", "Debug", "", "", ""
+	ID            string // "project", "preset", "regenerate", "openIde", "separator", "configuration", "variant", "target", "build", "test", "run", "debug", "clean", "cleanAll"
+	Shortcut      string // Actual key for handler: "", "", "g", "o", "", "", "", "", "b", "t", "r", "d", "c", "x"
+	ShortcutLabel string // Display label (right-aligned): "", "", "g", "o", "", "", "", "", "b", "t", "r", "d", "c", "x"
+	Emoji         string // "⚙️", "📋", "🚀", "📂", "", "🏗️", "🧬", "🎯", "🔨", "🧪", "▶️", "🐞", "🧹", "💥"
+	Label         string // "Project", "Preset", "Regenerate", "Open IDE", "", "Configuration", "Variant", "Target", "Build", "Test", "Run", "Debug", "Clean", "Clean All"
+	Value         string // "Xcode", "None", "", "", "", "Debug", "default", "All", "", "All", "Auto", "gdb", "", ""
 	Visible       bool   // true/false based on conditions
 	IsAction      bool   // false for toggles, true for actions
 	IsSelectable  bool   // false for separator
 	Hint          string // Footer hint/description for this row
 }
 
-// GenerateMenuRows returns exactly 8 rows (used by app.go)
+// MenuState is the application state the main menu is generated from
+type MenuState struct {
+	ProjectLabel     string // Selected generator, display form
+	PresetLabel      string // Selected configure preset, "None" or "Invalid"
+	PresetHint       string // Footer hint for the Preset row (preset title or load error)
+	HasPresets       bool   // Project defines usable configure presets
+	PresetActive     bool   // A configure preset is selected — the preset decides the generator
+	Configuration    string
+	Variant          string // Build variant: "default", "asan", ...
+	TargetLabel      string // Selected build target or "All"
+	HasTargets       bool   // Selected build tree lists its targets (File API reply or ninja)
+	TestLabel        string // ctest -R filter or "All"
+	CanTest          bool   // Selected build tree is configured
+	RunLabel         string // Executable Run launches, or "Auto" until the File API lists them
+	DebuggerLabel    string // Debugger the Debug row launches, empty when none is installed
+	CanOpenIDE       bool
+	CanClean         bool
+	HasBuild         bool
+	HasBuildsToClean bool
+	IsIDEGenerator   bool
+}
+
+// GenerateMenuRows returns exactly 14 rows (used by app.go)
 // All rows always visible - unavailable options are dimmed and not selectable
-func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool, canClean bool, hasBuild bool, hasBuildsToClean bool, isIDEGenerator bool) []MenuRow {
+func GenerateMenuRows(state MenuState) []MenuRow {
 	regenerateLabel := "Generate"
-	if hasBuild {
+	if state.HasBuild {
 		regenerateLabel = "Regenerate"
 	}
 	regenerateHint := "Run initial CMake configuration"
-	if hasBuild {
+	if state.HasBuild {
 		regenerateHint = "Re-run CMake configuration"
 	}
+	projectHint := "Select project type (Xcode, Ninja, etc.)"
+	if state.PresetActive {
+		projectHint = "Generator comes from the selected preset"
+	}
 
 	return []MenuRow{
 		{
@@ -34,11 +60,23 @@ func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool
 			ShortcutLabel: "",
 			Emoji:         "⚙️",
 			Label:         "Project",
-			Value:         projectLabel,
+			Value:         state.ProjectLabel,
 			Visible:       true,
 			IsAction:      false,
-			IsSelectable:  true,
-			Hint:          "Select project type (Xcode, Ninja, etc.)",
+			IsSelectable:  !state.PresetActive, // Preset decides the generator
+			Hint:          projectHint,
+		},
+		{
+			ID:            "preset",
+			Shortcut:      "",
+			ShortcutLabel: "",
+			Emoji:         "📋",
+			Label:         "Preset",
+			Value:         state.PresetLabel,
+			Visible:       true,
+			IsAction:      false,
+			IsSelectable:  state.HasPresets, // Not selectable without CMakePresets.json
+			Hint:          state.PresetHint,
 		},
 		{
 			ID:            "regenerate",
@@ -57,12 +95,12 @@ func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool
 			Shortcut:      "o",
 			ShortcutLabel: "o",
 			Emoji:         "📂",
-			Label:         openIdeLabel(isIDEGenerator),
+			Label:         openIdeLabel(state.IsIDEGenerator),
 			Value:         "",
 			Visible:       true,
 			IsAction:      true,
-			IsSelectable:  canOpenIDE,
-			Hint:          openIdeHint(isIDEGenerator),
+			IsSelectable:  state.CanOpenIDE,
+			Hint:          openIdeHint(state.IsIDEGenerator),
 		},
 		{
 			ID:            "separator",
@@ -82,12 +120,36 @@ func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool
 			ShortcutLabel: "",
 			Emoji:         "🏗️",
 			Label:         "Configuration",
-			Value:         configuration,
+			Value:         state.Configuration,
 			Visible:       true,
 			IsAction:      false,
 			IsSelectable:  true,
 			Hint:          "Select build configuration (Debug, Release, etc.)",
 		},
+		{
+			ID:            "variant",
+			Shortcut:      "",
+			ShortcutLabel: "",
+			Emoji:         "🧬",
+			Label:         "Variant",
+			Value:         state.Variant,
+			Visible:       true,
+			IsAction:      false,
+			IsSelectable:  !state.PresetActive, // Preset decides the build flags
+			Hint:          variantHint(state.PresetActive),
+		},
+		{
+			ID:            "target",
+			Shortcut:      "",
+			ShortcutLabel: "",
+			Emoji:         "🎯",
+			Label:         "Target",
+			Value:         state.TargetLabel,
+			Visible:       true,
+			IsAction:      false,
+			IsSelectable:  state.HasTargets, // Targets are known once the tree is configured
+			Hint:          targetHint(state.HasTargets),
+		},
 		{
 			ID:            "build",
 			Shortcut:      "b",
@@ -100,6 +162,42 @@ func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool
 			IsSelectable:  true,
 			Hint:          "Build the project",
 		},
+		{
+			ID:            "test",
+			Shortcut:      "t",
+			ShortcutLabel: "t",
+			Emoji:         "🧪",
+			Label:         "Test",
+			Value:         state.TestLabel,
+			Visible:       true,
+			IsAction:      true,
+			IsSelectable:  state.CanTest, // ctest needs a configured build tree
+			Hint:          testHint(state.CanTest),
+		},
+		{
+			ID:            "run",
+			Shortcut:      "r",
+			ShortcutLabel: "r",
+			Emoji:         "▶️",
+			Label:         "Run",
+			Value:         state.RunLabel,
+			Visible:       true,
+			IsAction:      true,
+			IsSelectable:  true, // Builds first, generating if needed
+			Hint:          "Build, then run the executable (R to set arguments)",
+		},
+		{
+			ID:            "debug",
+			Shortcut:      "d",
+			ShortcutLabel: "d",
+			Emoji:         "🐞",
+			Label:         "Debug",
+			Value:         state.DebuggerLabel,
+			Visible:       true,
+			IsAction:      true,
+			IsSelectable:  state.DebuggerLabel != "", // Needs gdb or lldb
+			Hint:          debugHint(state.DebuggerLabel),
+		},
 		{
 			ID:            "clean",
 			Shortcut:      "c",
@@ -109,7 +207,7 @@ func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool
 			Value:         "",
 			Visible:       true,
 			IsAction:      true,
-			IsSelectable:  canClean, // Not selectable when unavailable
+			IsSelectable:  state.CanClean, // Not selectable when unavailable
 			Hint:          "Remove build artifacts for current project",
 		},
 		{
@@ -121,12 +219,40 @@ func GenerateMenuRows(projectLabel string, configuration string, canOpenIDE bool
 			Value:         "",
 			Visible:       true,
 			IsAction:      true,
-			IsSelectable:  hasBuildsToClean, // Not selectable when no builds to clean
+			IsSelectable:  state.HasBuildsToClean, // Not selectable when no builds to clean
 			Hint:          "Remove entire Builds/ directory (all projects)",
 		},
 	}
 }
 
+func variantHint(presetActive bool) string {
+	if presetActive {
+		return "Build flags come from the selected preset"
+	}
+	return "Select build variant (sanitizers, coverage) — each gets its own build directory"
+}
+
+func targetHint(hasTargets bool) string {
+	if hasTargets {
+		return "Select build target (All = everything)"
+	}
+	return "Generate first to list build targets"
+}
+
+func testHint(canTest bool) string {
+	if canTest {
+		return "Run tests with ctest"
+	}
+	return "Generate first to run tests"
+}
+
+func debugHint(debugger string) string {
+	if debugger == "" {
+		return "Install gdb or lldb (or set [debug] debugger) to debug"
+	}
+	return "Build, then debug the executable in " + debugger + " with the Run arguments"
+}
+
 func openIdeLabel(isIDEGenerator bool) string {
 	if isIDEGenerator {
 		return "Open IDE"
@@ -138,5 +264,5 @@ func openIdeHint(isIDEGenerator bool) string {
 	if isIDEGenerator {
 		return "Open project in IDE"
 	}
-	return "Open build directory in editor"
+	return "Open project root in editor"
 }
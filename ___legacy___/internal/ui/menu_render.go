@@ -145,6 +145,22 @@ func styleNormalMenuRow(shortcutCol, emojiCol, labelCol, valueCol string, theme
 	return shortcutStyle.Render(shortcutCol) + emojiStyle.Render(emojiCol) + labelStyle.Render(labelCol) + valueStyle.Render(valueCol)
 }
 
+// scrollWindow returns the [start, end) slice of a total-row list that fits maxRows
+// and keeps selectedIndex visible
+func scrollWindow(selectedIndex, total, maxRows int) (start, end int) {
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	if selectedIndex >= maxRows {
+		start = selectedIndex - maxRows + 1
+	}
+	end = start + maxRows
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
 func assembleMenuOutput(lines []string, contentHeight, contentWidth, menuBoxWidth int) string {
 	innerHeight := contentHeight - 2
 	menuHeight := len(lines)
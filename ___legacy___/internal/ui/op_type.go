@@ -11,4 +11,6 @@ const (
 	OpClean
 	OpCleanAll
 	OpRegenerate
+	OpTest
+	OpRun
 )
@@ -13,7 +13,7 @@ type PreferenceRow struct {
 	Emoji   string
 	Label   string
 	Value   string
-	Enabled bool
+	Enabled bool // false = informational row, shown dimmed and never selected
 }
 
 // BuildPreferenceRows builds preference rows from config
@@ -23,26 +23,87 @@ func BuildPreferenceRows(cfg *config.Config) []PreferenceRow {
 		return []PreferenceRow{}
 	}
 
-	autoUpdateValue := "OFF"
-	if cfg.IsAutoScanEnabled() {
-		autoUpdateValue = "ON"
-	}
-
 	return []PreferenceRow{
-		{Emoji: "🔄", Label: "Auto-scan", Value: autoUpdateValue, Enabled: true},
+		{Emoji: "🔄", Label: "Auto-scan", Value: onOffValue(cfg.IsAutoScanEnabled()), Enabled: true},
 		{Emoji: "⏱️", Label: "Scan Interval", Value: fmt.Sprintf("%d min", cfg.AutoScanInterval()), Enabled: true},
 		{Emoji: "🎨", Label: "Theme", Value: cfg.Theme(), Enabled: true},
+		{Emoji: "🌈", Label: "Console Colors", Value: cfg.ConsoleColors(), Enabled: true},
+		{Emoji: "📒", Label: "Compile Commands", Value: onOffValue(cfg.ExportCompileCommands()), Enabled: true},
+		{Emoji: "🖥️", Label: "Build on PTY", Value: onOffValue(cfg.PTY()), Enabled: true},
+	}
+}
+
+func onOffValue(enabled bool) string {
+	if enabled {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// BuildEffectiveSettingRows builds the read-only rows showing the merged project settings
+// and where each value came from (default, user, .cake.toml)
+func BuildEffectiveSettingRows(settings config.Effective) []PreferenceRow {
+	generator := settings.Generator
+	if generator == "" {
+		generator = "auto"
+	}
+	definitions := "none"
+	if len(settings.Definitions) > 0 {
+		definitions = fmt.Sprintf("%d", len(settings.Definitions))
+	}
+	env := "none"
+	if len(settings.Env) > 0 {
+		env = fmt.Sprintf("%d vars", len(settings.Env))
+	}
+	jobs := "auto"
+	if settings.Jobs > 0 {
+		jobs = fmt.Sprintf("%d", settings.Jobs)
+	}
+	projectFile := "none"
+	if settings.ProjectError != "" {
+		projectFile = "invalid, ignored"
+	} else {
+		for _, source := range settings.Sources {
+			if source == config.SourceProject {
+				projectFile = "loaded"
+				break
+			}
+		}
+	}
+
+	run := "auto"
+	if settings.Run.Target != "" {
+		run = settings.Run.Target
+	}
+	if len(settings.Run.Args) > 0 {
+		run += fmt.Sprintf(", %d args", len(settings.Run.Args))
+	}
+
+	withSource := func(value, setting string) string {
+		return value + " (" + settings.Source(setting) + ")"
+	}
+	return []PreferenceRow{
+		{Emoji: "📄", Label: config.SourceProject, Value: projectFile},
+		{Emoji: "🏗️", Label: "Generator", Value: withSource(generator, config.SettingGenerator)},
+		{Emoji: "⚙️", Label: "Configuration", Value: withSource(settings.Configuration, config.SettingConfiguration)},
+		{Emoji: "📌", Label: "Definitions", Value: withSource(definitions, config.SettingDefinitions)},
+		{Emoji: "🌿", Label: "Environment", Value: withSource(env, config.SettingEnv)},
+		{Emoji: "🧵", Label: "Jobs", Value: withSource(jobs, config.SettingJobs)},
+		{Emoji: "▶️", Label: "Run", Value: withSource(run, config.SettingRun)},
+		{Emoji: "📒", Label: "Compile Commands", Value: withSource(onOffValue(settings.CompileCommands), config.SettingCompileCommands)},
+		{Emoji: "🖥️", Label: "Build on PTY", Value: withSource(onOffValue(settings.PTY), config.SettingPTY)},
 	}
 }
 
 // RenderPreferencesMenu renders preference rows as EMOJI | LABEL | VALUE
-// No shortcut column - preferences use navigation only
+// No shortcut column - preferences use navigation only. Informational (disabled) rows
+// follow the navigable ones below a separator.
 func RenderPreferencesMenu(rows []PreferenceRow, selectedIndex int, theme Theme, contentHeight int, contentWidth int) string {
 	if len(rows) == 0 {
 		return ""
 	}
 
-	emojiColWidth, labelColWidth, valueColWidth := calcPrefColWidths(contentWidth)
+	emojiColWidth, labelColWidth, valueColWidth := calcPrefColWidths(contentWidth, rows)
 	menuBoxWidth := emojiColWidth + labelColWidth + valueColWidth
 
 	lines := buildPrefLines(rows, selectedIndex, theme, emojiColWidth, labelColWidth, valueColWidth)
@@ -50,11 +111,22 @@ func RenderPreferencesMenu(rows []PreferenceRow, selectedIndex int, theme Theme,
 	return assembleMenuOutput(lines, contentHeight, contentWidth, menuBoxWidth)
 }
 
-func calcPrefColWidths(contentWidth int) (emojiColWidth, labelColWidth, valueColWidth int) {
+// calcPrefColWidths widens the value column to the longest value (informational rows carry
+// their source) as far as contentWidth allows
+func calcPrefColWidths(contentWidth int, rows []PreferenceRow) (emojiColWidth, labelColWidth, valueColWidth int) {
 	emojiColWidth = 3
 	labelColWidth = 18
 	valueColWidth = 10
 
+	for _, row := range rows {
+		if w := lipgloss.Width(row.Value); w > valueColWidth {
+			valueColWidth = w
+		}
+	}
+	if maxValueWidth := contentWidth - emojiColWidth - labelColWidth; valueColWidth > maxValueWidth && maxValueWidth >= 10 {
+		valueColWidth = maxValueWidth
+	}
+
 	if contentWidth < (emojiColWidth + labelColWidth + valueColWidth) {
 		labelColWidth = contentWidth - emojiColWidth - valueColWidth
 		if labelColWidth < 0 {
@@ -66,13 +138,20 @@ func calcPrefColWidths(contentWidth int) (emojiColWidth, labelColWidth, valueCol
 
 func buildPrefLines(rows []PreferenceRow, selectedIndex int, theme Theme, emojiColWidth, labelColWidth, valueColWidth int) []string {
 	var lines []string
+	separated := false
 	for i, row := range rows {
 		emojiCol := renderEmojiCol(row.Emoji, emojiColWidth)
 		labelCol := renderLabelCol(row.Label, labelColWidth)
-		valueCol := renderValueCol(row.Value, valueColWidth)
+		valueCol := renderValueCol(truncateToWidth(row.Value, valueColWidth), valueColWidth)
 
 		var styledLine string
-		if i == selectedIndex {
+		if !row.Enabled {
+			if !separated && i > 0 {
+				lines = append(lines, renderMenuSeparator(theme, emojiColWidth+labelColWidth+valueColWidth))
+			}
+			separated = true
+			styledLine = styleInfoPrefRow(emojiCol, labelCol, valueCol, theme)
+		} else if i == selectedIndex {
 			styledLine = styleSelectedPrefRow(emojiCol, labelCol, valueCol, theme)
 		} else {
 			styledLine = styleNormalPrefRow(emojiCol, labelCol, valueCol, theme)
@@ -99,15 +178,22 @@ func styleNormalPrefRow(emojiCol, labelCol, valueCol string, theme Theme) string
 	return emojiStyle.Render(emojiCol) + labelStyle.Render(labelCol) + valueStyle.Render(valueCol)
 }
 
+func styleInfoPrefRow(emojiCol, labelCol, valueCol string, theme Theme) string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	return style.Render(emojiCol) + style.Render(labelCol) + style.Render(valueCol)
+}
+
 // RenderPreferencesWithBanner renders preferences (left) + banner (right)
-// 50/50 split, same layout as main menu
-func RenderPreferencesWithBanner(cfg *config.Config, selectedIndex int, theme Theme, sizing DynamicSizing) string {
+// 50/50 split, same layout as main menu. The effective project settings are listed
+// read-only below the preferences.
+func RenderPreferencesWithBanner(cfg *config.Config, settings config.Effective, selectedIndex int, theme Theme, sizing DynamicSizing) string {
 	// 50/50 split
 	leftWidth := sizing.ContentInnerWidth / 2
 	rightWidth := sizing.ContentInnerWidth - leftWidth
 
-	// Build preference rows from config
+	// Build preference rows from config, then the effective settings
 	rows := BuildPreferenceRows(cfg)
+	rows = append(rows, BuildEffectiveSettingRows(settings)...)
 
 	// Left column: preferences menu
 	menuContent := RenderPreferencesMenu(rows, selectedIndex, theme, sizing.ContentHeight, leftWidth)
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	testResultsMaxWidth        = 72
+	testGlyphColWidth          = 3
+	testStatusColWidth         = 22
+	testDurationColWidth       = 10
+	testResultsHeaderLines     = 2 // summary + separator
+	testResultsMinNameColWidth = 8
+)
+
+// TestResultRow is one test in the test results view
+type TestResultRow struct {
+	Name     string
+	Status   string // "Passed", "Failed", "Timeout", "Not Run", "Skipped"
+	Detail   string // "Exception: SegFault", "Disabled", ...
+	Duration string // Formatted, e.g. "0.42 s"
+	Failed   bool   // Counts towards --rerun-failed
+}
+
+// TestResultsState is what the test results view is rendered from
+type TestResultsState struct {
+	Rows   []TestResultRow
+	Filter string // -R regex the run used; empty = all tests
+	Rerun  bool   // Run was --rerun-failed
+}
+
+// TestSummary counts passed, failed and other (not run, skipped) tests
+func TestSummary(rows []TestResultRow) (passed, failed, other int) {
+	for _, row := range rows {
+		switch {
+		case row.Failed:
+			failed++
+		case row.Status == "Passed":
+			passed++
+		default:
+			other++
+		}
+	}
+	return passed, failed, other
+}
+
+// RenderTestResults renders the per-test outcome list: a summary line, then
+// GLYPH | NAME | STATUS | DURATION rows, scrolled to keep selectedIndex visible
+func RenderTestResults(state TestResultsState, selectedIndex int, theme Theme, contentHeight int, contentWidth int) string {
+	boxWidth := contentWidth
+	if boxWidth > testResultsMaxWidth {
+		boxWidth = testResultsMaxWidth
+	}
+	nameColWidth := boxWidth - testGlyphColWidth - testStatusColWidth - testDurationColWidth
+	if nameColWidth < testResultsMinNameColWidth {
+		nameColWidth = testResultsMinNameColWidth
+	}
+
+	lines := []string{
+		renderTestSummary(state, theme, boxWidth),
+		renderMenuSeparator(theme, boxWidth),
+	}
+
+	start, end := scrollWindow(selectedIndex, len(state.Rows), contentHeight-2-testResultsHeaderLines)
+	for i := start; i < end; i++ {
+		lines = append(lines, renderTestRow(state.Rows[i], i == selectedIndex, theme, nameColWidth))
+	}
+
+	return assembleMenuOutput(lines, contentHeight, contentWidth, boxWidth)
+}
+
+func renderTestSummary(state TestResultsState, theme Theme, width int) string {
+	passed, failed, other := TestSummary(state.Rows)
+
+	parts := []string{fmt.Sprintf("%d passed", passed), fmt.Sprintf("%d failed", failed)}
+	if other > 0 {
+		parts = append(parts, fmt.Sprintf("%d not run", other))
+	}
+	if state.Filter != "" {
+		parts = append(parts, "filter: "+state.Filter)
+	}
+	if state.Rerun {
+		parts = append(parts, "rerun failed")
+	}
+
+	color := theme.OutputStatusColor
+	if failed > 0 {
+		color = theme.OutputStderrColor
+	}
+	summary := truncateToWidth(strings.Join(parts, " · "), width)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(summary)
+}
+
+func renderTestRow(row TestResultRow, isSelected bool, theme Theme, nameColWidth int) string {
+	glyph, color := testRowGlyph(row, theme)
+
+	status := row.Status
+	if row.Detail != "" {
+		status += " (" + row.Detail + ")"
+	}
+
+	glyphCol := renderEmojiCol(glyph, testGlyphColWidth)
+	nameCol := PadLineToWidth(truncateToWidth(row.Name, nameColWidth-1), nameColWidth)
+	statusCol := PadLineToWidth(truncateToWidth(status, testStatusColWidth-1), testStatusColWidth)
+	durationCol := renderValueCol(row.Duration, testDurationColWidth)
+
+	glyphStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	durationStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LabelTextColor))
+	if isSelected {
+		nameStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.MainBackgroundColor)).
+			Background(lipgloss.Color(theme.MenuSelectionBackground)).
+			Bold(true)
+	}
+
+	return glyphStyle.Render(glyphCol) + nameStyle.Render(nameCol) + statusStyle.Render(statusCol) + durationStyle.Render(durationCol)
+}
+
+// testRowGlyph returns the status glyph and its color
+func testRowGlyph(row TestResultRow, theme Theme) (string, string) {
+	switch {
+	case row.Status == "Timeout":
+		return "⏱", theme.OutputStderrColor
+	case row.Failed:
+		return "✘", theme.OutputStderrColor
+	case row.Status == "Passed":
+		return "✔", theme.OutputStatusColor
+	default:
+		return "○", theme.DimmedTextColor
+	}
+}
@@ -1,8 +1,12 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/jrengmusic/cake/internal/config"
 )
 
 // --- CalculateDynamicSizing ---
@@ -100,7 +104,22 @@ func TestNewDynamicSizing(t *testing.T) {
 
 // --- GenerateMenuRows ---
 
-func TestGenerateMenuRows_AlwaysReturns8Rows(t *testing.T) {
+func menuState(projectLabel, configuration string, canOpenIDE, canClean, hasBuild, hasBuildsToClean bool) MenuState {
+	return MenuState{
+		ProjectLabel:     projectLabel,
+		PresetLabel:      "None",
+		Configuration:    configuration,
+		Variant:          "default",
+		TargetLabel:      "All",
+		TestLabel:        "All",
+		CanOpenIDE:       canOpenIDE,
+		CanClean:         canClean,
+		HasBuild:         hasBuild,
+		HasBuildsToClean: hasBuildsToClean,
+	}
+}
+
+func TestGenerateMenuRows_AlwaysReturns14Rows(t *testing.T) {
 	combos := []struct {
 		canOpenIDE, canClean, hasBuild, hasBuildsToClean bool
 	}{
@@ -110,15 +129,15 @@ func TestGenerateMenuRows_AlwaysReturns8Rows(t *testing.T) {
 		{false, true, false, true},
 	}
 	for _, c := range combos {
-		rows := GenerateMenuRows("Xcode", "Debug", c.canOpenIDE, c.canClean, c.hasBuild, c.hasBuildsToClean, false)
-		if len(rows) != 8 {
-			t.Errorf("expected 8 rows, got %d (combo %+v)", len(rows), c)
+		rows := GenerateMenuRows(menuState("Xcode", "Debug", c.canOpenIDE, c.canClean, c.hasBuild, c.hasBuildsToClean))
+		if len(rows) != 14 {
+			t.Errorf("expected 14 rows, got %d (combo %+v)", len(rows), c)
 		}
 	}
 }
 
 func TestGenerateMenuRows_AllVisible(t *testing.T) {
-	rows := GenerateMenuRows("Ninja", "Release", true, true, true, true, false)
+	rows := GenerateMenuRows(menuState("Ninja", "Release", true, true, true, true))
 	for _, row := range rows {
 		if !row.Visible {
 			t.Errorf("row %q should be Visible", row.ID)
@@ -127,10 +146,10 @@ func TestGenerateMenuRows_AllVisible(t *testing.T) {
 }
 
 func TestGenerateMenuRows_SeparatorNotSelectable(t *testing.T) {
-	rows := GenerateMenuRows("Xcode", "Debug", true, true, true, true, false)
-	sep := rows[3]
+	rows := GenerateMenuRows(menuState("Xcode", "Debug", true, true, true, true))
+	sep := rows[4]
 	if sep.ID != "separator" {
-		t.Fatalf("row[3] expected separator, got %q", sep.ID)
+		t.Fatalf("row[4] expected separator, got %q", sep.ID)
 	}
 	if sep.IsSelectable {
 		t.Error("separator must not be selectable")
@@ -154,36 +173,36 @@ func TestGenerateMenuRows_SelectabilityByFlags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rows := GenerateMenuRows("Xcode", "Debug", tt.canOpenIDE, tt.canClean, false, tt.hasBuildsToClean, false)
+			rows := GenerateMenuRows(menuState("Xcode", "Debug", tt.canOpenIDE, tt.canClean, false, tt.hasBuildsToClean))
 
-			if rows[2].IsSelectable != tt.wantOpenIDESelectable {
-				t.Errorf("openIde IsSelectable: got %v want %v", rows[2].IsSelectable, tt.wantOpenIDESelectable)
+			if rows[3].IsSelectable != tt.wantOpenIDESelectable {
+				t.Errorf("openIde IsSelectable: got %v want %v", rows[3].IsSelectable, tt.wantOpenIDESelectable)
 			}
-			if rows[6].IsSelectable != tt.wantCleanSelectable {
-				t.Errorf("clean IsSelectable: got %v want %v", rows[6].IsSelectable, tt.wantCleanSelectable)
+			if rows[12].IsSelectable != tt.wantCleanSelectable {
+				t.Errorf("clean IsSelectable: got %v want %v", rows[12].IsSelectable, tt.wantCleanSelectable)
 			}
-			if rows[7].IsSelectable != tt.wantCleanAllSelectable {
-				t.Errorf("cleanAll IsSelectable: got %v want %v", rows[7].IsSelectable, tt.wantCleanAllSelectable)
+			if rows[13].IsSelectable != tt.wantCleanAllSelectable {
+				t.Errorf("cleanAll IsSelectable: got %v want %v", rows[13].IsSelectable, tt.wantCleanAllSelectable)
 			}
 		})
 	}
 }
 
 func TestGenerateMenuRows_RegenerateLabelByHasBuild(t *testing.T) {
-	rowsNoBuild := GenerateMenuRows("Xcode", "Debug", false, false, false, false, false)
-	if rowsNoBuild[1].Label != "Generate" {
-		t.Errorf("hasBuild=false: expected Label 'Generate', got %q", rowsNoBuild[1].Label)
+	rowsNoBuild := GenerateMenuRows(menuState("Xcode", "Debug", false, false, false, false))
+	if rowsNoBuild[2].Label != "Generate" {
+		t.Errorf("hasBuild=false: expected Label 'Generate', got %q", rowsNoBuild[2].Label)
 	}
 
-	rowsHasBuild := GenerateMenuRows("Xcode", "Debug", false, false, true, false, false)
-	if rowsHasBuild[1].Label != "Regenerate" {
-		t.Errorf("hasBuild=true: expected Label 'Regenerate', got %q", rowsHasBuild[1].Label)
+	rowsHasBuild := GenerateMenuRows(menuState("Xcode", "Debug", false, false, true, false))
+	if rowsHasBuild[2].Label != "Regenerate" {
+		t.Errorf("hasBuild=true: expected Label 'Regenerate', got %q", rowsHasBuild[2].Label)
 	}
 }
 
 func TestGenerateMenuRows_RowIDs(t *testing.T) {
-	expectedIDs := []string{"project", "regenerate", "openIde", "separator", "configuration", "build", "clean", "cleanAll"}
-	rows := GenerateMenuRows("Xcode", "Debug", true, true, true, true, false)
+	expectedIDs := []string{"project", "preset", "regenerate", "openIde", "separator", "configuration", "variant", "target", "build", "test", "run", "debug", "clean", "cleanAll"}
+	rows := GenerateMenuRows(menuState("Xcode", "Debug", true, true, true, true))
 
 	for i, id := range expectedIDs {
 		if rows[i].ID != id {
@@ -193,10 +212,10 @@ func TestGenerateMenuRows_RowIDs(t *testing.T) {
 }
 
 func TestGenerateMenuRows_FixedSelectableRows(t *testing.T) {
-	// project, regenerate, configuration, build are always selectable
-	rows := GenerateMenuRows("Xcode", "Debug", false, false, false, false, false)
+	// project, regenerate, configuration, variant, build, run are always selectable without a preset
+	rows := GenerateMenuRows(menuState("Xcode", "Debug", false, false, false, false))
 
-	alwaysSelectable := map[int]string{0: "project", 1: "regenerate", 4: "configuration", 5: "build"}
+	alwaysSelectable := map[int]string{0: "project", 2: "regenerate", 5: "configuration", 6: "variant", 8: "build", 10: "run"}
 	for idx, id := range alwaysSelectable {
 		if !rows[idx].IsSelectable {
 			t.Errorf("row[%d] (%s) should always be selectable", idx, id)
@@ -204,6 +223,127 @@ func TestGenerateMenuRows_FixedSelectableRows(t *testing.T) {
 	}
 }
 
+func TestGenerateMenuRows_PresetRow(t *testing.T) {
+	state := menuState("Ninja", "Debug", false, false, false, false)
+
+	rows := GenerateMenuRows(state)
+	if rows[1].IsSelectable {
+		t.Error("preset row must not be selectable without presets")
+	}
+
+	state.HasPresets = true
+	state.PresetLabel = "dev"
+	rows = GenerateMenuRows(state)
+	if !rows[1].IsSelectable || rows[1].Value != "dev" {
+		t.Errorf("preset row: got selectable=%v value=%q", rows[1].IsSelectable, rows[1].Value)
+	}
+	if !rows[0].IsSelectable {
+		t.Error("project row stays selectable while no preset is active")
+	}
+
+	state.PresetActive = true
+	rows = GenerateMenuRows(state)
+	if rows[0].IsSelectable {
+		t.Error("project row must not be selectable while a preset decides the generator")
+	}
+}
+
+func TestGenerateMenuRows_VariantRow(t *testing.T) {
+	state := menuState("Ninja", "Debug", false, false, false, false)
+	state.Variant = "asan"
+
+	rows := GenerateMenuRows(state)
+	if rows[6].ID != "variant" || !rows[6].IsSelectable || rows[6].Value != "asan" {
+		t.Errorf("variant row: got %+v", rows[6])
+	}
+
+	state.PresetActive = true
+	rows = GenerateMenuRows(state)
+	if rows[6].IsSelectable {
+		t.Error("variant row must not be selectable while a preset decides the build flags")
+	}
+}
+
+func TestGenerateMenuRows_DebugRow(t *testing.T) {
+	state := menuState("Ninja", "Debug", false, false, false, false)
+
+	rows := GenerateMenuRows(state)
+	if rows[11].ID != "debug" || rows[11].IsSelectable {
+		t.Errorf("debug row must not be selectable without a debugger: %+v", rows[11])
+	}
+
+	state.DebuggerLabel = "lldb"
+	rows = GenerateMenuRows(state)
+	if !rows[11].IsSelectable || rows[11].Value != "lldb" || rows[11].Shortcut != "d" {
+		t.Errorf("debug row: got %+v", rows[11])
+	}
+}
+
+func TestGenerateMenuRows_TargetRow(t *testing.T) {
+	state := menuState("Ninja", "Debug", false, false, false, false)
+
+	rows := GenerateMenuRows(state)
+	if rows[7].ID != "target" || rows[7].IsSelectable {
+		t.Errorf("target row must not be selectable before targets are known: %+v", rows[7])
+	}
+
+	state.HasTargets = true
+	state.TargetLabel = "MyPlugin_Standalone"
+	rows = GenerateMenuRows(state)
+	if !rows[7].IsSelectable || rows[7].Value != "MyPlugin_Standalone" {
+		t.Errorf("target row: got selectable=%v value=%q", rows[7].IsSelectable, rows[7].Value)
+	}
+}
+
+func TestGenerateMenuRows_TestRow(t *testing.T) {
+	state := menuState("Ninja", "Debug", false, false, false, false)
+
+	rows := GenerateMenuRows(state)
+	if rows[9].ID != "test" || rows[9].IsSelectable {
+		t.Errorf("test row must not be selectable before the tree is configured: %+v", rows[9])
+	}
+
+	state.CanTest = true
+	state.TestLabel = "^unit_"
+	rows = GenerateMenuRows(state)
+	if !rows[9].IsSelectable || rows[9].Value != "^unit_" || rows[9].Shortcut != "t" {
+		t.Errorf("test row: got selectable=%v value=%q shortcut=%q", rows[9].IsSelectable, rows[9].Value, rows[9].Shortcut)
+	}
+}
+
+// --- Test results ---
+
+func TestTestSummary(t *testing.T) {
+	rows := []TestResultRow{
+		{Name: "a", Status: "Passed"},
+		{Name: "b", Status: "Failed", Failed: true},
+		{Name: "c", Status: "Timeout", Failed: true},
+		{Name: "d", Status: "Not Run", Detail: "Disabled"},
+		{Name: "e", Status: "Passed"},
+	}
+	passed, failed, other := TestSummary(rows)
+	if passed != 2 || failed != 2 || other != 1 {
+		t.Errorf("TestSummary: got %d/%d/%d want 2/2/1", passed, failed, other)
+	}
+}
+
+func TestRenderTestResults_KeepsSelectionVisible(t *testing.T) {
+	var rows []TestResultRow
+	for i := 0; i < 30; i++ {
+		rows = append(rows, TestResultRow{Name: fmt.Sprintf("test_%02d", i), Status: "Passed", Duration: "0.01 s"})
+	}
+	out := RenderTestResults(TestResultsState{Rows: rows, Filter: "test_"}, 29, Theme{}, 12, 80)
+	if !strings.Contains(out, "test_29") {
+		t.Error("selected row should be scrolled into view")
+	}
+	if strings.Contains(out, "test_00") {
+		t.Error("rows above the window should not be rendered")
+	}
+	if !strings.Contains(out, "30 passed") || !strings.Contains(out, "filter: test_") {
+		t.Error("summary line should show counts and filter")
+	}
+}
+
 // --- OutputBuffer ---
 
 func newTestBuffer() *OutputBuffer {
@@ -611,3 +751,224 @@ func TestApplyScrollState_MaxScrollCalculation(t *testing.T) {
 		}
 	}
 }
+
+// --- Cache view ---
+
+func TestRenderCacheView_ShowsSelectedHelp(t *testing.T) {
+	state := CacheViewState{
+		Rows: []CacheRow{
+			{Name: "BUILD_SHARED_LIBS", Type: "BOOL", Value: "ON", Help: "Build shared libraries", Modified: true},
+			{Name: "CMAKE_BUILD_TYPE", Type: "STRING", Value: "Debug", Help: "Choose the type of build"},
+		},
+		Total:   5,
+		Pending: 1,
+	}
+	out := RenderCacheView(state, 0, Theme{}, 16, 100)
+	for _, want := range []string{"2 of 5 entries", "1 pending", "BUILD_SHARED_LIBS", "Build shared libraries"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cache view missing %q", want)
+		}
+	}
+	if strings.Contains(out, "Choose the type of build") {
+		t.Error("only the selected entry's help should be shown")
+	}
+}
+
+// --- Error list ---
+
+func TestRenderDiagnostics_ShowsSummaryAndSelected(t *testing.T) {
+	state := DiagnosticsViewState{
+		Rows: []DiagnosticRow{
+			{Location: "src/main.cpp:12:5", Message: "'foo' was not declared in this scope", Notes: []string{"src/foo.h:3:6: 'fooBar' declared here"}, IsError: true},
+			{Location: "src/util.cpp:40:9", Message: "unused variable 'x'"},
+		},
+		Errors:   1,
+		Warnings: 1,
+	}
+	out := RenderDiagnostics(state, 0, Theme{}, 16, 120)
+	for _, want := range []string{"1 error · 1 warning", "src/main.cpp:12:5", "src/util.cpp:40:9", "'fooBar' declared here"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("error list missing %q", want)
+		}
+	}
+}
+
+func TestBuildEffectiveSettingRows_ShowsSources(t *testing.T) {
+	settings := config.Resolve(nil, &config.ProjectConfig{Generator: "Ninja", Jobs: 8}, nil)
+	rows := BuildEffectiveSettingRows(settings)
+
+	values := map[string]string{}
+	for _, row := range rows {
+		if row.Enabled {
+			t.Errorf("effective setting row %q must not be selectable", row.Label)
+		}
+		values[row.Label] = row.Value
+	}
+	expected := map[string]string{
+		config.SourceProject: "loaded",
+		"Generator":          "Ninja (" + config.SourceProject + ")",
+		"Configuration":      "Debug (" + config.SourceDefault + ")",
+		"Jobs":               "8 (" + config.SourceProject + ")",
+		"Compile Commands":   "OFF (" + config.SourceDefault + ")",
+		"Build on PTY":       "OFF (" + config.SourceDefault + ")",
+	}
+	for label, want := range expected {
+		if values[label] != want {
+			t.Errorf("%s: got %q, want %q", label, values[label], want)
+		}
+	}
+
+	invalid := BuildEffectiveSettingRows(config.Resolve(nil, nil, fmt.Errorf("failed to parse")))
+	if invalid[0].Value != "invalid, ignored" {
+		t.Errorf("expected invalid %s to be reported, got %q", config.SourceProject, invalid[0].Value)
+	}
+}
+
+func TestRenderCMakeArgsView(t *testing.T) {
+	rows := []CMakeArgRow{
+		{Kind: CMakeArgDefinition, Value: "JUCE_COPY_PLUGIN_AFTER_BUILD=ON"},
+		{Kind: CMakeArgExtra, Value: "--log-level=VERBOSE"},
+	}
+	if got := CMakeArgsPreview(rows); got != "cmake … -DJUCE_COPY_PLUGIN_AFTER_BUILD=ON --log-level=VERBOSE" {
+		t.Errorf("unexpected preview %q", got)
+	}
+
+	state := CMakeArgsViewState{Generator: "Ninja", List: "juce", ListCount: 2, InUse: true, Rows: rows, Preview: CMakeArgsPreview(rows)}
+	out := RenderCMakeArgsView(state, 0, Theme{}, 16, 100)
+	for _, want := range []string{"Ninja · list juce · in use · 2 lists · definitions: 1 · arguments: 1", "JUCE_COPY_PLUGIN_AFTER_BUILD=ON", "-DJUCE_COPY_PLUGIN_AFTER_BUILD=ON --log-level"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cmake args view missing %q", want)
+		}
+	}
+
+	empty := RenderCMakeArgsView(CMakeArgsViewState{Generator: "Ninja", Preview: CMakeArgsPreview(nil)}, 0, Theme{}, 16, 100)
+	if !strings.Contains(empty, "Ninja · no lists yet") {
+		t.Error("a generator without lists must say so")
+	}
+}
+
+func TestParseSGR(t *testing.T) {
+	plain, spans := ParseSGR("\x1b[1m\x1b[31mmain.cpp:3:5: \x1b[0merror: \x1b[38;5;208mbad\x1b[39m thing\x1b[K")
+	if plain != "main.cpp:3:5: error: bad thing" {
+		t.Errorf("plain text: got %q", plain)
+	}
+	want := []StyledSpan{
+		{Text: "main.cpp:3:5: ", Style: SGRStyle{Foreground: "1", Bold: true}},
+		{Text: "error: "},
+		{Text: "bad", Style: SGRStyle{Foreground: "208"}},
+		{Text: " thing"},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("got %d spans %+v, want %d", len(spans), spans, len(want))
+	}
+	for i := range want {
+		if spans[i] != want[i] {
+			t.Errorf("span %d: got %+v, want %+v", i, spans[i], want[i])
+		}
+	}
+
+	if _, spans := ParseSGR("\x1b[38;2;255;128;0mtrue\x1b[0m"); len(spans) != 1 || spans[0].Style.Foreground != "#ff8000" {
+		t.Errorf("true color: got %+v", spans)
+	}
+	// Only non-color sequences, or none: no spans, so the theme colors the line
+	if plain, spans := ParseSGR("[3/9] Building\x1b[K"); plain != "[3/9] Building" || spans != nil {
+		t.Errorf("got %q %+v, want plain text without spans", plain, spans)
+	}
+	if _, spans := ParseSGR("plain"); spans != nil {
+		t.Errorf("expected no spans for plain text, got %+v", spans)
+	}
+}
+
+func TestWrapSpans_KeepsStyleAcrossLines(t *testing.T) {
+	red := SGRStyle{Foreground: "1"}
+	lines := wrapSpans([]StyledSpan{{Text: "ab"}, {Text: "cdefg", Style: red}}, 4)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %+v", lines)
+	}
+	if len(lines[0]) != 2 || lines[0][1].Text != "cd" || lines[0][1].Style != red {
+		t.Errorf("first line: got %+v", lines[0])
+	}
+	if len(lines[1]) != 1 || lines[1][0].Text != "efg" || lines[1][0].Style != red {
+		t.Errorf("second line must keep the span's color: got %+v", lines[1])
+	}
+}
+
+func TestOutputBuffer_StoresPlainTextAndSpans(t *testing.T) {
+	buffer := &OutputBuffer{maxLines: 10}
+	buffer.Append("\x1b[32mok\x1b[0m", TypeStdout)
+	buffer.ReplaceLast("\x1b[31mfailed\x1b[0m", TypeStdout)
+	lines := buffer.GetAllLines()
+	if len(lines) != 1 || lines[0].Text != "failed" || len(lines[0].Spans) != 1 || lines[0].Spans[0].Style.Foreground != "1" {
+		t.Errorf("got %+v", lines)
+	}
+}
+
+type spoolRecorder struct {
+	strings.Builder
+	closed bool
+}
+
+func (r *spoolRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestOutputBuffer_SpoolsEveryFinalLine(t *testing.T) {
+	buffer := &OutputBuffer{maxLines: 2}
+	log := &spoolRecorder{}
+	buffer.SpoolTo(log)
+
+	buffer.Append("Running: cmake --build", TypeInfo)
+	buffer.Append("[1/3] a.cpp", TypeStdout)
+	buffer.ReplaceLast("[2/3] b.cpp", TypeStdout)
+	buffer.ReplaceLast("[3/3] link", TypeStdout)
+	buffer.Append("\x1b[31mfailed\x1b[0m", TypeStderr)
+	buffer.Clear()
+	buffer.SpoolTo(nil)
+
+	if !log.closed {
+		t.Error("expected SpoolTo(nil) to close the log")
+	}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(log.String()), "\n") {
+		// Drop the HH:MM:SS timestamp
+		got = append(got, line[9:])
+	}
+	want := []string{"[info] Running: cmake --build", "[stdout] [3/3] link", "[stderr] failed"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+
+func TestReadLog_RoundTripsSpooledLines(t *testing.T) {
+	buffer := &OutputBuffer{maxLines: 1}
+	log := &spoolRecorder{}
+	buffer.SpoolTo(log)
+	buffer.Append("Running: cmake --build", TypeInfo)
+	buffer.Append("", TypeStdout)
+	buffer.Append("\x1b[31mfailed\x1b[0m", TypeStderr)
+	buffer.SpoolTo(nil)
+
+	saved, err := ReadLog(strings.NewReader("# cake build, 2026-10-17 14:23:05\n" + log.String()))
+	if err != nil {
+		t.Fatalf("ReadLog: %v", err)
+	}
+	lines := saved.GetAllLines()
+	if len(lines) != 4 {
+		t.Fatalf("expected every line kept, got %d: %+v", len(lines), lines)
+	}
+	if lines[0].Type != TypeInfo || lines[0].Time != "14:23:05" {
+		t.Errorf("header: got %+v", lines[0])
+	}
+	want := []OutputLine{
+		{Type: TypeInfo, Text: "Running: cmake --build"},
+		{Type: TypeStdout, Text: ""},
+		{Type: TypeStderr, Text: "failed"},
+	}
+	for i, line := range lines[1:] {
+		if line.Type != want[i].Type || line.Text != want[i].Text {
+			t.Errorf("line %d: got [%s] %q, want [%s] %q", i+1, line.Type, line.Text, want[i].Type, want[i].Text)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitArgs splits a typed command line into arguments. Whitespace separates arguments;
+// single and double quotes group. A backslash only escapes a quote, or whitespace outside
+// quotes, and is kept literally everywhere else, so Windows paths such as C:\work\in.wav
+// and \\server\share pass through. No variable or glob expansion takes place.
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case ch == '\\' && i+1 < len(runes) && isEscapable(runes[i+1], quote):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				current.WriteRune(ch)
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+			inArg = true
+		case ch == ' ' || ch == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(ch)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// isEscapable reports whether a backslash before next escapes it: inside double quotes
+// only a double quote, outside quotes a quote or whitespace, inside single quotes nothing
+func isEscapable(next, quote rune) bool {
+	switch quote {
+	case '\'':
+		return false
+	case '"':
+		return next == '"'
+	}
+	return next == '"' || next == '\'' || next == ' ' || next == '\t'
+}
+
+// JoinArgs is the inverse of SplitArgs: arguments with whitespace or quotes are single-quoted
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t'\"\\") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
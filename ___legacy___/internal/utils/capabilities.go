@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// GeneratorCapability is one entry of the "generators" array printed by `cmake -E capabilities`
+type GeneratorCapability struct {
+	Name            string   `json:"name"`
+	ExtraGenerators []string `json:"extraGenerators"`
+	PlatformSupport bool     `json:"platformSupport"`
+	ToolsetSupport  bool     `json:"toolsetSupport"`
+}
+
+// cmakeCapabilities is the subset of `cmake -E capabilities` output cake reads
+type cmakeCapabilities struct {
+	Generators []GeneratorCapability `json:"generators"`
+}
+
+// QueryCMakeGenerators runs `cmake -E capabilities` and returns every generator
+// name this cmake accepts for -G, extra generators included
+func QueryCMakeGenerators(vsEnv []string) ([]string, error) {
+	cmakePath := FindExecutableInEnv("cmake", vsEnv)
+	cmd := exec.Command(cmakePath, "-E", "capabilities")
+	if len(vsEnv) > 0 {
+		cmd.Env = vsEnv
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("QueryCMakeGenerators: %w", err)
+	}
+	return ParseCMakeGenerators(output)
+}
+
+// ParseCMakeGenerators extracts generator names from `cmake -E capabilities` JSON.
+// Extra generators are expanded to CMake's "<Extra> - <Base>" form, e.g. "CodeBlocks - Ninja".
+func ParseCMakeGenerators(data []byte) ([]string, error) {
+	var caps cmakeCapabilities
+	if err := json.Unmarshal(data, &caps); err != nil {
+		return nil, fmt.Errorf("ParseCMakeGenerators: %w", err)
+	}
+
+	var names []string
+	for _, gen := range caps.Generators {
+		if gen.Name == "" {
+			continue
+		}
+		names = append(names, gen.Name)
+		for _, extra := range gen.ExtraGenerators {
+			names = append(names, extra+ExtraGeneratorSeparator+gen.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jrengmusic/cake/internal"
+)
+
+// CompileCommandsDefinition makes cmake write compile_commands.json into the build tree.
+// Makefile and Ninja generators honour it; Xcode and Visual Studio ignore it.
+const CompileCommandsDefinition = "CMAKE_EXPORT_COMPILE_COMMANDS:BOOL=ON"
+
+// compileCommandsCopyMarker, in Builds/, holds the SHA-256 of the copy LinkCompileCommands
+// made where symlinks are not allowed, so a later call knows the root file is cake's to replace
+const compileCommandsCopyMarker = ".compile_commands.copy"
+
+// LinkCompileCommands points projectRoot's compile_commands.json at buildDir's, so clangd
+// follows the selected build. A relative symlink is used where the platform allows one,
+// a copy otherwise. Only a symlink or cake's own copy is ever replaced: a compile_commands.json
+// cake did not write is an error. Returns false when buildDir has none, after removing the
+// link to the previous build so clangd does not follow the wrong tree.
+func LinkCompileCommands(projectRoot, buildDir string) (bool, error) {
+	source := filepath.Join(buildDir, internal.CompileCommandsFile)
+	if _, err := os.Stat(source); err != nil {
+		return false, UnlinkCompileCommands(projectRoot)
+	}
+
+	link := filepath.Join(projectRoot, internal.CompileCommandsFile)
+	marker := filepath.Join(projectRoot, internal.BuildsDirName, compileCommandsCopyMarker)
+	target, err := filepath.Rel(projectRoot, source)
+	if err != nil {
+		target = source
+	}
+
+	_, err = os.Lstat(link)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return false, fmt.Errorf("LinkCompileCommands: %w", err)
+	case !ownsCompileCommands(link, marker):
+		return false, fmt.Errorf("LinkCompileCommands: %s was not written by cake; remove it to let cake link the selected build's", link)
+	default:
+		if current, readErr := os.Readlink(link); readErr == nil && current == target {
+			return true, nil
+		}
+		// A link to another build, or a copy from an earlier run
+		if err := os.Remove(link); err != nil {
+			return false, fmt.Errorf("LinkCompileCommands: %w", err)
+		}
+	}
+
+	if err := os.Symlink(target, link); err == nil {
+		// discard: a marker left from an earlier copy no longer matches anything
+		_ = os.Remove(marker)
+		return true, nil
+	}
+	if err := copyFile(source, link); err != nil {
+		return false, fmt.Errorf("LinkCompileCommands: %w", err)
+	}
+	if err := recordCompileCommandsCopy(link, marker); err != nil {
+		return false, fmt.Errorf("LinkCompileCommands: failed to record the copy: %w", err)
+	}
+	return true, nil
+}
+
+// recordCompileCommandsCopy writes the SHA-256 of cake's copy at link into marker
+func recordCompileCommandsCopy(link, marker string) error {
+	sum, err := fileSHA256(link)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(marker, []byte(sum), 0644)
+}
+
+// UnlinkCompileCommands removes projectRoot's compile_commands.json when it is a symlink or
+// cake's own copy; a compile_commands.json cake did not write is left alone
+func UnlinkCompileCommands(projectRoot string) error {
+	link := filepath.Join(projectRoot, internal.CompileCommandsFile)
+	marker := filepath.Join(projectRoot, internal.BuildsDirName, compileCommandsCopyMarker)
+	if _, err := os.Lstat(link); err != nil || !ownsCompileCommands(link, marker) {
+		return nil
+	}
+	if err := os.Remove(link); err != nil {
+		return fmt.Errorf("UnlinkCompileCommands: %w", err)
+	}
+	// discard: the marker only describes the copy just removed
+	_ = os.Remove(marker)
+	return nil
+}
+
+// ownsCompileCommands reports whether link is cake's to replace: a symlink, or a file whose
+// contents are still those of the copy recorded in marker
+func ownsCompileCommands(link, marker string) bool {
+	info, err := os.Lstat(link)
+	if err != nil {
+		return false
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return true
+	}
+	if !info.Mode().IsRegular() {
+		return false
+	}
+	recorded, err := os.ReadFile(marker)
+	if err != nil {
+		return false
+	}
+	sum, err := fileSHA256(link)
+	return err == nil && sum == string(recorded)
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func copyFile(source, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CTest outcomes (SSOT). Exceptions (crashes) are reported as TestStatusFailed with the
+// exception text in CTestCase.Detail.
+const (
+	TestStatusPassed  = "Passed"
+	TestStatusFailed  = "Failed"
+	TestStatusTimeout = "Timeout"
+	TestStatusNotRun  = "Not Run"
+	TestStatusSkipped = "Skipped"
+)
+
+// testDetailDisabled is the Not Run detail of a test with the DISABLED property
+const testDetailDisabled = "Disabled"
+
+// ctestResultPattern matches ctest's per-test summary line, e.g.
+// "1/3 Test #1: unit_tests .......................   Passed    0.01 sec"
+// "2/3 Test #2: crash ............................***Exception: SegFault  0.02 sec"
+var ctestResultPattern = regexp.MustCompile(`^\d+/\d+\s+Test\s+#(\d+):\s+(\S+)\s+\.*\s*(?:\*\*\*)?(.+?)\s+([0-9.]+)\s+sec$`)
+
+// CTestCase is the outcome of one test in a ctest run
+type CTestCase struct {
+	Number   int
+	Name     string
+	Status   string // TestStatusPassed, TestStatusFailed, ...
+	Detail   string // Extra outcome text: "Exception: SegFault", "Disabled", failed regex, ...
+	Duration time.Duration
+}
+
+// Failed reports whether the test counts as failed — what `ctest --rerun-failed` reruns.
+// Disabled tests are reported as Not Run but are not failures.
+func (c CTestCase) Failed() bool {
+	switch c.Status {
+	case TestStatusFailed, TestStatusTimeout:
+		return true
+	case TestStatusNotRun:
+		return c.Detail != testDetailDisabled
+	default:
+		return false
+	}
+}
+
+// ParseCTestLine parses one line of ctest output; ok is false for anything but a per-test result line
+func ParseCTestLine(line string) (CTestCase, bool) {
+	match := ctestResultPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return CTestCase{}, false
+	}
+
+	number, _ := strconv.Atoi(match[1])
+	seconds, _ := strconv.ParseFloat(match[4], 64)
+	status, detail := parseCTestOutcome(match[3])
+
+	return CTestCase{
+		Number:   number,
+		Name:     match[2],
+		Status:   status,
+		Detail:   detail,
+		Duration: time.Duration(seconds * float64(time.Second)),
+	}, true
+}
+
+// parseCTestOutcome splits the outcome column ("Passed", "Failed  Required regular expression ...",
+// "Not Run (Disabled)", "Exception: SegFault") into a status and its detail
+func parseCTestOutcome(outcome string) (status, detail string) {
+	outcome = strings.TrimSpace(outcome)
+
+	if strings.HasPrefix(outcome, "Exception") {
+		return TestStatusFailed, outcome
+	}
+	for _, known := range []string{TestStatusPassed, TestStatusFailed, TestStatusTimeout, TestStatusNotRun, TestStatusSkipped} {
+		if rest, ok := strings.CutPrefix(outcome, known); ok {
+			detail = strings.Trim(strings.TrimSpace(rest), "()")
+			return known, detail
+		}
+	}
+	// Unknown outcome — keep it visible rather than guessing
+	return TestStatusFailed, outcome
+}
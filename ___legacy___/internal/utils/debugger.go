@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Debugger names cake knows the command line of (SSOT)
+const (
+	DebuggerGDB  = "gdb"
+	DebuggerLLDB = "lldb"
+)
+
+// debuggerSearchOrder lists the debuggers tried when none is configured:
+// lldb first where it is the platform toolchain's debugger
+func debuggerSearchOrder() []string {
+	if runtime.GOOS == "darwin" {
+		return []string{DebuggerLLDB, DebuggerGDB}
+	}
+	return []string{DebuggerGDB, DebuggerLLDB}
+}
+
+// FindDebugger returns the path of debugger, or of the first installed gdb/lldb when empty
+func FindDebugger(debugger string) (string, error) {
+	if debugger != "" {
+		path, err := exec.LookPath(debugger)
+		if err != nil {
+			return "", fmt.Errorf("debugger %s not found: %w", debugger, err)
+		}
+		return path, nil
+	}
+	for _, name := range debuggerSearchOrder() {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no debugger found: install gdb or lldb, or set [debug] debugger")
+}
+
+// IsLLDB reports whether debugger takes lldb's command line (lldb, lldb-18, /usr/bin/lldb, ...)
+func IsLLDB(debugger string) bool {
+	return strings.HasPrefix(strings.ToLower(filepath.Base(debugger)), DebuggerLLDB)
+}
+
+// DebuggerArgs returns the debugger arguments that load executable with args and run
+// initCommands first: `-ex <cmd>... --args exe args` for gdb, `-o <cmd>... -- exe args` for lldb
+func DebuggerArgs(debugger string, initCommands []string, executable string, args []string) []string {
+	commandFlag, separator := "-ex", "--args"
+	if IsLLDB(debugger) {
+		commandFlag, separator = "-o", "--"
+	}
+
+	var debuggerArgs []string
+	for _, command := range initCommands {
+		debuggerArgs = append(debuggerArgs, commandFlag, command)
+	}
+	debuggerArgs = append(debuggerArgs, separator, executable)
+	return append(debuggerArgs, args...)
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// StartDetached starts cmd in its own session with no terminal, so a GUI editor neither
+// draws over the TUI nor dies with cake. It returns without waiting; the child is reaped in
+// the background when it exits, so it does not stay a zombie until cake quits.
+func StartDetached(cmd *exec.Cmd) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cmd.Start failed: %w", err)
+	}
+	go func() {
+		// discard: the editor runs on its own; its exit status has no one to report to
+		_ = cmd.Wait()
+	}()
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// StartDetached starts cmd without a console in its own process group, so a GUI editor
+// does not share cake's console or its Ctrl+C, and releases it without waiting
+func StartDetached(cmd *exec.Cmd) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cmd.Start failed: %w", err)
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Diagnostic severities (SSOT). "fatal error" is reported as SeverityError.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+)
+
+var (
+	// gccDiagnosticPattern matches GCC and Clang, e.g.
+	// "src/main.cpp:12:5: error: 'foo' was not declared in this scope"
+	// "/abs/path/util.h:3: warning: extra tokens"
+	gccDiagnosticPattern = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)?\s+(fatal error|error|warning|note):\s*(.*)$`)
+
+	// msvcDiagnosticPattern matches cl.exe, e.g.
+	// "C:\src\main.cpp(12,5): error C2065: 'foo': undeclared identifier"
+	// "C:\src\main.cpp(12): note: see declaration of 'bar'"
+	msvcDiagnosticPattern = regexp.MustCompile(`^(.+?)\((\d+)(?:,(\d+))?\)\s*:\s+(fatal error|error|warning|note)(?:\s+([A-Z]+\d+))?\s*:\s*(.*)$`)
+
+	// msvcToolPattern matches link.exe and lib.exe, e.g.
+	// "LINK : fatal error LNK1104: cannot open file 'foo.lib'"
+	// "main.obj : error LNK2019: unresolved external symbol bar"
+	msvcToolPattern = regexp.MustCompile(`^(\S+)\s+:\s+(fatal error|error|warning)\s+([A-Z]+\d+)\s*:\s*(.*)$`)
+
+	// toolDiagnosticPattern matches drivers and linkers that report without a source line, e.g.
+	// "collect2: error: ld returned 1 exit status", "ld.lld: error: undefined symbol: bar"
+	toolDiagnosticPattern = regexp.MustCompile(`^(?:\S*[/\\])?(ld(?:\.lld|\.gold|\.bfd|64\.lld)?|lld-link|collect2|cc1plus|cc1|clang(?:\+\+)?|[gc]\+\+|gcc|cc):\s+(fatal error|error|warning):\s*(.*)$`)
+
+	// gnuLinkerPattern matches GNU ld's per-object errors, optionally prefixed by the linker, e.g.
+	// "/usr/bin/ld: main.cpp:(.text+0x1e): undefined reference to `bar()'"
+	gnuLinkerPattern = regexp.MustCompile(`^(?:\S*ld(?:\.\w+)?:\s+)?(.+?):\(\.[^)]*\):\s+(.*)$`)
+
+	// appleLinkerPattern matches ld64's undefined symbol report
+	appleLinkerPattern = regexp.MustCompile(`^(Undefined symbols for architecture \S+):$`)
+
+	ansiEscapePattern = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+)
+
+// Diagnostic is one compiler or linker error or warning from the build output
+type Diagnostic struct {
+	File     string // As printed; empty when Tool reported it
+	Line     int    // 0 = unknown
+	Column   int    // 0 = unknown
+	Severity string // SeverityError, SeverityWarning (SeverityNote only from ParseDiagnosticLine)
+	Code     string // MSVC and link.exe code, e.g. "C2065", "LNK2019"
+	Tool     string // Linker or driver that reported it without a source file ("collect2", "LINK", "ld")
+	Message  string
+	Notes    []string // Note lines that followed, "file:line: text"
+}
+
+// IsError reports whether the diagnostic fails the build
+func (d Diagnostic) IsError() bool {
+	return d.Severity == SeverityError
+}
+
+// Location returns "file:line:col", as much of it as is known, else the reporting tool
+func (d Diagnostic) Location() string {
+	if d.File == "" {
+		return d.Tool
+	}
+	location := d.File
+	if d.Line > 0 {
+		location += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			location += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return location
+}
+
+// ParseDiagnosticLine parses one line of build output; ok is false for anything but a
+// GCC, Clang, MSVC or linker diagnostic
+func ParseDiagnosticLine(line string) (Diagnostic, bool) {
+	line = strings.TrimRight(ansiEscapePattern.ReplaceAllString(line, ""), " \r")
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return Diagnostic{}, false
+	}
+
+	if match := toolDiagnosticPattern.FindStringSubmatch(trimmed); match != nil {
+		return Diagnostic{Tool: match[1], Severity: diagnosticSeverity(match[2]), Message: match[3]}, true
+	}
+	if match := msvcDiagnosticPattern.FindStringSubmatch(trimmed); match != nil {
+		return Diagnostic{
+			File:     match[1],
+			Line:     atoiOrZero(match[2]),
+			Column:   atoiOrZero(match[3]),
+			Severity: diagnosticSeverity(match[4]),
+			Code:     match[5],
+			Message:  match[6],
+		}, true
+	}
+	if match := msvcToolPattern.FindStringSubmatch(trimmed); match != nil {
+		return Diagnostic{Tool: match[1], Severity: diagnosticSeverity(match[2]), Code: match[3], Message: match[4]}, true
+	}
+	if match := gccDiagnosticPattern.FindStringSubmatch(trimmed); match != nil {
+		return Diagnostic{
+			File:     match[1],
+			Line:     atoiOrZero(match[2]),
+			Column:   atoiOrZero(match[3]),
+			Severity: diagnosticSeverity(match[4]),
+			Message:  match[5],
+		}, true
+	}
+	if match := gnuLinkerPattern.FindStringSubmatch(trimmed); match != nil {
+		return Diagnostic{File: match[1], Severity: SeverityError, Tool: "ld", Message: match[2]}, true
+	}
+	if match := appleLinkerPattern.FindStringSubmatch(trimmed); match != nil {
+		return Diagnostic{Tool: "ld", Severity: SeverityError, Message: match[1]}, true
+	}
+	return Diagnostic{}, false
+}
+
+func diagnosticSeverity(text string) string {
+	switch text {
+	case "fatal error", "error":
+		return SeverityError
+	case "warning":
+		return SeverityWarning
+	default:
+		return SeverityNote
+	}
+}
+
+func atoiOrZero(text string) int {
+	value, _ := strconv.Atoi(text)
+	return value
+}
+
+// DiagnosticCollector gathers diagnostics from streamed build output. Notes attach to the
+// error or warning before them; a diagnostic repeated by another translation unit (a warning
+// in a shared header) is kept once. Safe for the stdout and stderr goroutines.
+type DiagnosticCollector struct {
+	mu          sync.Mutex
+	diagnostics []Diagnostic
+	seen        map[string]bool
+	attachNotes bool // The last error or warning was new, so following notes belong to it
+}
+
+// NewDiagnosticCollector returns an empty collector
+func NewDiagnosticCollector() *DiagnosticCollector {
+	return &DiagnosticCollector{seen: map[string]bool{}}
+}
+
+// Add parses line and records it if it is a diagnostic
+func (c *DiagnosticCollector) Add(line string) {
+	diagnostic, ok := ParseDiagnosticLine(line)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if diagnostic.Severity == SeverityNote {
+		if c.attachNotes && len(c.diagnostics) > 0 {
+			last := &c.diagnostics[len(c.diagnostics)-1]
+			last.Notes = append(last.Notes, diagnostic.Location()+": "+diagnostic.Message)
+		}
+		return
+	}
+
+	key := fmt.Sprintf("%s|%d|%d|%s|%s|%s", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Severity, diagnostic.Tool, diagnostic.Message)
+	if c.seen[key] {
+		c.attachNotes = false
+		return
+	}
+	c.seen[key] = true
+	c.diagnostics = append(c.diagnostics, diagnostic)
+	c.attachNotes = true
+}
+
+// Diagnostics returns the diagnostics collected so far, in output order
+func (c *DiagnosticCollector) Diagnostics() []Diagnostic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Diagnostic(nil), c.diagnostics...)
+}
+
+// CountDiagnostics returns the number of errors and warnings
+func CountDiagnostics(diagnostics []Diagnostic) (errors, warnings int) {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.IsError() {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// ResolveDiagnosticPath makes a printed path absolute. Ninja and Visual Studio run the
+// compiler in the build tree, so a relative path is looked up there first, then under the
+// project root; a path found in neither is taken relative to the build tree.
+func ResolveDiagnosticPath(file, buildDir, projectRoot string) string {
+	if file == "" || filepath.IsAbs(file) {
+		return file
+	}
+	for _, dir := range []string{buildDir, projectRoot} {
+		candidate := filepath.Join(dir, file)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return filepath.Join(buildDir, file)
+}
+
+// WriteQuickfix writes diagnostics as a vim errorfile, one "file:line:col: severity: message"
+// line each with absolute paths, which vim's default 'errorformat' reads (`vim -q path`, `:cfile`)
+func WriteQuickfix(path string, diagnostics []Diagnostic, buildDir, projectRoot string) error {
+	var content strings.Builder
+	for _, diagnostic := range diagnostics {
+		message := diagnostic.Message
+		if diagnostic.Code != "" {
+			message = diagnostic.Code + ": " + message
+		}
+		if diagnostic.Line == 0 {
+			// Linker errors have no line to jump to; vim lists them as text
+			content.WriteString(diagnostic.Location() + ": " + diagnostic.Severity + ": " + message + "\n")
+			continue
+		}
+		column := diagnostic.Column
+		if column < 1 {
+			column = 1
+		}
+		file := ResolveDiagnosticPath(diagnostic.File, buildDir, projectRoot)
+		fmt.Fprintf(&content, "%s:%d:%d: %s: %s\n", file, diagnostic.Line, column, diagnostic.Severity, message)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("WriteQuickfix: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("WriteQuickfix: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Editor argument template placeholders
+const (
+	EditorFilePlaceholder   = "{file}"
+	EditorLinePlaceholder   = "{line}"
+	EditorColumnPlaceholder = "{column}"
+	EditorDirPlaceholder    = "{dir}"
+)
+
+// DefaultEditor is used when neither the config, $VISUAL nor $EDITOR names one
+const DefaultEditor = "nvim"
+
+// guiEditors open their own window: cake starts them detached instead of handing them the terminal
+var guiEditors = map[string]bool{
+	"code": true, "codium": true, "cursor": true, "subl": true, "sublime_text": true,
+	"zed": true, "clion": true, "idea": true, "gvim": true, "mvim": true,
+	"gedit": true, "kate": true, "notepad++": true,
+}
+
+// ResolveEditor returns the editor command: configured, else $VISUAL, else $EDITOR, else nvim
+func ResolveEditor(configured string) string {
+	for _, editor := range []string{configured, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if strings.TrimSpace(editor) != "" {
+			return editor
+		}
+	}
+	return DefaultEditor
+}
+
+// IsGUIEditor reports whether editor opens its own window rather than running in the terminal
+func IsGUIEditor(executable string) bool {
+	return guiEditors[EditorName(executable)]
+}
+
+// EditorOpenArgs returns the arguments that open dir, from template ({dir}; empty = just the directory)
+func EditorOpenArgs(template, dir string) ([]string, error) {
+	if template == "" {
+		template = EditorDirPlaceholder
+	}
+	parts, err := SplitArgs(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid editor open template: %w", err)
+	}
+	args := make([]string, 0, len(parts))
+	for _, part := range parts {
+		args = append(args, strings.ReplaceAll(part, EditorDirPlaceholder, dir))
+	}
+	return args, nil
+}
+
+// editorGotoTemplates holds the arguments that open {file} at {line}:{column}, by editor name.
+// The [editor.goto] table of the user config adds editors and overrides these.
+var editorGotoTemplates = map[string]string{
+	"nvim":        "+{line} {file}",
+	"vim":         "+{line} {file}",
+	"vi":          "+{line} {file}",
+	"gvim":        "+{line} {file}",
+	"emacs":       "+{line}:{column} {file}",
+	"emacsclient": "+{line}:{column} {file}",
+	"nano":        "+{line},{column} {file}",
+	"micro":       "+{line}:{column} {file}",
+	"kak":         "+{line}:{column} {file}",
+	"hx":          "{file}:{line}:{column}",
+	"helix":       "{file}:{line}:{column}",
+	"code":        "--goto {file}:{line}:{column}",
+	"codium":      "--goto {file}:{line}:{column}",
+	"cursor":      "--goto {file}:{line}:{column}",
+	"subl":        "{file}:{line}:{column}",
+	"zed":         "{file}:{line}:{column}",
+	"clion":       "--line {line} --column {column} {file}",
+	"idea":        "--line {line} --column {column} {file}",
+}
+
+// EditorName returns the name templates are looked up by: the base name of the
+// command's executable, without extension ("/usr/bin/nvim" -> "nvim", "code.cmd" -> "code")
+func EditorName(executable string) string {
+	name := filepath.Base(executable)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// IsVimEditor reports whether editor takes a vim errorfile with -q
+func IsVimEditor(executable string) bool {
+	switch EditorName(executable) {
+	case "nvim", "vim", "vi", "gvim":
+		return true
+	}
+	return false
+}
+
+// EditorGotoArgs returns the arguments that open file at line:column in editor, from
+// templates (by editor name) or the built-in ones. Unknown editors just get the file.
+// The template is split before substitution, so paths with spaces stay one argument.
+func EditorGotoArgs(executable string, templates map[string]string, file string, line, column int) ([]string, error) {
+	name := EditorName(executable)
+	template, ok := templates[name]
+	if !ok {
+		template, ok = editorGotoTemplates[name]
+	}
+	if !ok {
+		template = EditorFilePlaceholder
+	}
+
+	parts, err := SplitArgs(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid goto template for %s: %w", name, err)
+	}
+	if line < 1 {
+		line = 1
+	}
+	if column < 1 {
+		column = 1
+	}
+	replacer := strings.NewReplacer(
+		EditorFilePlaceholder, file,
+		EditorLinePlaceholder, strconv.Itoa(line),
+		EditorColumnPlaceholder, strconv.Itoa(column),
+	)
+	args := make([]string, 0, len(parts))
+	for _, part := range parts {
+		args = append(args, replacer.Replace(part))
+	}
+	return args, nil
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// MergeEnv returns base with overrides (NAME=VALUE) applied, for exec.Cmd.Env.
+// An empty base means the current process environment. Names compare case-insensitively on Windows.
+// Returns base unchanged when there is nothing to override.
+func MergeEnv(base []string, overrides []string) []string {
+	if len(overrides) == 0 {
+		return base
+	}
+	if len(base) == 0 {
+		base = os.Environ()
+	}
+
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, entry := range base {
+		if !envOverridden(entry, overrides) {
+			merged = append(merged, entry)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+func envOverridden(entry string, overrides []string) bool {
+	name, _, _ := strings.Cut(entry, "=")
+	for _, override := range overrides {
+		overrideName, _, _ := strings.Cut(override, "=")
+		if overrideName == name || (runtime.GOOS == "windows" && strings.EqualFold(overrideName, name)) {
+			return true
+		}
+	}
+	return false
+}
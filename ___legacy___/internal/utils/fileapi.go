@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileAPIClientName is cake's stateful client directory under .cmake/api/v1/query/
+const FileAPIClientName = "client-cake"
+
+// FileAPIQueryFile is the stateful query file inside the client directory
+const FileAPIQueryFile = "query.json"
+
+// File API object kinds cake requests (SSOT for query and reply)
+const (
+	FileAPIKindCodemodel  = "codemodel"
+	FileAPIKindCache      = "cache"
+	FileAPIKindToolchains = "toolchains"
+)
+
+// fileAPIRequests lists the object kinds and major versions in cake's query
+var fileAPIRequests = []fileAPIRequest{
+	{Kind: FileAPIKindCodemodel, Version: 2},
+	{Kind: FileAPIKindCache, Version: 2},
+	{Kind: FileAPIKindToolchains, Version: 1},
+}
+
+type fileAPIRequest struct {
+	Kind    string `json:"kind"`
+	Version int    `json:"version"`
+}
+
+type fileAPIQuery struct {
+	Requests []fileAPIRequest `json:"requests"`
+}
+
+// GetFileAPIDirectory returns <buildDir>/.cmake/api/v1
+func GetFileAPIDirectory(buildDir string) string {
+	return filepath.Join(buildDir, ".cmake", "api", "v1")
+}
+
+// GetFileAPIReplyDirectory returns <buildDir>/.cmake/api/v1/reply
+func GetFileAPIReplyDirectory(buildDir string) string {
+	return filepath.Join(GetFileAPIDirectory(buildDir), "reply")
+}
+
+// WriteFileAPIQuery writes cake's File API query into buildDir so the next
+// configure produces a codemodel-v2, cache-v2 and toolchains-v1 reply
+func WriteFileAPIQuery(buildDir string) error {
+	clientDir := filepath.Join(GetFileAPIDirectory(buildDir), "query", FileAPIClientName)
+	if err := os.MkdirAll(clientDir, 0755); err != nil {
+		return fmt.Errorf("WriteFileAPIQuery: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fileAPIQuery{Requests: fileAPIRequests}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("WriteFileAPIQuery: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(clientDir, FileAPIQueryFile), data, 0644); err != nil {
+		return fmt.Errorf("WriteFileAPIQuery: %w", err)
+	}
+	return nil
+}
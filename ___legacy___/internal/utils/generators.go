@@ -1,19 +1,82 @@
 package utils
 
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jrengmusic/cake/internal"
+)
+
 // Generator name constants - SSOT for all generator references
 // These are the CMake generator names passed to -G flag
 const (
-	GeneratorXcode  = "Xcode"
-	GeneratorNinja  = "Ninja"
-	GeneratorVS2026 = "Visual Studio 18 2026"
-	GeneratorVS2022 = "Visual Studio 17 2022"
+	GeneratorXcode            = "Xcode"
+	GeneratorNinja            = "Ninja"
+	GeneratorNinjaMultiConfig = "Ninja Multi-Config"
+	GeneratorUnixMakefiles    = "Unix Makefiles"
+	GeneratorMinGWMakefiles   = "MinGW Makefiles"
+	GeneratorMSYSMakefiles    = "MSYS Makefiles"
+	GeneratorNMakeMakefiles   = "NMake Makefiles"
+	GeneratorNMakeJOM         = "NMake Makefiles JOM"
+	GeneratorWatcomWMake      = "Watcom WMake"
+	GeneratorVS2026           = "Visual Studio 18 2026"
+	GeneratorVS2022           = "Visual Studio 17 2022"
 )
 
+// ExtraGeneratorSeparator joins an extra generator and its base, e.g. "CodeBlocks - Ninja"
+const ExtraGeneratorSeparator = " - "
+
+// buildDirConfigSeparator joins a single-config generator directory and its configuration (Ninja-Debug).
+// GetDirectoryName never emits "-", so the separator always splits unambiguously.
+const buildDirConfigSeparator = "-"
+
+// vsGeneratorPrefix starts every Visual Studio generator name
+const vsGeneratorPrefix = "Visual Studio "
+
+// vsGeneratorPattern captures the year of "Visual Studio <version> <year>"
+var vsGeneratorPattern = regexp.MustCompile(`^Visual Studio \d+ (\d{4})$`)
+
+// vsDirectoryPattern captures the year of a "VS<year>" build directory
+var vsDirectoryPattern = regexp.MustCompile(`^VS(\d{4})$`)
+
+// vsVersionByYear maps a VS release year to its major version, for "VS<year>" → generator name
+var vsVersionByYear = map[string]string{
+	"2026": "18",
+	"2022": "17",
+	"2019": "16",
+	"2017": "15",
+}
+
+// buildToolByGenerator maps base generators to the executable their build files need
+var buildToolByGenerator = map[string]string{
+	GeneratorXcode:            "xcodebuild",
+	GeneratorNinja:            "ninja",
+	GeneratorNinjaMultiConfig: "ninja",
+	GeneratorUnixMakefiles:    "make",
+	GeneratorMinGWMakefiles:   "mingw32-make",
+	GeneratorMSYSMakefiles:    "make",
+	GeneratorNMakeMakefiles:   "nmake",
+	GeneratorNMakeJOM:         "jom",
+	GeneratorWatcomWMake:      "wmake",
+}
+
+// knownExtraGenerators lists the extra generators CMake can prefix a base generator with.
+// Used to map build directory names back to generator names.
+var knownExtraGenerators = []string{"CodeBlocks", "CodeLite", "Eclipse CDT4", "Kate", "Sublime Text 2"}
+
 // ValidGenerators returns all supported generator names
 func ValidGenerators() []string {
 	return []string{
 		GeneratorXcode,
 		GeneratorNinja,
+		GeneratorNinjaMultiConfig,
+		GeneratorUnixMakefiles,
+		GeneratorMinGWMakefiles,
+		GeneratorMSYSMakefiles,
+		GeneratorNMakeMakefiles,
+		GeneratorNMakeJOM,
+		GeneratorWatcomWMake,
 		GeneratorVS2026,
 		GeneratorVS2022,
 	}
@@ -21,36 +84,106 @@ func ValidGenerators() []string {
 
 // GetDirectoryName returns shortened directory name for a generator
 // Used for Builds/<dir>/ path construction
+// Visual Studio generators become VS<year>; other names drop spaces and dashes
+// ("Unix Makefiles" → UnixMakefiles, "CodeBlocks - Ninja" → CodeBlocksNinja)
 func GetDirectoryName(generator string) string {
-	switch generator {
-	case GeneratorVS2026:
-		return "VS2026"
-	case GeneratorVS2022:
-		return "VS2022"
-	default:
-		return generator // Xcode, Ninja unchanged
+	if match := vsGeneratorPattern.FindStringSubmatch(generator); match != nil {
+		return "VS" + match[1]
 	}
+
+	var b strings.Builder
+	for _, r := range generator {
+		if r == ' ' || r == '-' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String() // Xcode, Ninja unchanged
 }
 
 // GetGeneratorNameFromDirectory returns CMake generator name from directory name
 // Used when scanning existing build directories
 func GetGeneratorNameFromDirectory(dirName string) string {
-	switch dirName {
-	case "VS2026":
-		return GeneratorVS2026
-	case "VS2022":
-		return GeneratorVS2022
-	default:
-		return dirName // Xcode, Ninja unchanged
+	if match := vsDirectoryPattern.FindStringSubmatch(dirName); match != nil {
+		if version, ok := vsVersionByYear[match[1]]; ok {
+			return vsGeneratorPrefix + version + " " + match[1]
+		}
+	}
+
+	for _, base := range ValidGenerators() {
+		if GetDirectoryName(base) == dirName {
+			return base
+		}
+		if IsGeneratorIDE(base) {
+			continue // extra generators only wrap Ninja and Makefile generators
+		}
+		for _, extra := range knownExtraGenerators {
+			name := extra + ExtraGeneratorSeparator + base
+			if GetDirectoryName(name) == dirName {
+				return name
+			}
+		}
+	}
+	return dirName // Xcode, Ninja unchanged
+}
+
+// GetBuildDirName returns the Builds/ subdirectory for a generator, configuration and variant.
+// Multi-config generators hold every configuration in one directory (Builds/Xcode/);
+// single-config generators get one directory per configuration (Builds/Ninja-Debug/).
+// Variants other than the default get a suffix (Builds/Ninja-Debug-asan/, Builds/Xcode-asan/).
+func GetBuildDirName(generator, config, variant string) string {
+	dirName := GetDirectoryName(generator)
+	if !IsGeneratorMultiConfig(generator) && config != "" {
+		dirName += buildDirConfigSeparator + config
+	}
+	if variant != "" && variant != VariantDefault {
+		dirName += buildDirConfigSeparator + variant
+	}
+	return dirName
+}
+
+// ParseBuildDirName is the reverse of GetBuildDirName: "Ninja-Debug" → ("Ninja", "Debug", "default"),
+// "VS2022-asan" → ("Visual Studio 17 2022", "", "asan")
+func ParseBuildDirName(dirName string) (generator, config, variant string) {
+	dirName, variant = splitVariantSuffix(dirName)
+	if idx := strings.LastIndex(dirName, buildDirConfigSeparator); idx != -1 {
+		return GetGeneratorNameFromDirectory(dirName[:idx]), dirName[idx+len(buildDirConfigSeparator):], variant
+	}
+	return GetGeneratorNameFromDirectory(dirName), "", variant
+}
+
+// GetBuildDirectory returns the absolute build directory for a generator, configuration and variant.
+// SSOT for Builds/<dir>/ paths — state and every op derive their build directory from it.
+func GetBuildDirectory(projectRoot, generator, config, variant string) string {
+	return filepath.Join(projectRoot, internal.BuildsDirName, GetBuildDirName(generator, config, variant))
+}
+
+// GetBaseGenerator strips an extra generator prefix: "CodeBlocks - Ninja" → "Ninja"
+func GetBaseGenerator(generator string) string {
+	if idx := strings.Index(generator, ExtraGeneratorSeparator); idx != -1 {
+		return generator[idx+len(ExtraGeneratorSeparator):]
 	}
+	return generator
+}
+
+// GetBuildTool returns the executable a generator's build files need,
+// or "" when cake has no way to check (including Visual Studio, detected via vswhere)
+func GetBuildTool(generator string) string {
+	return buildToolByGenerator[GetBaseGenerator(generator)]
+}
+
+// IsGeneratorVS returns true for any Visual Studio generator
+func IsGeneratorVS(generator string) bool {
+	return strings.HasPrefix(generator, vsGeneratorPrefix)
+}
+
+// IsGeneratorMultiConfig returns true if one build tree holds every configuration
+// (selected at build time with --config) instead of fixing CMAKE_BUILD_TYPE at configure time
+func IsGeneratorMultiConfig(generator string) bool {
+	return IsGeneratorIDE(generator) || GetBaseGenerator(generator) == GeneratorNinjaMultiConfig
 }
 
 // IsGeneratorIDE returns true if generator creates an IDE project
 func IsGeneratorIDE(generator string) bool {
-	switch generator {
-	case GeneratorXcode, GeneratorVS2026, GeneratorVS2022:
-		return true
-	default:
-		return false
-	}
+	return generator == GeneratorXcode || IsGeneratorVS(generator)
 }
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jrengmusic/cake/internal"
+)
+
+// Operation log rotation limits: the oldest logs of a build tree go first
+const (
+	LogKeepCount     = 20       // Logs kept per build tree, counting the new one
+	LogMaxTotalBytes = 50 << 20 // Total size the older logs may take up
+)
+
+// logTimeLayout names log files so that they sort chronologically
+const logTimeLayout = "20060102-150405"
+
+// OperationLogDir returns the directory operation logs of buildDir are written to
+func OperationLogDir(buildDir string) string {
+	return filepath.Join(buildDir, filepath.FromSlash(internal.LogsDirName))
+}
+
+// CreateOperationLog rotates the build tree's logs and creates the log of a new operation,
+// "<yyyymmdd-hhmmss>-<operation>.log", starting with a header line naming it
+func CreateOperationLog(buildDir, operation string, now time.Time) (*os.File, error) {
+	dir := OperationLogDir(buildDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("CreateOperationLog: %w", err)
+	}
+	if err := RotateLogs(dir, LogKeepCount-1, LogMaxTotalBytes); err != nil {
+		return nil, fmt.Errorf("CreateOperationLog: %w", err)
+	}
+
+	path := filepath.Join(dir, now.Format(logTimeLayout)+"-"+operation+".log")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("CreateOperationLog: %w", err)
+	}
+	// discard: the header only labels the log; a failed write shows up on the next line anyway
+	_, _ = fmt.Fprintf(file, "# %s %s, %s\n", internal.AppName, operation, now.Format("2006-01-02 15:04:05"))
+	return file, nil
+}
+
+// RotateLogs deletes the oldest *.log files in dir until at most keep remain and they
+// take up at most maxBytes together
+func RotateLogs(dir string, keep int, maxBytes int64) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("RotateLogs: %w", err)
+	}
+
+	type logFile struct {
+		name string
+		size int64
+	}
+	var logs []logFile
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, logFile{name: entry.Name(), size: info.Size()})
+		total += info.Size()
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].name < logs[j].name })
+
+	for len(logs) > 0 && (len(logs) > keep || total > maxBytes) {
+		if err := os.Remove(filepath.Join(dir, logs[0].name)); err != nil {
+			return fmt.Errorf("RotateLogs: %w", err)
+		}
+		total -= logs[0].size
+		logs = logs[1:]
+	}
+	return nil
+}
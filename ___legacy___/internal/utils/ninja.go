@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ninjaUtilityTargets are the phony targets CMake adds to every Ninja tree; not worth offering as build targets
+var ninjaUtilityTargets = map[string]bool{
+	"all":                     true,
+	"clean":                   true,
+	"help":                    true,
+	"edit_cache":              true,
+	"rebuild_cache":           true,
+	"install":                 true,
+	"list_install_components": true,
+	"test":                    true,
+	"package":                 true,
+	"package_source":          true,
+}
+
+// QueryNinjaTargets runs `ninja -t targets` in buildDir and returns the target names it lists.
+// Used for Ninja trees cmake has not answered cake's File API query for.
+func QueryNinjaTargets(buildDir string, vsEnv []string) ([]string, error) {
+	ninjaPath := FindExecutableInEnv("ninja", vsEnv)
+	cmd := exec.Command(ninjaPath, "-C", buildDir, "-t", "targets")
+	if len(vsEnv) > 0 {
+		cmd.Env = vsEnv
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("QueryNinjaTargets: %w", err)
+	}
+	return ParseNinjaTargets(output), nil
+}
+
+// ParseNinjaTargets extracts CMake target names from `ninja -t targets` output ("<output>: <rule>" per line).
+// Keeps phony aliases and linker outputs at the top of the tree; drops files in subdirectories,
+// per-config aliases (app:Debug), CMake's utility targets and cmake_* internals. Sorted, no duplicates.
+func ParseNinjaTargets(data []byte) []string {
+	seen := make(map[string]bool)
+	var targets []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		name, rule, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ": ")
+		if !ok || name == "" {
+			continue
+		}
+		if rule != "phony" && !strings.Contains(rule, "_LINKER__") {
+			continue
+		}
+		if strings.ContainsAny(name, "/\\:.") || strings.HasPrefix(name, "cmake_") || ninjaUtilityTargets[name] {
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			targets = append(targets, name)
+		}
+	}
+
+	sort.Strings(targets)
+	return targets
+}
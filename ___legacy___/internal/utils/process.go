@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// ProcessTerminateGrace is how long a process tree has to exit after SIGTERM before it is killed
+const ProcessTerminateGrace = 3 * time.Second
+
+// liveProcessTrees holds every started tree that has not been closed yet, so cake can
+// take them down when it quits or is signalled mid-operation
+var liveProcessTrees = struct {
+	sync.Mutex
+	trees map[*ProcessTree]bool
+}{trees: map[*ProcessTree]bool{}}
+
+func trackProcessTree(pt *ProcessTree) {
+	liveProcessTrees.Lock()
+	defer liveProcessTrees.Unlock()
+	liveProcessTrees.trees[pt] = true
+}
+
+func untrackProcessTree(pt *ProcessTree) {
+	liveProcessTrees.Lock()
+	defer liveProcessTrees.Unlock()
+	delete(liveProcessTrees.trees, pt)
+}
+
+// TerminateProcessTrees closes every live process tree and waits until each one is gone,
+// at most ProcessTerminateGrace. Called on quit and on SIGINT, SIGTERM and SIGHUP.
+func TerminateProcessTrees() {
+	liveProcessTrees.Lock()
+	trees := make([]*ProcessTree, 0, len(liveProcessTrees.trees))
+	for pt := range liveProcessTrees.trees {
+		trees = append(trees, pt)
+	}
+	liveProcessTrees.Unlock()
+
+	for _, pt := range trees {
+		pt.Close()
+	}
+	for _, pt := range trees {
+		pt.wait()
+	}
+}
@@ -0,0 +1,91 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// processPollInterval is how often a terminating process group is checked for survivors
+const processPollInterval = 50 * time.Millisecond
+
+// ProcessTree represents a subprocess and its descendants, placed in a process group of
+// their own so that one signal to the group reaches ninja, make and every compiler they
+// started, not just cmake. Closing sends SIGTERM to the group and SIGKILL to whatever is
+// left after ProcessTerminateGrace.
+type ProcessTree struct {
+	pgid      int
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// StartProcessTree starts cmd as the leader of a new process group. Callers MUST call Close
+// on the returned ProcessTree when the subprocess has finished (normally or via abort) so
+// descendants that outlived it are terminated.
+func StartProcessTree(cmd *exec.Cmd) (*ProcessTree, error) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// A new session (PTY builds) already leads its own group; setpgid would fail there
+	if !cmd.SysProcAttr.Setsid {
+		cmd.SysProcAttr.Setpgid = true
+	}
+
+	if startErr := cmd.Start(); startErr != nil {
+		return nil, fmt.Errorf("cmd.Start failed: %w", startErr)
+	}
+
+	pt := &ProcessTree{pgid: cmd.Process.Pid, done: make(chan struct{})}
+	trackProcessTree(pt)
+	return pt, nil
+}
+
+// Close sends SIGTERM to the process group and, in the background, SIGKILL once the
+// grace period has passed with members still running. Returns immediately; safe to call
+// multiple times and from the abort path while the operation's own deferred Close runs.
+func (pt *ProcessTree) Close() {
+	pt.closeOnce.Do(func() {
+		if !pt.alive() {
+			pt.finish()
+			return
+		}
+		// discard: the group may have exited between the check and the signal
+		_ = syscall.Kill(-pt.pgid, syscall.SIGTERM)
+		go pt.escalate()
+	})
+}
+
+// escalate waits for the group to exit after SIGTERM and kills it when the grace period runs out
+func (pt *ProcessTree) escalate() {
+	defer pt.finish()
+	deadline := time.Now().Add(ProcessTerminateGrace)
+	for time.Now().Before(deadline) {
+		time.Sleep(processPollInterval)
+		if !pt.alive() {
+			return
+		}
+	}
+	// discard: best-effort teardown — nothing actionable remains if the group is already gone
+	_ = syscall.Kill(-pt.pgid, syscall.SIGKILL)
+}
+
+// alive reports whether any process of the group still exists
+func (pt *ProcessTree) alive() bool {
+	err := syscall.Kill(-pt.pgid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func (pt *ProcessTree) finish() {
+	untrackProcessTree(pt)
+	close(pt.done)
+}
+
+// wait blocks until Close has finished terminating the group
+func (pt *ProcessTree) wait() {
+	<-pt.done
+}
@@ -73,7 +73,9 @@ func StartProcessTree(cmd *exec.Cmd) (*ProcessTree, error) {
 	// discard: process handle no longer needed — job retains the binding
 	_ = windows.CloseHandle(openedHandle)
 
-	return &ProcessTree{jobHandle: jobHandle}, nil
+	pt := &ProcessTree{jobHandle: jobHandle}
+	trackProcessTree(pt)
+	return pt, nil
 }
 
 // Close releases the job handle. Because the job is configured with
@@ -86,4 +88,8 @@ func (pt *ProcessTree) Close() {
 		_ = windows.CloseHandle(pt.jobHandle)
 		pt.jobHandle = 0
 	}
+	untrackProcessTree(pt)
 }
+
+// wait returns at once: closing the job terminates the tree synchronously
+func (pt *ProcessTree) wait() {}
@@ -0,0 +1,40 @@
+//go:build darwin
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a new pseudo-terminal master and returns it with the slave's device path
+func openPTY() (*os.File, string, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("open /dev/ptmx: %w", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, "", fmt.Errorf("grant pty: %w", err)
+	}
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, "", fmt.Errorf("unlock pty: %w", err)
+	}
+	// TIOCPTYGNAME fills a 128-byte buffer with the slave's path; x/sys has no wrapper for it
+	var name [128]byte
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, "", fmt.Errorf("pty name: %w", errno)
+	}
+	return master, string(name[:bytes.IndexByte(name[:], 0)]), nil
+}
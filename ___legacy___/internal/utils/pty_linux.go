@@ -0,0 +1,33 @@
+//go:build linux
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a new pseudo-terminal master and returns it with the slave's device path
+func openPTY() (*os.File, string, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("open /dev/ptmx: %w", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, "", fmt.Errorf("unlock pty: %w", err)
+	}
+	number, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, "", fmt.Errorf("pty number: %w", err)
+	}
+	return master, "/dev/pts/" + strconv.Itoa(number), nil
+}
@@ -0,0 +1,25 @@
+//go:build !linux && !darwin
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// PTYSupported reports whether StreamCommandPTY can run commands on a pseudo-terminal here
+const PTYSupported = false
+
+func startPTYProcessTree(cmd *exec.Cmd) (*os.File, *ProcessTree, error) {
+	return nil, nil, fmt.Errorf("pseudo-terminals are not supported on %s", runtime.GOOS)
+}
+
+type ptyReader struct {
+	master *os.File
+}
+
+func (r ptyReader) Read(p []byte) (int, error) {
+	return r.master.Read(p)
+}
@@ -0,0 +1,67 @@
+//go:build linux || darwin
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// PTYSupported reports whether StreamCommandPTY can run commands on a pseudo-terminal here
+const PTYSupported = true
+
+// startPTYProcessTree starts cmd as a new session whose controlling terminal, stdin, stdout
+// and stderr are the slave side of a fresh pseudo-terminal, and returns the master to read from
+func startPTYProcessTree(cmd *exec.Cmd) (*os.File, *ProcessTree, error) {
+	master, slaveName, err := openPTY()
+	if err != nil {
+		return nil, nil, err
+	}
+	slave, err := os.OpenFile(slaveName, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("open %s: %w", slaveName, err)
+	}
+	// ninja elides its status line to the terminal width; give it room for long target names
+	// discard: a PTY without a size still works, ninja then assumes 80 columns
+	_ = unix.IoctlSetWinsize(int(slave.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: PTYRows, Col: PTYColumns})
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// Setsid also makes the child a process group leader, so ProcessTree signals the whole tree
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+
+	tree, startErr := StartProcessTree(cmd)
+	// discard: the child holds its own copy; the master sees EOF once every copy is closed
+	_ = slave.Close()
+	if startErr != nil {
+		// discard: rollback cleanup — original error is what the caller sees
+		_ = master.Close()
+		return nil, nil, startErr
+	}
+	return master, tree, nil
+}
+
+// ptyReader turns the EIO Linux returns from a master whose slave has closed into io.EOF
+type ptyReader struct {
+	master *os.File
+}
+
+func (r ptyReader) Read(p []byte) (int, error) {
+	n, err := r.master.Read(p)
+	if errors.Is(err, syscall.EIO) {
+		return n, io.EOF
+	}
+	return n, err
+}
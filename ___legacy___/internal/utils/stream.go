@@ -17,6 +17,16 @@ import (
 // instead of \r-overwriting. CAKE collapses consecutive matches to a single line.
 var ninjaProgressPattern = regexp.MustCompile(`^\[\d+/\d+\]`)
 
+// terminalControlPattern matches the escape sequences a tool writes to a terminal that are not
+// colors: CSI sequences other than SGR ("\x1b[K" clearing ninja's status line) and OSC titles
+var terminalControlPattern = regexp.MustCompile("\x1b\\[[0-?]*[ -/]*[@-ln-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+// Pseudo-terminal size for StreamCommandPTY
+const (
+	PTYRows    = 50
+	PTYColumns = 200
+)
+
 // StreamCommand pipes stdout/stderr from a command to callback functions.
 // Uses byte-by-byte reading to handle \r (progress lines) and \n (complete lines).
 // Waits for pipes to fully drain before returning — callers call cmd.Wait() after.
@@ -65,6 +75,36 @@ func StreamCommand(cmd *exec.Cmd, appendCallback func(string, ui.OutputLineType)
 	return tree, nil
 }
 
+// StreamCommandPTY is StreamCommand with the child on a pseudo-terminal: ninja and the
+// compilers see a terminal, so they keep their own \r progress line and colors, and stdout
+// and stderr arrive as one stream in their true order (reported as TypeStdout). Colors
+// (SGR sequences) are kept in the lines; other terminal control sequences are dropped.
+// Callers check PTYSupported first.
+func StreamCommandPTY(cmd *exec.Cmd, appendCallback func(string, ui.OutputLineType), replaceCallback func(string, ui.OutputLineType), onProcessTreeStarted func(*ProcessTree)) (*ProcessTree, error) {
+	master, tree, startErr := startPTYProcessTree(cmd)
+	if startErr != nil {
+		return nil, fmt.Errorf("StreamCommandPTY: %w", startErr)
+	}
+	defer master.Close()
+
+	if onProcessTreeStarted != nil {
+		onProcessTreeStarted(tree)
+	}
+
+	streamPipe(bufio.NewReader(ptyReader{master: master}), ui.TypeStdout, stripTerminalControl(appendCallback), stripTerminalControl(replaceCallback))
+	return tree, nil
+}
+
+// stripTerminalControl wraps callback to drop non-color escape sequences, and lines left empty by it
+func stripTerminalControl(callback func(string, ui.OutputLineType)) func(string, ui.OutputLineType) {
+	return func(line string, lineType ui.OutputLineType) {
+		line = strings.TrimSpace(terminalControlPattern.ReplaceAllString(line, ""))
+		if line != "" {
+			callback(line, lineType)
+		}
+	}
+}
+
 // streamPipe reads byte-by-byte from a reader, handling \n and \r line terminators.
 // Tracks isProgressLine state per TIT pattern:
 //   - First \r on a line: append normally, mark as progress
@@ -0,0 +1,959 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrengmusic/cake/internal/ui"
+)
+
+// --- ParseCMakeGenerators ---
+
+func TestParseCMakeGenerators(t *testing.T) {
+	data := []byte(`{
+		"version": {"major": 3, "minor": 28},
+		"generators": [
+			{"name": "Ninja", "extraGenerators": ["CodeBlocks", "Kate"], "platformSupport": false, "toolsetSupport": false},
+			{"name": "Unix Makefiles", "extraGenerators": ["CodeBlocks"], "platformSupport": false, "toolsetSupport": false},
+			{"name": "Ninja Multi-Config", "extraGenerators": [], "platformSupport": false, "toolsetSupport": false},
+			{"name": "Visual Studio 17 2022", "platformSupport": true, "toolsetSupport": true}
+		],
+		"serverMode": false
+	}`)
+
+	want := []string{
+		"Ninja",
+		"CodeBlocks - Ninja",
+		"Kate - Ninja",
+		"Unix Makefiles",
+		"CodeBlocks - Unix Makefiles",
+		"Ninja Multi-Config",
+		"Visual Studio 17 2022",
+	}
+
+	got, err := ParseCMakeGenerators(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("index %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseCMakeGenerators_InvalidJSON(t *testing.T) {
+	if _, err := ParseCMakeGenerators([]byte("cmake: unknown option")); err == nil {
+		t.Error("expected error for non-JSON output")
+	}
+}
+
+// --- GetDirectoryName / GetGeneratorNameFromDirectory ---
+
+func TestGetDirectoryName(t *testing.T) {
+	tests := []struct {
+		generator string
+		want      string
+	}{
+		{GeneratorXcode, "Xcode"},
+		{GeneratorNinja, "Ninja"},
+		{GeneratorNinjaMultiConfig, "NinjaMultiConfig"},
+		{GeneratorUnixMakefiles, "UnixMakefiles"},
+		{GeneratorNMakeJOM, "NMakeMakefilesJOM"},
+		{"CodeBlocks - Ninja", "CodeBlocksNinja"},
+		{"Eclipse CDT4 - Unix Makefiles", "EclipseCDT4UnixMakefiles"},
+		{GeneratorVS2026, "VS2026"},
+		{GeneratorVS2022, "VS2022"},
+		{"Visual Studio 16 2019", "VS2019"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.generator, func(t *testing.T) {
+			if got := GetDirectoryName(tc.generator); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetGeneratorNameFromDirectory_RoundTrip(t *testing.T) {
+	generators := append(ValidGenerators(),
+		"CodeBlocks - Ninja",
+		"Kate - Ninja Multi-Config",
+		"Sublime Text 2 - Unix Makefiles",
+		"Visual Studio 16 2019",
+	)
+
+	for _, gen := range generators {
+		t.Run(gen, func(t *testing.T) {
+			if got := GetGeneratorNameFromDirectory(GetDirectoryName(gen)); got != gen {
+				t.Errorf("round trip: got %q, want %q", got, gen)
+			}
+		})
+	}
+}
+
+func TestGetGeneratorNameFromDirectory_Unknown(t *testing.T) {
+	if got := GetGeneratorNameFromDirectory("SomethingElse"); got != "SomethingElse" {
+		t.Errorf("got %q, want directory name unchanged", got)
+	}
+}
+
+// --- GetBuildDirName / ParseBuildDirName ---
+
+func TestGetBuildDirName(t *testing.T) {
+	tests := []struct {
+		generator string
+		config    string
+		variant   string
+		want      string
+	}{
+		{GeneratorNinja, "Debug", VariantDefault, "Ninja-Debug"},
+		{GeneratorUnixMakefiles, "Release", VariantDefault, "UnixMakefiles-Release"},
+		{"CodeBlocks - Ninja", "Debug", VariantDefault, "CodeBlocksNinja-Debug"},
+		{GeneratorNinjaMultiConfig, "Debug", VariantDefault, "NinjaMultiConfig"},
+		{GeneratorXcode, "Release", VariantDefault, "Xcode"},
+		{GeneratorVS2022, "Debug", VariantDefault, "VS2022"},
+		{GeneratorNinja, "", VariantDefault, "Ninja"},
+		{GeneratorNinja, "Debug", "", "Ninja-Debug"},
+		{GeneratorNinja, "Debug", VariantASan, "Ninja-Debug-asan"},
+		{GeneratorXcode, "Release", VariantCoverage, "Xcode-coverage"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			if got := GetBuildDirName(tc.generator, tc.config, tc.variant); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseBuildDirName_RoundTrip(t *testing.T) {
+	tests := []struct {
+		generator string
+		config    string
+		variant   string
+	}{
+		{GeneratorNinja, "Debug", VariantDefault},
+		{GeneratorUnixMakefiles, "Release", VariantDefault},
+		{"Kate - Ninja", "Debug", VariantDefault},
+		{GeneratorNinjaMultiConfig, "", VariantDefault},
+		{GeneratorVS2026, "", VariantDefault},
+		{GeneratorNinja, "Debug", VariantTSan},
+		{GeneratorVS2022, "", VariantASan},
+		{GeneratorNinja, "", VariantUBSan},
+	}
+
+	for _, tc := range tests {
+		dirName := GetBuildDirName(tc.generator, tc.config, tc.variant)
+		t.Run(dirName, func(t *testing.T) {
+			gen, config, variant := ParseBuildDirName(dirName)
+			if gen != tc.generator || config != tc.config || variant != tc.variant {
+				t.Errorf("got (%q, %q, %q), want (%q, %q, %q)", gen, config, variant, tc.generator, tc.config, tc.variant)
+			}
+		})
+	}
+}
+
+// --- Variants ---
+
+func TestVariantDefinitions(t *testing.T) {
+	if got := VariantDefinitions(GeneratorNinja, VariantDefault); len(got) != 0 {
+		t.Errorf("default variant must add no definitions, got %v", got)
+	}
+
+	asan := strings.Join(VariantDefinitions(GeneratorNinja, VariantASan), "\n")
+	for _, want := range []string{
+		"CMAKE_CXX_FLAGS_INIT:STRING=-fsanitize=address -fno-omit-frame-pointer",
+		"CMAKE_EXE_LINKER_FLAGS_INIT:STRING=-fsanitize=address",
+		"CMAKE_SHARED_LINKER_FLAGS_INIT:STRING=-fsanitize=address",
+	} {
+		if !strings.Contains(asan, want) {
+			t.Errorf("asan definitions missing %q:\n%s", want, asan)
+		}
+	}
+
+	msvc := VariantDefinitions(GeneratorVS2022, VariantASan)
+	if len(msvc) != 2 || msvc[1] != "CMAKE_CXX_FLAGS_INIT:STRING=/fsanitize=address" {
+		t.Errorf("unexpected MSVC asan definitions %v", msvc)
+	}
+
+	if IsVariantSupported(GeneratorVS2022, VariantTSan) || !IsVariantSupported(GeneratorXcode, VariantTSan) {
+		t.Error("tsan must be supported everywhere but Visual Studio")
+	}
+}
+
+// --- IsGeneratorMultiConfig ---
+
+func TestIsGeneratorMultiConfig(t *testing.T) {
+	tests := []struct {
+		generator string
+		want      bool
+	}{
+		{GeneratorXcode, true},
+		{GeneratorVS2022, true},
+		{GeneratorNinjaMultiConfig, true},
+		{"Kate - Ninja Multi-Config", true},
+		{GeneratorNinja, false},
+		{GeneratorUnixMakefiles, false},
+		{"CodeBlocks - Ninja", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.generator, func(t *testing.T) {
+			if got := IsGeneratorMultiConfig(tc.generator); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// --- GetBuildTool ---
+
+func TestGetBuildTool(t *testing.T) {
+	tests := []struct {
+		generator string
+		want      string
+	}{
+		{GeneratorNinja, "ninja"},
+		{GeneratorNinjaMultiConfig, "ninja"},
+		{GeneratorUnixMakefiles, "make"},
+		{GeneratorMinGWMakefiles, "mingw32-make"},
+		{GeneratorXcode, "xcodebuild"},
+		{"CodeBlocks - Ninja", "ninja"},
+		{"CodeLite - Unix Makefiles", "make"},
+		{GeneratorVS2022, ""},
+		{"Green Hills MULTI", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.generator, func(t *testing.T) {
+			if got := GetBuildTool(tc.generator); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// --- IsGeneratorIDE ---
+
+func TestIsGeneratorIDE(t *testing.T) {
+	tests := []struct {
+		generator string
+		want      bool
+	}{
+		{GeneratorXcode, true},
+		{GeneratorVS2026, true},
+		{"Visual Studio 16 2019", true},
+		{GeneratorNinja, false},
+		{"CodeBlocks - Ninja", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.generator, func(t *testing.T) {
+			if got := IsGeneratorIDE(tc.generator); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// --- WriteFileAPIQuery ---
+
+func TestWriteFileAPIQuery(t *testing.T) {
+	buildDir := filepath.Join(t.TempDir(), "Ninja-Debug") // need not exist yet
+	if err := WriteFileAPIQuery(buildDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(buildDir, ".cmake", "api", "v1", "query", FileAPIClientName, FileAPIQueryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var query fileAPIQuery
+	if err := json.Unmarshal(data, &query); err != nil {
+		t.Fatalf("query is not valid JSON: %v", err)
+	}
+
+	want := map[string]int{FileAPIKindCodemodel: 2, FileAPIKindCache: 2, FileAPIKindToolchains: 1}
+	if len(query.Requests) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(query.Requests), len(want))
+	}
+	for _, request := range query.Requests {
+		if want[request.Kind] != request.Version {
+			t.Errorf("%s: got version %d, want %d", request.Kind, request.Version, want[request.Kind])
+		}
+	}
+}
+
+// --- LinkCompileCommands ---
+
+func TestLinkCompileCommands(t *testing.T) {
+	root := t.TempDir()
+	debugDir := filepath.Join(root, "Builds", "Ninja-Debug")
+	releaseDir := filepath.Join(root, "Builds", "Ninja-Release")
+	for _, dir := range []string{debugDir, releaseDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "compile_commands.json"), []byte(`["`+filepath.Base(dir)+`"]`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rootFile := filepath.Join(root, "compile_commands.json")
+
+	for _, dir := range []string{debugDir, releaseDir} {
+		linked, err := LinkCompileCommands(root, dir)
+		if err != nil || !linked {
+			t.Fatalf("%s: linked=%v err=%v", dir, linked, err)
+		}
+		data, err := os.ReadFile(rootFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), filepath.Base(dir)) {
+			t.Errorf("root compile_commands.json should follow %s, got %s", dir, data)
+		}
+	}
+
+	// A tree without a database (e.g. Xcode) drops the link to the previous build
+	linked, err := LinkCompileCommands(root, filepath.Join(root, "Builds", "Xcode"))
+	if err != nil || linked {
+		t.Errorf("expected no link without a database, got linked=%v err=%v", linked, err)
+	}
+	if _, err := os.Lstat(rootFile); !os.IsNotExist(err) {
+		t.Errorf("stale link must be removed, got err=%v", err)
+	}
+}
+
+func TestLinkCompileCommands_KeepsForeignFile(t *testing.T) {
+	root := t.TempDir()
+	buildDir := filepath.Join(root, "Builds", "Ninja-Debug")
+	if err := os.MkdirAll(buildDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(buildDir, "compile_commands.json"), []byte(`["build"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rootFile := filepath.Join(root, "compile_commands.json")
+	if err := os.WriteFile(rootFile, []byte(`["committed"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if linked, err := LinkCompileCommands(root, buildDir); err == nil || linked {
+		t.Errorf("expected an error for a file cake did not write, got linked=%v err=%v", linked, err)
+	}
+	if err := UnlinkCompileCommands(root); err != nil {
+		t.Errorf("UnlinkCompileCommands: %v", err)
+	}
+	if data, _ := os.ReadFile(rootFile); string(data) != `["committed"]` {
+		t.Errorf("foreign file must be left alone, got %s", data)
+	}
+}
+
+func TestOwnsCompileCommands_Copy(t *testing.T) {
+	root := t.TempDir()
+	rootFile := filepath.Join(root, "compile_commands.json")
+	marker := filepath.Join(root, "Builds", compileCommandsCopyMarker)
+	if err := os.WriteFile(rootFile, []byte(`["copy"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ownsCompileCommands(rootFile, marker) {
+		t.Error("a file without a marker must not be cake's")
+	}
+
+	if err := recordCompileCommandsCopy(rootFile, marker); err != nil {
+		t.Fatal(err)
+	}
+	if !ownsCompileCommands(rootFile, marker) {
+		t.Error("the recorded copy must be cake's")
+	}
+
+	// Edited by hand since cake copied it: no longer cake's to replace
+	if err := os.WriteFile(rootFile, []byte(`["edited"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ownsCompileCommands(rootFile, marker) {
+		t.Error("an edited copy must not be cake's")
+	}
+}
+
+// --- ParseNinjaTargets ---
+
+func TestParseNinjaTargets(t *testing.T) {
+	data := []byte(`cmake_object_order_depends_target_app: phony
+CMakeFiles/app.dir/main.cpp.o: CXX_COMPILER__app_Debug
+app: CXX_EXECUTABLE_LINKER__app_Debug
+libcore.a: CXX_STATIC_LIBRARY_LINKER__core_Debug
+core: phony
+app:Debug: phony
+edit_cache: phony
+rebuild_cache: phony
+sub/all: phony
+all: phony
+clean: phony
+help: phony
+build.ninja: RERUN_CMAKE
+docs: phony
+core: phony
+`)
+
+	got := ParseNinjaTargets(data)
+	want := []string{"app", "core", "docs"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d]: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// --- ParseCTestLine ---
+
+func TestParseCTestLine(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantOK     bool
+		wantNumber int
+		wantName   string
+		wantStatus string
+		wantDetail string
+		wantFailed bool
+		wantMillis int64
+	}{
+		{"1/6 Test #1: unit_tests .......................   Passed    0.01 sec", true, 1, "unit_tests", TestStatusPassed, "", false, 10},
+		{"2/6 Test #2: regex_check ......................***Failed  Required regular expression not found. Regex=[ok]  0.50 sec", true, 2, "regex_check", TestStatusFailed, "Required regular expression not found. Regex=[ok]", true, 500},
+		{"3/6 Test #3: slow ............................***Timeout  10.00 sec", true, 3, "slow", TestStatusTimeout, "", true, 10000},
+		{"4/6 Test #4: crash ...........................***Exception: SegFault  0.02 sec", true, 4, "crash", TestStatusFailed, "Exception: SegFault", true, 20},
+		{"5/6 Test #5: off .............................***Not Run (Disabled)   0.00 sec", true, 5, "off", TestStatusNotRun, "Disabled", false, 0},
+		{"6/6 Test #6: missing .........................***Not Run   0.00 sec", true, 6, "missing", TestStatusNotRun, "", true, 0},
+		{"100% tests passed, 0 tests failed out of 6", false, 0, "", "", "", false, 0},
+		{"    Start 1: unit_tests", false, 0, "", "", "", false, 0},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseCTestLine(tt.line)
+		if ok != tt.wantOK {
+			t.Errorf("ParseCTestLine(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got.Number != tt.wantNumber || got.Name != tt.wantName || got.Status != tt.wantStatus || got.Detail != tt.wantDetail {
+			t.Errorf("ParseCTestLine(%q) = %+v", tt.line, got)
+		}
+		if got.Failed() != tt.wantFailed {
+			t.Errorf("ParseCTestLine(%q).Failed() = %v, want %v", tt.line, got.Failed(), tt.wantFailed)
+		}
+		if got.Duration.Milliseconds() != tt.wantMillis {
+			t.Errorf("ParseCTestLine(%q) duration = %v, want %dms", tt.line, got.Duration, tt.wantMillis)
+		}
+	}
+}
+
+// --- MergeEnv ---
+
+func TestMergeEnv(t *testing.T) {
+	base := []string{"PATH=/usr/bin", "CC=gcc", "HOME=/home/me"}
+
+	if got := MergeEnv(base, nil); len(got) != len(base) {
+		t.Errorf("expected base unchanged without overrides, got %v", got)
+	}
+
+	got := MergeEnv(base, []string{"CC=clang", "CXX=clang++"})
+	want := []string{"PATH=/usr/bin", "HOME=/home/me", "CC=clang", "CXX=clang++"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("MergeEnv = %v, want %v", got, want)
+	}
+	if base[1] != "CC=gcc" {
+		t.Error("MergeEnv must not modify base")
+	}
+
+	// Empty base: overrides apply on top of the process environment
+	t.Setenv("CAKE_MERGE_ENV_TEST", "before")
+	merged := strings.Join(MergeEnv(nil, []string{"CAKE_MERGE_ENV_TEST=after"}), "\n")
+	if !strings.Contains(merged, "CAKE_MERGE_ENV_TEST=after") || strings.Contains(merged, "CAKE_MERGE_ENV_TEST=before") {
+		t.Error("expected the override to replace the process environment value")
+	}
+}
+
+// --- SplitArgs ---
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"--verbose  -n 3", []string{"--verbose", "-n", "3"}},
+		{`--name "two words" 'it''s'`, []string{"--name", "two words", "its"}},
+		{`a\ b c\"d`, []string{"a b", `c"d`}},
+		{`""`, []string{""}},
+		{`--in C:\work\data\in.wav`, []string{"--in", `C:\work\data\in.wav`}},
+		{`\\server\share\dir\`, []string{`\\server\share\dir\`}},
+		{`"C:\Program Files\Microsoft VS Code\bin\code.cmd" --goto`, []string{`C:\Program Files\Microsoft VS Code\bin\code.cmd`, "--goto"}},
+		{`"say \"hi\""`, []string{`say "hi"`}},
+	}
+	for _, tt := range tests {
+		got, err := SplitArgs(tt.line)
+		if err != nil {
+			t.Errorf("SplitArgs(%q): unexpected error %v", tt.line, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+		if roundTrip, _ := SplitArgs(JoinArgs(got)); strings.Join(roundTrip, "|") != strings.Join(got, "|") {
+			t.Errorf("JoinArgs(%q) = %q does not split back", got, JoinArgs(got))
+		}
+	}
+
+	if _, err := SplitArgs(`--name "open`); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+// --- DebuggerArgs ---
+
+func TestDebuggerArgs(t *testing.T) {
+	tests := []struct {
+		debugger string
+		want     []string
+	}{
+		{"gdb", []string{"-ex", "break main", "--args", "/b/app", "-v"}},
+		{"/usr/bin/gdb-multiarch", []string{"-ex", "break main", "--args", "/b/app", "-v"}},
+		{"lldb", []string{"-o", "break main", "--", "/b/app", "-v"}},
+		{"/opt/llvm/bin/lldb-18", []string{"-o", "break main", "--", "/b/app", "-v"}},
+	}
+	for _, tt := range tests {
+		got := DebuggerArgs(tt.debugger, []string{"break main"}, "/b/app", []string{"-v"})
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("DebuggerArgs(%s) = %q, want %q", tt.debugger, got, tt.want)
+		}
+	}
+}
+
+func TestIsWatchIgnored(t *testing.T) {
+	ignore := []string{"*.md", "docs/", "third_party/*"}
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"Builds", true, true},
+		{".git", true, true},
+		{"src/.hidden.cpp", false, true},
+		{"README.md", false, true},
+		{"src/notes.md", false, true},
+		{"docs", true, true},
+		{"docs", false, false},
+		{"third_party/juce", true, true},
+		{"src/third_party/juce", true, false},
+		{"src/main.cpp", false, false},
+		{"CMakeLists.txt", false, false},
+	}
+	for _, tt := range tests {
+		if got := IsWatchIgnored(tt.rel, tt.isDir, ignore); got != tt.want {
+			t.Errorf("IsWatchIgnored(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestSourceFingerprint(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fingerprint := func() uint64 {
+		t.Helper()
+		value, err := SourceFingerprint(root, []string{"*.md"}, []string{filepath.Join(root, "build")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	write("CMakeLists.txt", "project(demo)")
+	write("src/main.cpp", "int main() {}")
+	initial := fingerprint()
+
+	write("Builds/Ninja/Debug/demo.o", "object")
+	write("NOTES.md", "todo")
+	if got := fingerprint(); got != initial {
+		t.Error("changes under Builds/ or to ignored files must not change the fingerprint")
+	}
+
+	// A preset with binaryDir ${sourceDir}/build builds inside the sources
+	write("build/CMakeFiles/demo.dir/main.cpp.o", "object")
+	if got := fingerprint(); got != initial {
+		t.Error("changes in an in-source build tree must not change the fingerprint")
+	}
+
+	write("src/main.cpp", "int main() { return 1; }")
+	if got := fingerprint(); got == initial {
+		t.Error("an edited source must change the fingerprint")
+	}
+}
+
+func TestParseDiagnosticLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Diagnostic
+	}{
+		{
+			"/src/demo/main.cpp:12:5: error: 'foo' was not declared in this scope",
+			Diagnostic{File: "/src/demo/main.cpp", Line: 12, Column: 5, Severity: SeverityError, Message: "'foo' was not declared in this scope"},
+		},
+		{
+			"\x1b[1mutil.h:3:10: \x1b[0;1;35mwarning: \x1b[0munused parameter 'x' [-Wunused-parameter]",
+			Diagnostic{File: "util.h", Line: 3, Column: 10, Severity: SeverityWarning, Message: "unused parameter 'x' [-Wunused-parameter]"},
+		},
+		{
+			"main.cpp:1:10: fatal error: 'missing.h' file not found",
+			Diagnostic{File: "main.cpp", Line: 1, Column: 10, Severity: SeverityError, Message: "'missing.h' file not found"},
+		},
+		{
+			"C:\\src\\main.cpp(12,5): error C2065: 'foo': undeclared identifier",
+			Diagnostic{File: "C:\\src\\main.cpp", Line: 12, Column: 5, Severity: SeverityError, Code: "C2065", Message: "'foo': undeclared identifier"},
+		},
+		{
+			"C:\\src\\main.cpp(40): warning C4996: 'strcpy': This function may be unsafe.",
+			Diagnostic{File: "C:\\src\\main.cpp", Line: 40, Severity: SeverityWarning, Code: "C4996", Message: "'strcpy': This function may be unsafe."},
+		},
+		{
+			"main.obj : error LNK2019: unresolved external symbol bar referenced in function main",
+			Diagnostic{Tool: "main.obj", Severity: SeverityError, Code: "LNK2019", Message: "unresolved external symbol bar referenced in function main"},
+		},
+		{
+			"/usr/bin/ld: main.cpp:(.text+0x1e): undefined reference to `bar()'",
+			Diagnostic{File: "main.cpp", Tool: "ld", Severity: SeverityError, Message: "undefined reference to `bar()'"},
+		},
+		{
+			"ld.lld: error: undefined symbol: bar()",
+			Diagnostic{Tool: "ld.lld", Severity: SeverityError, Message: "undefined symbol: bar()"},
+		},
+		{
+			"collect2: error: ld returned 1 exit status",
+			Diagnostic{Tool: "collect2", Severity: SeverityError, Message: "ld returned 1 exit status"},
+		},
+		{
+			"main.cpp:8:6: note: declared here",
+			Diagnostic{File: "main.cpp", Line: 8, Column: 6, Severity: SeverityNote, Message: "declared here"},
+		},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDiagnosticLine(tt.line)
+		if !ok {
+			t.Errorf("ParseDiagnosticLine(%q): not parsed", tt.line)
+			continue
+		}
+		if got.File != tt.want.File || got.Line != tt.want.Line || got.Column != tt.want.Column ||
+			got.Severity != tt.want.Severity || got.Code != tt.want.Code || got.Tool != tt.want.Tool || got.Message != tt.want.Message {
+			t.Errorf("ParseDiagnosticLine(%q)\n got %+v\nwant %+v", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{
+		"[2/5] Building CXX object CMakeFiles/demo.dir/main.cpp.o",
+		"FAILED: CMakeFiles/demo.dir/main.cpp.o",
+		"ninja: build stopped: subcommand failed.",
+		"In file included from main.cpp:1:",
+		"",
+	} {
+		if _, ok := ParseDiagnosticLine(line); ok {
+			t.Errorf("ParseDiagnosticLine(%q): expected no diagnostic", line)
+		}
+	}
+}
+
+func TestDiagnosticCollector(t *testing.T) {
+	collector := NewDiagnosticCollector()
+	for _, line := range []string{
+		"[1/3] Building CXX object a.o",
+		"shared.h:4:7: warning: unused variable 'unused' [-Wunused-variable]",
+		"main.cpp:12:5: error: no matching function for call to 'f'",
+		"main.cpp:3:6: note: candidate function not viable",
+		"[2/3] Building CXX object b.o",
+		"shared.h:4:7: warning: unused variable 'unused' [-Wunused-variable]",
+		"shared.h:2:1: note: in expansion of macro",
+	} {
+		collector.Add(line)
+	}
+
+	diagnostics := collector.Diagnostics()
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected the repeated header warning once, got %d diagnostics: %+v", len(diagnostics), diagnostics)
+	}
+	if len(diagnostics[0].Notes) != 0 {
+		t.Errorf("a repeated diagnostic's notes must not attach to it again, got %v", diagnostics[0].Notes)
+	}
+	if len(diagnostics[1].Notes) != 1 || diagnostics[1].Notes[0] != "main.cpp:3:6: candidate function not viable" {
+		t.Errorf("expected the note on the error, got %v", diagnostics[1].Notes)
+	}
+	if errors, warnings := CountDiagnostics(diagnostics); errors != 1 || warnings != 1 {
+		t.Errorf("CountDiagnostics: got %d errors, %d warnings", errors, warnings)
+	}
+}
+
+func TestEditorGotoArgs(t *testing.T) {
+	tests := []struct {
+		editor    string
+		templates map[string]string
+		want      string
+	}{
+		{"/usr/bin/nvim", nil, "+12 /src/my file.cpp"},
+		{"emacsclient", nil, "+12:5 /src/my file.cpp"},
+		{"code.cmd", nil, "--goto /src/my file.cpp:12:5"},
+		{"hx", nil, "/src/my file.cpp:12:5"},
+		{"myedit", nil, "/src/my file.cpp"},
+		{"myedit", map[string]string{"myedit": "-l {line} -c {column} {file}"}, "-l 12 -c 5 /src/my file.cpp"},
+		{"nvim", map[string]string{"nvim": "{file} +{line}"}, "/src/my file.cpp +12"},
+	}
+	for _, tt := range tests {
+		args, err := EditorGotoArgs(tt.editor, tt.templates, "/src/my file.cpp", 12, 5)
+		if err != nil {
+			t.Errorf("EditorGotoArgs(%q): %v", tt.editor, err)
+			continue
+		}
+		if got := strings.Join(args, " "); got != tt.want {
+			t.Errorf("EditorGotoArgs(%q) = %q, want %q", tt.editor, got, tt.want)
+		}
+		if tt.want == "/src/my file.cpp" && len(args) != 1 {
+			t.Errorf("a path with spaces must stay one argument, got %q", args)
+		}
+	}
+}
+
+func TestResolveEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := ResolveEditor(""); got != DefaultEditor {
+		t.Errorf("no editor set: got %q, want %q", got, DefaultEditor)
+	}
+	t.Setenv("EDITOR", "vim")
+	if got := ResolveEditor(""); got != "vim" {
+		t.Errorf("$EDITOR: got %q, want vim", got)
+	}
+	t.Setenv("VISUAL", "code --wait")
+	if got := ResolveEditor(""); got != "code --wait" {
+		t.Errorf("$VISUAL before $EDITOR: got %q", got)
+	}
+	if got := ResolveEditor("hx"); got != "hx" {
+		t.Errorf("configured editor first: got %q, want hx", got)
+	}
+}
+
+func TestEditorOpenArgs(t *testing.T) {
+	if args, _ := EditorOpenArgs("", "/src/my project"); len(args) != 1 || args[0] != "/src/my project" {
+		t.Errorf("default template: got %q", args)
+	}
+	args, err := EditorOpenArgs("--new-window {dir}", "/src/my project")
+	if err != nil || strings.Join(args, "|") != "--new-window|/src/my project" {
+		t.Errorf("got %q, %v", args, err)
+	}
+	// Windows paths in a template keep their backslashes
+	args, err = EditorOpenArgs(`--user-data-dir C:\Users\me\code-data {dir}`, `C:\work\app`)
+	if err != nil || strings.Join(args, "|") != `--user-data-dir|C:\Users\me\code-data|C:\work\app` {
+		t.Errorf("Windows paths: got %q, %v", args, err)
+	}
+	if !IsGUIEditor("/usr/local/bin/code") || !IsGUIEditor("clion.cmd") || IsGUIEditor("nvim") {
+		t.Error("IsGUIEditor: code and clion are GUI editors, nvim is not")
+	}
+}
+
+func TestWriteQuickfix(t *testing.T) {
+	root := t.TempDir()
+	buildDir := filepath.Join(root, "Builds", "Ninja")
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "main.cpp"), []byte("int main() {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics := []Diagnostic{
+		{File: "../../src/main.cpp", Line: 12, Column: 5, Severity: SeverityError, Message: "'foo' was not declared"},
+		{File: "src/main.cpp", Line: 3, Severity: SeverityWarning, Code: "C4996", Message: "'strcpy' is unsafe"},
+		{Tool: "collect2", Severity: SeverityError, Message: "ld returned 1 exit status"},
+	}
+	path := filepath.Join(buildDir, "errors.err")
+	if err := WriteQuickfix(path, diagnostics, buildDir, root); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainPath := filepath.Join(root, "src", "main.cpp")
+	want := mainPath + ":12:5: error: 'foo' was not declared\n" +
+		mainPath + ":3:1: warning: C4996: 'strcpy' is unsafe\n" +
+		"collect2: error: ld returned 1 exit status\n"
+	if string(data) != want {
+		t.Errorf("quickfix file:\n got %q\nwant %q", data, want)
+	}
+}
+
+// --- ProcessTree ---
+
+func TestTerminateProcessTrees_KillsGrandchildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are Unix; Windows uses a Job Object")
+	}
+	// The background sleep inherits stdout: the pipe only reaches EOF once the grandchild is gone too
+	cmd := exec.Command("sh", "-c", "sleep 30 & echo started; wait")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StartProcessTree(cmd); err != nil {
+		t.Fatal(err)
+	}
+	started := make([]byte, len("started"))
+	if _, err := io.ReadFull(stdout, started); err != nil {
+		t.Fatal(err)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		// discard: only EOF matters
+		_, _ = io.Copy(io.Discard, stdout)
+		_ = cmd.Wait()
+		close(drained)
+	}()
+
+	TerminateProcessTrees()
+	select {
+	case <-drained:
+	case <-time.After(ProcessTerminateGrace + time.Second):
+		t.Fatal("sleep survived TerminateProcessTrees")
+	}
+}
+
+func TestStreamCommandPTY_MergesStreamsAndKeepsColors(t *testing.T) {
+	if !PTYSupported {
+		t.Skip("no pseudo-terminal on " + runtime.GOOS)
+	}
+	cmd := exec.Command("sh", "-c", `[ -t 1 ] && echo tty; echo out; echo err >&2; printf '\033[31mred\033[0m\033[K\n'`)
+	var lines []string
+	collect := func(line string, _ ui.OutputLineType) { lines = append(lines, line) }
+	tree, err := StreamCommandPTY(cmd, collect, collect, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tree.Close()
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"tty", "out", "err", "\x1b[31mred\x1b[0m"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+}
+
+// --- Operation logs ---
+
+func TestRotateLogs(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"20260101-000000-build.log", "20260102-000000-build.log", "20260103-000000-test.log", "20260104-000000-build.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, 100*(i+1)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// By count: the two oldest go
+	if err := RotateLogs(dir, 2, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	assertLogs := func(want ...string) {
+		t.Helper()
+		entries, _ := os.ReadDir(dir)
+		var got []string
+		for _, entry := range entries {
+			got = append(got, entry.Name())
+		}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	assertLogs("20260103-000000-test.log", "20260104-000000-build.log", "notes.txt")
+
+	// By size: 300 + 400 bytes exceed 500, so the older one goes
+	if err := RotateLogs(dir, 10, 500); err != nil {
+		t.Fatal(err)
+	}
+	assertLogs("20260104-000000-build.log", "notes.txt")
+}
+
+func TestCreateOperationLog(t *testing.T) {
+	buildDir := t.TempDir()
+	now := time.Date(2026, 10, 17, 14, 23, 5, 0, time.UTC)
+	file, err := CreateOperationLog(buildDir, "build", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	want := filepath.Join(buildDir, ".cake", "logs", "20261017-142305-build.log")
+	if file.Name() != want {
+		t.Errorf("got %s, want %s", file.Name(), want)
+	}
+	data, _ := os.ReadFile(want)
+	if string(data) != "# cake build, 2026-10-17 14:23:05\n" {
+		t.Errorf("header: got %q", data)
+	}
+}
+
+func TestStartDetached_ReapsTheChild(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("process states are read from Linux /proc")
+	}
+	cmd := exec.Command("true")
+	if err := StartDetached(cmd); err != nil {
+		t.Fatal(err)
+	}
+	pid := cmd.Process.Pid
+	if pid <= 0 {
+		t.Fatalf("child was released (pid %d): nothing is left to reap it", pid)
+	}
+	stat := filepath.Join("/proc", strconv.Itoa(pid), "stat")
+
+	// A zombie keeps its /proc entry in state Z; only a reaped child is gone
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(stat); os.IsNotExist(err) {
+			return
+		}
+		if time.Now().After(deadline) {
+			data, _ := os.ReadFile(stat)
+			t.Fatalf("detached child was never reaped: %s", data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
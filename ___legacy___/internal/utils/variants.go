@@ -0,0 +1,99 @@
+package utils
+
+import "strings"
+
+// Build variant constants - SSOT for all variant references.
+// Every variant but the default gets its own build tree (Builds/Ninja-Debug-asan).
+const (
+	VariantDefault  = "default"
+	VariantASan     = "asan"
+	VariantUBSan    = "ubsan"
+	VariantTSan     = "tsan"
+	VariantCoverage = "coverage"
+)
+
+// variantOrder is the Variant row cycle order
+var variantOrder = []string{VariantDefault, VariantASan, VariantUBSan, VariantTSan, VariantCoverage}
+
+// variantFlags are the compile and link flags a variant adds
+type variantFlags struct {
+	compile string
+	link    string
+}
+
+// gnuVariantFlags apply to GCC and Clang (every generator but Visual Studio)
+var gnuVariantFlags = map[string]variantFlags{
+	VariantASan:     {compile: "-fsanitize=address -fno-omit-frame-pointer", link: "-fsanitize=address"},
+	VariantUBSan:    {compile: "-fsanitize=undefined -fno-omit-frame-pointer", link: "-fsanitize=undefined"},
+	VariantTSan:     {compile: "-fsanitize=thread", link: "-fsanitize=thread"},
+	VariantCoverage: {compile: "--coverage", link: "--coverage"},
+}
+
+// msvcVariantFlags apply to Visual Studio generators; MSVC only ships AddressSanitizer
+var msvcVariantFlags = map[string]variantFlags{
+	VariantASan: {compile: "/fsanitize=address"},
+}
+
+// Variants returns all variant names in cycle order
+func Variants() []string {
+	return append([]string{}, variantOrder...)
+}
+
+// IsVariant returns true for a known variant name
+func IsVariant(name string) bool {
+	for _, variant := range variantOrder {
+		if variant == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVariantSupported returns true if generator's toolchain can build variant
+func IsVariantSupported(generator, variant string) bool {
+	if variant == "" || variant == VariantDefault {
+		return true
+	}
+	_, ok := variantFlagsFor(generator)[variant]
+	return ok
+}
+
+func variantFlagsFor(generator string) map[string]variantFlags {
+	if IsGeneratorVS(generator) {
+		return msvcVariantFlags
+	}
+	return gnuVariantFlags
+}
+
+// VariantDefinitions returns the -D bodies that apply variant's flags at configure time.
+// They seed CMAKE_<LANG>_FLAGS_INIT and the linker _INIT flags, so the compiler's own
+// defaults (e.g. MSVC's /EHsc) are kept. Empty for the default or an unsupported variant.
+func VariantDefinitions(generator, variant string) []string {
+	flags, ok := variantFlagsFor(generator)[variant]
+	if !ok {
+		return nil
+	}
+
+	var definitions []string
+	for _, lang := range []string{"C", "CXX"} {
+		definitions = append(definitions, "CMAKE_"+lang+"_FLAGS_INIT:STRING="+flags.compile)
+	}
+	if flags.link != "" {
+		for _, kind := range []string{"EXE", "SHARED", "MODULE"} {
+			definitions = append(definitions, "CMAKE_"+kind+"_LINKER_FLAGS_INIT:STRING="+flags.link)
+		}
+	}
+	return definitions
+}
+
+// splitVariantSuffix strips a trailing -<variant> from a build directory name:
+// "Ninja-Debug-asan" → ("Ninja-Debug", "asan"), "Xcode" → ("Xcode", "default")
+func splitVariantSuffix(dirName string) (string, string) {
+	if idx := strings.LastIndex(dirName, buildDirConfigSeparator); idx != -1 {
+		suffix := dirName[idx+len(buildDirConfigSeparator):]
+		if suffix != VariantDefault && IsVariant(suffix) {
+			return dirName[:idx], suffix
+		}
+	}
+	return dirName, VariantDefault
+}
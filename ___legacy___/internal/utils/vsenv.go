@@ -0,0 +1,13 @@
+package utils
+
+// CaptureVSEnvironment locates vcvarsall.bat and captures the environment it produces.
+// Returns nil when Visual Studio is unavailable — callers fall back to the system PATH.
+func CaptureVSEnvironment() []string {
+	vcVarsAllPath, vsErr := FindVCVarsAll()
+	if vsErr != nil {
+		return nil
+	}
+	// VS env capture failure is non-fatal: build operations fall back to system PATH
+	capturedVSEnv, _ := CaptureVSEnv(vcVarsAllPath)
+	return capturedVSEnv
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jrengmusic/cake/internal"
+)
+
+// SourceFingerprint hashes the path, size and modification time of every file under root,
+// skipping Builds/, hidden entries, the ignore patterns and the buildDirs (absolute build
+// trees, which a preset's binaryDir may put anywhere in the sources). Any edit, addition,
+// removal or rename changes it. Polling a fingerprint keeps cake free of a file notification
+// dependency and behaves the same on every platform.
+func SourceFingerprint(root string, ignore []string, buildDirs []string) (uint64, error) {
+	hash := fnv.New64a()
+	var stamp [16]byte
+
+	skipped := make(map[string]bool, len(buildDirs))
+	for _, dir := range buildDirs {
+		if dir != "" {
+			skipped[filepath.Clean(dir)] = true
+		}
+	}
+
+	err := filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files removed mid-walk are picked up by the next poll
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		if entry.IsDir() && skipped[filepath.Clean(p)] {
+			// A build writing objects must not look like a source change
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		if IsWatchIgnored(filepath.ToSlash(rel), entry.IsDir(), ignore) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		hash.Write([]byte(rel))
+		binary.LittleEndian.PutUint64(stamp[:8], uint64(info.Size()))
+		binary.LittleEndian.PutUint64(stamp[8:], uint64(info.ModTime().UnixNano()))
+		hash.Write(stamp[:])
+		return nil
+	})
+	return hash.Sum64(), err
+}
+
+// IsWatchIgnored reports whether rel (slash-separated, relative to the project root) is
+// left out of watching. Builds/ and hidden entries always are. A pattern ending in "/"
+// only matches directories; a pattern containing "/" matches the whole relative path,
+// any other pattern the base name ("*.md", "docs/", "third_party/*").
+func IsWatchIgnored(rel string, isDir bool, ignore []string) bool {
+	base := path.Base(rel)
+	if rel == internal.BuildsDirName || strings.HasPrefix(base, ".") {
+		return true
+	}
+	for _, pattern := range ignore {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if pattern == "" || (dirOnly && !isDir) {
+			continue
+		}
+		subject := base
+		if strings.Contains(pattern, "/") {
+			subject = rel
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+		if matched, _ := path.Match(pattern, subject); matched {
+			return true
+		}
+	}
+	return false
+}